@@ -9,10 +9,10 @@ func NewCmdAutoScalingAutoScalingGroup() *cobra.Command {
 		Use:   "asg",
 		Short: "Auto Scaling Group",
 		Run: func(cmd *cobra.Command, args []string) {
-			groups, err := c.GetAutoScalingGroups()
+			groups, err := c.GetAutoScalingGroupsWithContext(ctx)
 
 			handleError(err)
-			handleError(groups.WriteHCL(w))
+			handleError(writeHCL(groups))
 
 		},
 	}
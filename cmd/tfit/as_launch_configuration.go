@@ -9,10 +9,10 @@ func NewCmdAutoScalingLaunchConfiguration() *cobra.Command {
 		Use:   "lc",
 		Short: "Launch Configuration",
 		Run: func(cmd *cobra.Command, args []string) {
-			launchConfigs, err := c.GetLaunchConfigurations()
+			launchConfigs, err := c.GetLaunchConfigurationsWithContext(ctx)
 
 			handleError(err)
-			handleError(launchConfigs.WriteHCL(w))
+			handleError(writeHCL(launchConfigs))
 		},
 	}
 
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
+	"github.com/spf13/cobra"
+)
+
+var auditFormat, auditMinSeverity, auditFailOn string
+
+func NewCmdAudit() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit the resources for security issues",
+	}
+
+	cmd.PersistentFlags().StringVar(&auditFormat, "format", "text", "Format of the findings: text, json or sarif")
+	cmd.PersistentFlags().StringVar(&auditMinSeverity, "min-severity", "low", "Only report findings at or above this severity: low, medium, high or critical")
+	cmd.PersistentFlags().StringVar(&auditFailOn, "fail-on", "", "Exit with status 2 when a finding is at or above this severity")
+
+	cmd.AddCommand(NewCmdAuditSecurityGroups())
+	cmd.AddCommand(NewCmdAuditS3())
+	cmd.AddCommand(NewCmdAuditIAM())
+	cmd.AddCommand(NewCmdAuditTags())
+
+	return cmd
+}
+
+// writeAuditFindings writes the findings according to the audit flags
+func writeAuditFindings(findings tfit.Findings) {
+	writeFindings(findings, auditFormat, auditMinSeverity, auditFailOn)
+}
+
+// writeFindings writes the findings at or above minSeverity in the given
+// format and exits with status 2 when one is at or above failOn (if set)
+func writeFindings(findings tfit.Findings, format, minSeverity, failOn string) {
+	min, err := tfit.ParseSeverity(minSeverity)
+	handleError(err)
+	findings = findings.Filter(min)
+
+	switch format {
+	case "text":
+		handleError(findings.WriteText(w))
+	case "json":
+		handleError(findings.WriteJSON(w))
+	case "sarif":
+		handleError(findings.WriteSARIF(w))
+	default:
+		handleError(fmt.Errorf("Unknown format: %s", format))
+	}
+
+	if len(failOn) > 0 {
+		severity, err := tfit.ParseSeverity(failOn)
+		handleError(err)
+		if len(findings.Filter(severity)) > 0 {
+			reportRun()
+			os.Exit(2)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdAuditIAM() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "iam",
+		Short: "Admin-equivalent grants, iam:PassRole on *, permissive trust policies & unused policies",
+		Run: func(cmd *cobra.Command, args []string) {
+			findings, err := c.AuditIAMWithContext(ctx)
+			handleError(err)
+			writeAuditFindings(findings)
+		},
+	}
+
+	return cmd
+}
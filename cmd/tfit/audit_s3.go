@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdAuditS3() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "s3",
+		Short: "Unencrypted, unversioned & unlogged buckets, public policies and wildcard CORS origins",
+		Run: func(cmd *cobra.Command, args []string) {
+			findings, err := c.AuditBucketsWithContext(ctx)
+			handleError(err)
+			writeAuditFindings(findings)
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdAuditSecurityGroups() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secgroups",
+		Short: "World-open sensitive ports, broad port ranges, unused groups & cross-account sources",
+		Run: func(cmd *cobra.Command, args []string) {
+			findings, err := c.AuditSecurityGroupsWithContext(ctx)
+			handleError(err)
+			writeAuditFindings(findings)
+		},
+	}
+
+	return cmd
+}
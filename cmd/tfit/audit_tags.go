@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdAuditTags() *cobra.Command {
+	var required, types []string
+
+	cmd := &cobra.Command{
+		Use:   "tags",
+		Short: "Resources missing required tags",
+		Run: func(cmd *cobra.Command, args []string) {
+			findings, err := c.AuditTagsWithContext(ctx, required, types...)
+			handleError(err)
+			writeAuditFindings(findings)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&required, "require", nil, "Tags every resource must have, e.g. owner,env,cost-center")
+	cmd.Flags().StringSliceVar(&types, "type", nil, "Only audit these resource types, e.g. aws_instance,aws_vpc (Default to every tagged type)")
+
+	return cmd
+}
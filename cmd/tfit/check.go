@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdCheck() *cobra.Command {
+	var paths []string
+	var builtin bool
+	var format, minSeverity, failOn string
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the resources against declarative rules",
+		Long: `Check the resources against declarative rules
+
+The resources are checked as tfit exports them. When rules apply to
+aws_security_group_rule, the rules of the security groups (but the default
+ones) are checked as aws_security_group_rule, as with --sg-rules separate,
+and are no longer ingress & egress blocks of the groups.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			var rules tfit.CheckRules
+			if builtin || len(paths) == 0 {
+				builtins, err := tfit.ParseCheckRules([]byte(tfit.BuiltinCheckRules), "builtin")
+				handleError(err)
+				rules = append(rules, builtins...)
+			}
+			if len(paths) > 0 {
+				custom, err := tfit.ReadCheckRules(paths...)
+				handleError(err)
+				rules = append(rules, custom...)
+			}
+
+			findings, err := c.CheckResourcesWithContext(ctx, rules)
+			handleError(err)
+			writeFindings(findings, format, minSeverity, failOn)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&paths, "rules", nil, "Rule files or directories of .hcl rule files, can be repeated (Default to the built-in rules)")
+	cmd.Flags().BoolVar(&builtin, "builtin", false, "Also run the built-in rules along with --rules")
+	cmd.Flags().StringVar(&format, "format", "text", "Format of the violations: text, json or sarif")
+	cmd.Flags().StringVar(&minSeverity, "min-severity", "low", "Only report violations at or above this severity: low, medium, high or critical")
+	cmd.Flags().StringVar(&failOn, "fail-on", "low", "Exit with status 2 when a violation is at or above this severity")
+
+	return cmd
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdDiff() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "diff <old snapshot> <new snapshot>",
+		Short: "Report the resources added, removed or changed between two snapshots",
+		Long: `Report the resources added, removed or changed between two snapshots
+taken by 'tfit snapshot'. Exit with status 2 when something changed.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			old, err := tfit.ReadSnapshot(args[0])
+			handleError(err)
+			new, err := tfit.ReadSnapshot(args[1])
+			handleError(err)
+
+			d := tfit.DiffSnapshots(old, new)
+			switch format {
+			case "text":
+				handleError(d.WriteText(w))
+			case "json":
+				handleError(d.WriteJSON(w))
+			default:
+				handleError(fmt.Errorf("Unknown format: %s", format))
+			}
+
+			if d.HasChanges() {
+				os.Exit(2)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "Format of the report: text or json")
+
+	return cmd
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdDrift() *cobra.Command {
+	var config, format string
+	var states, types []string
+
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Compare an existing Terraform configuration with AWS",
+		Long: `Compare the .tf files of a directory with what tfit exports and report
+the resources missing from the configuration, the resources which no longer
+exist in AWS and the literal attributes whose live value differs.
+Exit with status 2 when a drift is found.
+
+With --state, a configured resource is the AWS resource whose id the state
+has for its address (root module only, without count or for_each).
+Otherwise it is matched by its identifying attributes when the configuration
+sets them literally (bucket, name, Name tag, ...), then by address with the
+name tfit would give the AWS resource.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(config) == 0 {
+				handleError(fmt.Errorf("--config is required"))
+			}
+			if format != "text" && format != "json" {
+				handleError(fmt.Errorf("Unknown format: %s", format))
+			}
+
+			configured, err := tfit.ParseConfigDir(config)
+			handleError(err)
+
+			if len(states) > 0 {
+				state, err := tfit.ReadStateFiles(states...)
+				handleError(err)
+				state.ResolveIDs(configured)
+			}
+
+			live, err := c.GetResourcesWithContext(ctx, types...)
+			handleError(err)
+
+			report := tfit.Drift(configured, live, types...)
+			if format == "json" {
+				handleError(report.WriteJSON(w))
+			} else {
+				handleError(report.WriteText(w))
+			}
+
+			if report.HasDrift() {
+				reportRun()
+				os.Exit(2)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&config, "config", "", "Directory of the Terraform configuration (.tf files)")
+	cmd.Flags().StringVar(&format, "format", "text", "Format of the report: text or json")
+	cmd.Flags().StringSliceVar(&states, "state", nil, "Terraform state file telling the AWS id of the configured resources, can be repeated or comma separated")
+	cmd.Flags().StringSliceVar(&types, "type", nil, "Only compare these resource types, e.g. aws_vpc,aws_subnet (Default to all)")
+
+	return cmd
+}
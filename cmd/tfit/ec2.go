@@ -15,6 +15,20 @@ func NewCmdEC2() *cobra.Command {
 	cmd.AddCommand(NewCmdEC2VPCs())
 	cmd.AddCommand(NewCmdEC2Subnets())
 	cmd.AddCommand(NewCmdEC2RouteTables())
+	cmd.AddCommand(NewCmdEC2NetworkACLs())
+	cmd.AddCommand(NewCmdEC2Volumes())
+	cmd.AddCommand(NewCmdEC2InternetGateways())
+	cmd.AddCommand(NewCmdEC2NATGateways())
+	cmd.AddCommand(NewCmdEC2EgressOnlyInternetGateways())
+	cmd.AddCommand(NewCmdEC2EIPs())
+	cmd.AddCommand(NewCmdEC2VPCPeeringConnections())
+	cmd.AddCommand(NewCmdEC2VPCEndpoints())
+	cmd.AddCommand(NewCmdEC2VPCEndpointServices())
+	cmd.AddCommand(NewCmdEC2TransitGateways())
+	cmd.AddCommand(NewCmdEC2VPNGateways())
+	cmd.AddCommand(NewCmdEC2CustomerGateways())
+	cmd.AddCommand(NewCmdEC2VPNConnections())
+	cmd.AddCommand(NewCmdEC2LaunchTemplates())
 
 	return cmd
 }
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2CustomerGateways() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cgw",
+		Short: "Customer Gateways",
+		Run: func(cmd *cobra.Command, args []string) {
+			cgws, err := c.GetCustomerGatewaysWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(cgws))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2EgressOnlyInternetGateways() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eigw",
+		Short: "EC2 Egress Only Internet Gateways",
+		Run: func(cmd *cobra.Command, args []string) {
+			gw, err := c.GetEgressOnlyInternetGatewaysWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(gw))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2EIPs() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eip",
+		Short: "EC2 Elastic IPs",
+		Run: func(cmd *cobra.Command, args []string) {
+			eips, err := c.GetEIPsWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(eips))
+		},
+	}
+
+	return cmd
+}
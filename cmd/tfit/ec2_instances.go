@@ -9,9 +9,9 @@ func NewCmdEC2Instances() *cobra.Command {
 		Use:   "instances",
 		Short: "EC2 Instances",
 		Run: func(cmd *cobra.Command, args []string) {
-			ec2, err := c.GetInstances()
+			ec2, err := c.GetInstancesWithContext(ctx)
 			handleError(err)
-			handleError(ec2.WriteHCL(w))
+			handleError(writeHCL(ec2))
 		},
 	}
 
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2InternetGateways() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "igw",
+		Short: "EC2 Internet Gateways",
+		Run: func(cmd *cobra.Command, args []string) {
+			gw, err := c.GetInternetGatewaysWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(gw))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2LaunchTemplates() *cobra.Command {
+	var version string
+
+	cmd := &cobra.Command{
+		Use:   "launch-templates",
+		Short: "EC2 Launch Templates",
+		Long: `EC2 Launch Templates
+
+A template is exported from its default version, or its latest one with
+'--version latest'. The Auto Scaling groups reference the exported templates.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			versions := map[string]string{
+				"default": tfit.LaunchTemplateVersionDefault,
+				"latest":  tfit.LaunchTemplateVersionLatest,
+			}
+			v, ok := versions[version]
+			if !ok {
+				handleError(fmt.Errorf("Unknown version: %s", version))
+			}
+
+			lts, err := c.GetLaunchTemplatesWithContext(ctx, v)
+			handleError(err)
+			handleError(writeHCL(lts))
+		},
+	}
+
+	cmd.Flags().StringVar(&version, "version", "default", "The version of the templates to export: default or latest")
+
+	return cmd
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2NATGateways() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "natgw",
+		Short: "EC2 NAT Gateways",
+		Run: func(cmd *cobra.Command, args []string) {
+			gw, err := c.GetNATGatewaysWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(gw))
+		},
+	}
+
+	return cmd
+}
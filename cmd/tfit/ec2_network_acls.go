@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2NetworkACLs() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nacl",
+		Short: "VPC Network ACLs",
+		Run: func(cmd *cobra.Command, args []string) {
+			acls, err := c.GetNetworkACLsWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(acls))
+		},
+	}
+
+	return cmd
+}
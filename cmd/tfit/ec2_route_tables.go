@@ -1,19 +1,40 @@
 package main
 
 import (
+	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
 	"github.com/spf13/cobra"
 )
 
 func NewCmdEC2RouteTables() *cobra.Command {
+	var splitRoutes bool
+
 	cmd := &cobra.Command{
 		Use:   "rtb",
 		Short: "VPC Route & Route Table",
+		Long: `VPC Route & Route Table
+
+The original default route table of a VPC is exported as
+aws_default_route_table and the subnets associated with a table as
+aws_route_table_association. AWS only tells which table is the main one:
+when the main table has routes, tags or subnets and exactly one other table
+of the VPC has none, that table is taken to be the original default one
+and the main table is exported as aws_route_table with an
+aws_main_route_table_association.
+With --split-routes the propagating VPN gateways are exported as
+aws_vpn_gateway_route_propagation too.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			rtb, err := c.GetRouteTables()
+			rtb, err := c.GetRouteTablesWithContext(ctx)
 			handleError(err)
-			handleError(rtb.WriteHCL(w))
+
+			cols := []tfit.Collection{rtb, rtb.Associations(), rtb.MainAssociations()}
+			if splitRoutes {
+				cols = append(cols, rtb.SplitRoutes(), rtb.SplitPropagations())
+			}
+			handleError(writeHCL(cols...))
 		},
 	}
 
+	cmd.Flags().BoolVar(&splitRoutes, "split-routes", false, "Export the routes as aws_route & the propagations as aws_vpn_gateway_route_propagation instead of attributes of the tables")
+
 	return cmd
 }
@@ -1,21 +1,42 @@
 package main
 
 import (
+	"fmt"
+
+	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
 	"github.com/spf13/cobra"
 )
 
 func NewCmdEC2SecurityGroups() *cobra.Command {
+	var sgRules string
+
 	cmd := &cobra.Command{
 		Use:   "secgroup",
 		Short: "EC2 Security Groups",
+		Long: `EC2 Security Groups
+
+The default group of a VPC is exported as aws_default_security_group.
+'--sg-rules separate' exports the rules of the other groups as
+aws_security_group_rule, referencing the exported groups.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			AccountId, err := rootCommand.cfg.GetAccountId()
+			if sgRules != "inline" && sgRules != "separate" {
+				handleError(fmt.Errorf("Unknown rules mode: %s", sgRules))
+			}
+
+			AccountId, err := rootCommand.cfg.GetAccountIdWithContext(ctx)
 			handleError(err)
-			sg, err := c.GetSecurityGroups(AccountId)
+			sg, err := c.GetSecurityGroupsWithContext(ctx, AccountId)
 			handleError(err)
-			handleError(sg.WriteHCL(w))
+
+			cols := []tfit.Collection{sg}
+			if sgRules == "separate" {
+				cols = append(cols, sg.SplitRules())
+			}
+			handleError(writeHCL(cols...))
 		},
 	}
 
+	cmd.Flags().StringVar(&sgRules, "sg-rules", "inline", "How to export the rules: inline (ingress & egress blocks) or separate (aws_security_group_rule)")
+
 	return cmd
 }
@@ -9,9 +9,9 @@ func NewCmdEC2Subnets() *cobra.Command {
 		Use:   "subnet",
 		Short: "EC2 Subnet",
 		Run: func(cmd *cobra.Command, args []string) {
-			subnets, err := c.GetSubnets()
+			subnets, err := c.GetSubnetsWithContext(ctx)
 			handleError(err)
-			handleError(subnets.WriteHCL(w))
+			handleError(writeHCL(subnets))
 		},
 	}
 
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2TransitGateways() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tgw",
+		Short: "Transit Gateways, their VPC attachments, route tables & static routes",
+		Long: `Transit Gateways, their VPC attachments, route tables & static routes
+
+Only the transit gateways of the account are exported, not the shared ones.
+The default route table of a transit gateway is created with it: only its
+static routes are exported.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			AccountId, err := rootCommand.cfg.GetAccountIdWithContext(ctx)
+			handleError(err)
+			tgws, err := c.GetTransitGatewaysWithContext(ctx, AccountId)
+			handleError(err)
+			attachments, err := c.GetTransitGatewayVPCAttachmentsWithContext(ctx, AccountId)
+			handleError(err)
+			rtbs, err := c.GetTransitGatewayRouteTablesWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(tgws, attachments, rtbs, rtbs.Routes()))
+		},
+	}
+
+	return cmd
+}
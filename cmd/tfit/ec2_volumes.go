@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2Volumes() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "volumes",
+		Short: "EBS Volumes, their attachments to the instances & the snapshot lifecycle policies",
+		Run: func(cmd *cobra.Command, args []string) {
+			volumes, err := c.GetVolumesWithContext(ctx)
+			handleError(err)
+			policies, err := c.GetLifecyclePoliciesWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(volumes, volumes.Attachments(), policies))
+		},
+	}
+
+	return cmd
+}
@@ -8,10 +8,19 @@ func NewCmdEC2VPCs() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "vpc",
 		Short: "EC2 VPC",
+		Long: `EC2 VPC
+
+The VPCs come with their secondary IPv4 CIDR blocks, the DHCP options
+sets & their associations with the VPCs, and the flow logs of the VPCs,
+the subnets & the network interfaces.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			vpc, err := c.GetVPCs()
+			vpc, err := c.GetVPCsWithContext(ctx)
+			handleError(err)
+			dhcp, err := c.GetDHCPOptionsSetsWithContext(ctx)
+			handleError(err)
+			flowLogs, err := c.GetFlowLogsWithContext(ctx)
 			handleError(err)
-			handleError(vpc.WriteHCL(w))
+			handleError(writeHCL(vpc, vpc.CIDRBlockAssociations(), dhcp, vpc.DHCPOptionsAssociations(), flowLogs))
 		},
 	}
 
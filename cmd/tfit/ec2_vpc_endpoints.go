@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2VPCEndpoints() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vpce",
+		Short: "VPC Endpoints (gateway & interface)",
+		Run: func(cmd *cobra.Command, args []string) {
+			vpce, err := c.GetVPCEndpointsWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(vpce))
+		},
+	}
+
+	return cmd
+}
+
+func NewCmdEC2VPCEndpointServices() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vpce-svc",
+		Short: "VPC Endpoint Services",
+		Run: func(cmd *cobra.Command, args []string) {
+			services, err := c.GetVPCEndpointServicesWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(services))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2VPCPeeringConnections() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pcx",
+		Short: "VPC Peering Connections",
+		Long: `VPC Peering Connections
+
+The connections requested by another account are exported
+as aws_vpc_peering_connection_accepter.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			AccountId, err := rootCommand.cfg.GetAccountIdWithContext(ctx)
+			handleError(err)
+			pcx, err := c.GetVPCPeeringConnectionsWithContext(ctx, AccountId)
+			handleError(err)
+			handleError(writeHCL(pcx))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2VPNConnections() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vpn",
+		Short: "VPN Connections & their static routes",
+		Long: `VPN Connections & their static routes
+
+The tunnel options aren't exported: their pre-shared keys are secrets.
+Terraform generates new ones unless they're added to the configuration.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			vpns, err := c.GetVPNConnectionsWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(vpns, vpns.Routes()))
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEC2VPNGateways() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vgw",
+		Short: "VPN Gateways & their attachments to the VPCs",
+		Long: `VPN Gateways & their attachments to the VPCs
+
+The route propagations are exported with the route tables, see rtb.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			vgws, err := c.GetVPNGatewaysWithContext(ctx)
+			handleError(err)
+			handleError(writeHCL(vgws, vgws.Attachments()))
+		},
+	}
+
+	return cmd
+}
@@ -9,9 +9,9 @@ func NewCmdELB() *cobra.Command {
 		Use:   "elb",
 		Short: "Elastic Load Balancer",
 		Run: func(cmd *cobra.Command, args []string) {
-			elbs, err := c.ListELBs()
+			elbs, err := c.ListELBsWithContext(ctx)
 			handleError(err)
-			handleError(elbs.WriteHCL(w))
+			handleError(writeHCL(elbs))
 		},
 	}
 
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdGraph() *cobra.Command {
+	var format string
+	var types []string
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Export the dependency graph of the resources",
+		Run: func(cmd *cobra.Command, args []string) {
+			g, err := c.GetGraphWithContext(ctx, types...)
+			handleError(err)
+
+			switch format {
+			case "dot":
+				handleError(g.WriteDOT(w))
+			case "json":
+				handleError(g.WriteJSON(w))
+			default:
+				handleError(fmt.Errorf("Unknown format: %s", format))
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "dot", "Format of the graph: dot or json")
+	cmd.Flags().StringSliceVar(&types, "type", nil, "Only graph these resource types, e.g. aws_instance,aws_subnet (Default to all)")
+
+	return cmd
+}
@@ -9,9 +9,9 @@ func NewCmdIAMGroup() *cobra.Command {
 		Use:   "group",
 		Short: "IAM Groups",
 		Run: func(cmd *cobra.Command, args []string) {
-			groups, err := c.ListIAMGroups()
+			groups, err := c.ListIAMGroupsWithContext(ctx)
 			handleError(err)
-			handleError(groups.WriteHCL(w))
+			handleError(writeHCL(groups))
 		},
 	}
 
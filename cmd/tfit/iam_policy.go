@@ -9,9 +9,9 @@ func NewCmdIAMPolicy() *cobra.Command {
 		Use:   "policy",
 		Short: "IAM Policies",
 		Run: func(cmd *cobra.Command, args []string) {
-			polices, err := c.GetPolicies()
+			polices, err := c.GetPoliciesWithContext(ctx)
 			handleError(err)
-			handleError(polices.WriteHCL(w))
+			handleError(writeHCL(polices))
 		},
 	}
 
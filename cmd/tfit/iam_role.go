@@ -9,9 +9,9 @@ func NewCmdIAMRole() *cobra.Command {
 		Use:   "role",
 		Short: "IAM Roles",
 		Run: func(cmd *cobra.Command, args []string) {
-			roles, err := c.ListRoles()
+			roles, err := c.ListRolesWithContext(ctx)
 			handleError(err)
-			handleError(roles.WriteHCL(w))
+			handleError(writeHCL(roles))
 		},
 	}
 
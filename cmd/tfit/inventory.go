@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCmdInventory() *cobra.Command {
+	var format string
+	var types []string
+
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Count the resources per type or list them as CSV or JSON",
+		Run: func(cmd *cobra.Command, args []string) {
+			inv, err := c.GetInventoryWithContext(ctx, types...)
+			handleError(err)
+
+			switch format {
+			case "text":
+				handleError(inv.WriteText(w))
+			case "csv":
+				handleError(inv.WriteCSV(w))
+			case "json":
+				handleError(inv.WriteJSON(w))
+			default:
+				handleError(fmt.Errorf("Unknown format: %s", format))
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "Format of the inventory: text (count per type), csv or json (a row per resource)")
+	cmd.Flags().StringSliceVar(&types, "type", nil, "Only list these resource types, e.g. aws_instance,aws_s3_bucket (Default to all)")
+
+	return cmd
+}
@@ -1,9 +1,16 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
 	"github.com/spf13/cobra"
@@ -17,6 +24,15 @@ type RootCmd struct {
 var c *tfit.AWSClient
 var output string
 var w io.Writer
+var timeout time.Duration
+var ctx context.Context
+var cancel context.CancelFunc = func() {}
+var rateLimits map[string]string
+var logLevel string
+var logFormat string
+var verbose int
+var logger tfit.Logger
+var defaultTags map[string]string
 
 var rootCommand = RootCmd{
 	cobraCommand: &cobra.Command{
@@ -25,10 +41,12 @@ var rootCommand = RootCmd{
 }
 
 func Execute() {
+	defer cancel()
 	if err := rootCommand.cobraCommand.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	reportRun()
 }
 
 func init() {
@@ -51,6 +69,16 @@ func NewRootCmd() *cobra.Command {
 	cmd.PersistentFlags().StringVar(&rootCommand.cfg.Profile, "profile", defaultProfile, "AWS Profile. Overrides AWS_PROFILE environment variable")
 
 	cmd.PersistentFlags().StringVar(&output, "output", "", "The output of HCL (Terraform config) contents (Default to StdOut)")
+	cmd.PersistentFlags().IntVar(&rootCommand.cfg.Concurrency, "concurrency", tfit.DefaultConcurrency, "Number of AWS calls running at the same time")
+	cmd.PersistentFlags().StringToStringVar(&rateLimits, "rate-limit", nil, "Requests per second per AWS service, e.g. ec2=20,route53=5")
+	cmd.PersistentFlags().IntVar(&rootCommand.cfg.MaxRetries, "max-retries", tfit.DefaultMaxRetries, "Maximum number of retries of a throttled or failed AWS call")
+	cmd.PersistentFlags().StringVar(&rootCommand.cfg.Naming, "naming", tfit.NamingNameTag, "How to name the exported resources: id, name-tag or template")
+	cmd.PersistentFlags().StringVar(&rootCommand.cfg.NamingTemplate, "naming-template", "", "Go template used by '--naming template', e.g. '{{ .Tags.env }}-{{ .Name }}'")
+	cmd.PersistentFlags().StringToStringVar(&defaultTags, "default-tags", nil, "Tags added to the exported resources which don't have them, e.g. owner=ops,env=prod")
+	cmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Maximum duration of the whole export, e.g. 5m (Default to no timeout)")
+	cmd.PersistentFlags().StringVar(&logLevel, "log-level", "warn", "Minimum level of the logs written to StdErr: debug, info, warn or error")
+	cmd.PersistentFlags().StringVar(&logFormat, "log-format", tfit.LogFormatText, "Format of the logs: text or json")
+	cmd.PersistentFlags().CountVarP(&verbose, "verbose", "v", "Lower the log level by one step per -v (-v shows the progress, -vv the debug logs)")
 
 	// Sub-commands
 	cmd.AddCommand(NewCmdEC2())
@@ -59,15 +87,32 @@ func NewRootCmd() *cobra.Command {
 	cmd.AddCommand(NewCmdS3())
 	cmd.AddCommand(NewCmdAutoScaling())
 	cmd.AddCommand(NewCmdELB())
+	cmd.AddCommand(NewCmdDrift())
+	cmd.AddCommand(NewCmdUnmanaged())
+	cmd.AddCommand(NewCmdSnapshot())
+	cmd.AddCommand(NewCmdDiff())
+	cmd.AddCommand(NewCmdGraph())
+	cmd.AddCommand(NewCmdAudit())
+	cmd.AddCommand(NewCmdInventory())
+	cmd.AddCommand(NewCmdCheck())
 
 	return cmd
 }
 
 func initConfig() {
 	var err error
+	logger, err = newLogger(logLevel, logFormat, verbose)
+	handleError(err)
+	rootCommand.cfg.Logger = logger
+
+	rootCommand.cfg.RateLimits, err = parseRateLimits(rateLimits)
+	handleError(err)
+
 	c, err = rootCommand.cfg.Client()
 	handleError(err)
 
+	ctx, cancel = newContext(timeout)
+
 	if len(output) == 0 {
 		w = os.Stdout
 	} else {
@@ -76,9 +121,134 @@ func initConfig() {
 	}
 }
 
+func parseRateLimits(src map[string]string) (map[string]float64, error) {
+	limits := make(map[string]float64, len(src))
+	for service, v := range src {
+		rps, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid rate limit for %s: %s", service, err)
+		}
+		limits[service] = rps
+	}
+
+	return limits, nil
+}
+
+func newLogger(level, format string, verbose int) (tfit.Logger, error) {
+	l, err := tfit.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	l -= tfit.Level(verbose)
+	if l < tfit.LevelDebug {
+		l = tfit.LevelDebug
+	}
+
+	return tfit.NewLogger(os.Stderr, l, format)
+}
+
+// newContext returns a context which is cancelled once the timeout
+// (if any) expires or when the process receives SIGINT/SIGTERM
+func newContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	parent, stop := context.WithCancel(context.Background())
+	ctx, cancel := parent, stop
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, timeout)
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigs:
+			stop()
+		case <-ctx.Done():
+		}
+		signal.Stop(sigs)
+	}()
+
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// reportRun logs how many retries each AWS service needed
+// and how much was fetched & rendered during the run
+func reportRun() {
+	if c == nil || logger == nil {
+		return
+	}
+
+	stats := c.RetryStats()
+	services := make([]string, 0, len(stats))
+	for k := range stats {
+		services = append(services, k)
+	}
+	sort.Strings(services)
+
+	for _, s := range services {
+		logger.Log(tfit.LevelWarn, "AWS calls were retried", "service", s, "retries", stats[s].Retries, "throttled", stats[s].Throttles)
+	}
+
+	p := c.Progress()
+	for _, s := range sortedKeys(p.Pages) {
+		logger.Log(tfit.LevelInfo, "pages fetched", "service", s, "pages", p.Pages[s])
+	}
+	for _, t := range sortedKeys(p.Rendered) {
+		logger.Log(tfit.LevelInfo, "resources rendered", "type", t, "total", p.Rendered[t])
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// writeHCL renders the exported resources, with the missing default tags.
+// The collections are separated by a blank line, empty ones are skipped.
+func writeHCL(cols ...tfit.Collection) error {
+	sep := false
+	for _, col := range cols {
+		tfit.AddMissingTags(col, defaultTags)
+
+		buf := bytes.NewBuffer(nil)
+		if err := c.WriteHCL(buf, col); err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+			continue
+		}
+
+		if sep {
+			if _, err := io.WriteString(w, "\n\n"); err != nil {
+				return err
+			}
+		}
+		sep = true
+
+		if _, err := buf.WriteTo(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func handleError(err error) {
 	if err != nil {
-		fmt.Println(err)
+		reportRun()
+		if logger != nil {
+			logger.Log(tfit.LevelError, err.Error())
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
 		os.Exit(1)
 	}
 }
@@ -9,9 +9,9 @@ func NewCmdRoute53ResourceRecordSet() *cobra.Command {
 		Use:   "rrs",
 		Short: "Route53 Resource Record Sets",
 		Run: func(cmd *cobra.Command, args []string) {
-			rrs, err := c.GetAllResourceRecordSets()
+			rrs, err := c.GetAllResourceRecordSetsWithContext(ctx)
 			handleError(err)
-			handleError(rrs.WriteHCL(w))
+			handleError(writeHCL(rrs))
 		},
 	}
 
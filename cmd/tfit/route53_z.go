@@ -9,9 +9,9 @@ func NewCmdRoute53Zones() *cobra.Command {
 		Use:   "zone",
 		Short: "Route53 Hosted Zones",
 		Run: func(cmd *cobra.Command, args []string) {
-			zones, err := c.GetHostZones(5)
+			zones, err := c.GetHostZonesWithContext(ctx)
 			handleError(err)
-			handleError(zones.WriteHCL(w))
+			handleError(writeHCL(zones))
 		},
 	}
 
@@ -9,9 +9,9 @@ func NewCmdS3Buckets() *cobra.Command {
 		Use:   "buckets",
 		Short: "S3 Buckets",
 		Run: func(cmd *cobra.Command, args []string) {
-			buckets, err := c.GetBuckets()
+			buckets, err := c.GetBucketsWithContext(ctx)
 			handleError(err)
-			handleError(buckets.WriteHCL(w))
+			handleError(writeHCL(buckets))
 		},
 	}
 
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmdSnapshot() *cobra.Command {
+	var types []string
+
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save a JSON inventory of the exported resources",
+		Run: func(cmd *cobra.Command, args []string) {
+			s, err := c.TakeSnapshotWithContext(ctx, types...)
+			handleError(err)
+			handleError(s.WriteJSON(w))
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&types, "type", nil, "Only save these resource types, e.g. aws_vpc,aws_subnet (Default to all)")
+
+	return cmd
+}
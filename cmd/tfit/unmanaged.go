@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/d0m0reg00dthing/tfit/pkg/tfit"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdUnmanaged() *cobra.Command {
+	var states, types []string
+	var format string
+	var hcl bool
+
+	cmd := &cobra.Command{
+		Use:   "unmanaged",
+		Short: "List the AWS resources which are in no Terraform state",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(states) == 0 {
+				handleError(fmt.Errorf("--state is required"))
+			}
+
+			state, err := tfit.ReadStateFiles(states...)
+			handleError(err)
+
+			cols, err := c.GetAllWithContext(ctx, types...)
+			handleError(err)
+
+			// The resources are rendered before being compared
+			// with the state, so the default tags go in first
+			if hcl {
+				for _, col := range cols {
+					tfit.AddMissingTags(col, defaultTags)
+				}
+			}
+			live, err := c.CollectionResources(cols...)
+			handleError(err)
+
+			unmanaged := state.Unmanaged(live)
+			if hcl {
+				handleError(writeHCL(unmanaged))
+				return
+			}
+
+			switch format {
+			case "text":
+				for _, t := range unmanaged.Types() {
+					rs := unmanaged.OfType(t)
+					fmt.Fprintf(w, "%s (%d):\n", t, len(rs))
+					for _, r := range rs {
+						fmt.Fprintf(w, "  %s\t%s\n", r.ID, r.Address())
+					}
+				}
+			case "json":
+				type resource struct {
+					ID      string `json:"id"`
+					Address string `json:"address"`
+				}
+				grouped := make(map[string][]resource)
+				for _, r := range unmanaged {
+					grouped[r.Type] = append(grouped[r.Type], resource{r.ID, r.Address()})
+				}
+
+				enc := json.NewEncoder(w)
+				enc.SetIndent("", "  ")
+				handleError(enc.Encode(grouped))
+			default:
+				handleError(fmt.Errorf("Unknown format: %s", format))
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&states, "state", nil, "Terraform state file, can be repeated or comma separated")
+	cmd.Flags().StringSliceVar(&types, "type", nil, "Only look at these resource types, e.g. aws_vpc,aws_subnet (Default to all)")
+	cmd.Flags().StringVar(&format, "format", "text", "Format of the list: text or json")
+	cmd.Flags().BoolVar(&hcl, "hcl", false, "Write the HCL of the unmanaged resources instead of listing them")
+
+	return cmd
+}
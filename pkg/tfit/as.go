@@ -29,7 +29,13 @@ type Group struct {
 
 	PlacementGroup          *string
 	LaunchConfigurationName *string
-	LaunchTemplateName      *string
+
+	// The launch template & its version, referenced when
+	// the template is exported along with the group
+	LaunchTemplateID      *string
+	LaunchTemplateName    *string
+	LaunchTemplateVersion *string
+	LaunchTemplateRef     string
 
 	AvailabilityZones []*string
 	VPCZoneIdentifier []*string
@@ -39,6 +45,8 @@ type Group struct {
 	TargetGroupARNs      []*string
 	EnabledMetrics       []*string
 	ServiceLinkedRoleARN *string
+
+	ResourceName string
 }
 
 type AutoScalingGroups []*Group
@@ -68,9 +76,11 @@ func (g *Group) setEnabledMetrics(src []*autoscaling.EnabledMetric) {
 	}
 }
 
-func (g *Group) setLaunchTemplateName(src *autoscaling.LaunchTemplateSpecification) {
+func (g *Group) setLaunchTemplate(src *autoscaling.LaunchTemplateSpecification) {
 	if src != nil {
+		g.LaunchTemplateID = src.LaunchTemplateId
 		g.LaunchTemplateName = src.LaunchTemplateName
+		g.LaunchTemplateVersion = src.Version
 	}
 }
 
@@ -90,20 +100,60 @@ func (g *Group) set(src *autoscaling.Group) {
 	g.ServiceLinkedRoleARN = src.ServiceLinkedRoleARN
 	g.setTags(src.Tags)
 	g.setEnabledMetrics(src.EnabledMetrics)
-	g.setLaunchTemplateName(src.LaunchTemplate)
+	g.setLaunchTemplate(src.LaunchTemplate)
 	g.parseVPCZoneIdentifier(src.VPCZoneIdentifier)
 }
 
+func (g *Group) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_autoscaling_group",
+		ID:   aws.StringValue(g.Name),
+		Name: aws.StringValue(g.Name),
+		Tags: tagDescriptionValues(g.Tags),
+	}
+}
+
+func (src *AutoScalingGroups) addToGraph(g *Graph) {
+	for _, v := range *src {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "launch_configuration", "aws_launch_configuration", aws.StringValue(v.LaunchConfigurationName))
+		g.addEdge(n, "launch_template", "aws_launch_template", aws.StringValue(v.LaunchTemplateID))
+		g.addEdges(n, "subnet", "aws_subnet", v.VPCZoneIdentifier)
+		g.addEdges(n, "target_group", "aws_lb_target_group", v.TargetGroupARNs)
+	}
+}
+
+func (src *AutoScalingGroups) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *src {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (src *AutoScalingGroups) addMissingTags(tags map[string]string) {
+	for _, v := range *src {
+		v.Tags = addMissingTagDescriptions(v.Tags, tags)
+	}
+}
+
 // GetAutoScalingGroups craps a list of autoscaling group
 // and placing it into a slice of 'AutoScalingGroups'
 func (c *AWSClient) GetAutoScalingGroups() (*AutoScalingGroups, error) {
+	return c.GetAutoScalingGroupsWithContext(aws.BackgroundContext())
+}
+
+// GetAutoScalingGroupsWithContext is the same as GetAutoScalingGroups with
+// the addition of the ability to pass a context for cancellation
+func (c *AWSClient) GetAutoScalingGroupsWithContext(ctx aws.Context) (*AutoScalingGroups, error) {
 	var res AutoScalingGroups
 	options := &autoscaling.DescribeAutoScalingGroupsInput{
 		MaxRecords: aws.Int64(100),
 	}
 
 	for {
-		groups, err := c.asconn.DescribeAutoScalingGroups(options)
+		groups, err := c.asconn.DescribeAutoScalingGroupsWithContext(ctx, options)
 		if err != nil {
 			return nil, err
 		}
@@ -111,6 +161,8 @@ func (c *AWSClient) GetAutoScalingGroups() (*AutoScalingGroups, error) {
 		for _, v := range groups.AutoScalingGroups {
 			tmp := &Group{}
 			tmp.set(v)
+			tmp.ResourceName = c.namer.Name(tmp.nameInfo())
+			tmp.LaunchTemplateRef = c.namer.reference("aws_launch_template", tmp.LaunchTemplateID)
 			res = append(res, tmp)
 		}
 
@@ -135,7 +187,7 @@ func (src *AutoScalingGroups) WriteHCL(w io.Writer) error {
 	tmpl := `
     {{- if .}}
     {{- range .}}
-    resource "aws_autoscaling_group" "{{ .Name }}" {
+    resource "aws_autoscaling_group" "{{ .ResourceName }}" {
       name = "{{ .Name }}"
       min_size = {{ .MinSize }}
       max_size = {{ .MaxSize }}
@@ -157,8 +209,30 @@ func (src *AutoScalingGroups) WriteHCL(w io.Writer) error {
       {{- if .LaunchConfigurationName }}
       launch_configuration = "{{ .LaunchConfigurationName }}"
       {{- end }}
-      {{- if .LaunchTemplateName}}
-      launch_template = "{{ .LaunchTemplateName }}"
+      {{- if .LaunchTemplateRef }}
+
+      launch_template {
+        id = "{{ .LaunchTemplateRef }}"
+        {{- if .LaunchTemplateVersion }}
+        version = "{{ .LaunchTemplateVersion }}"
+        {{- end }}
+      }
+      {{- else if .LaunchTemplateName }}
+
+      launch_template {
+        name = "{{ .LaunchTemplateName }}"
+        {{- if .LaunchTemplateVersion }}
+        version = "{{ .LaunchTemplateVersion }}"
+        {{- end }}
+      }
+      {{- else if .LaunchTemplateID }}
+
+      launch_template {
+        id = "{{ .LaunchTemplateID }}"
+        {{- if .LaunchTemplateVersion }}
+        version = "{{ .LaunchTemplateVersion }}"
+        {{- end }}
+      }
       {{- end }}
       {{- if .ServiceLinkedRoleARN }}
       service_linked_role_arn = "{{ .ServiceLinkedRoleARN }}"
@@ -202,9 +276,33 @@ func (src *AutoScalingGroups) WriteHCL(w io.Writer) error {
 }
 
 //**************** Launch Configuration ****************
-type LaunchConfigurations []*autoscaling.LaunchConfiguration
+type LaunchConfiguration struct {
+	*autoscaling.LaunchConfiguration
+	ResourceName string
+}
+
+type LaunchConfigurations []*LaunchConfiguration
+
+func (lc *LaunchConfiguration) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_launch_configuration",
+		ID:   aws.StringValue(lc.LaunchConfigurationName),
+		Name: aws.StringValue(lc.LaunchConfigurationName),
+	}
+}
+
+func (src *LaunchConfigurations) addToGraph(g *Graph) {
+	for _, v := range *src {
+		n := g.addNode(v.nameInfo())
+		g.addEdges(n, "security_group", "aws_security_group", v.SecurityGroups)
+	}
+}
 
 func (c *AWSClient) GetLaunchConfigurations() (*LaunchConfigurations, error) {
+	return c.GetLaunchConfigurationsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetLaunchConfigurationsWithContext(ctx aws.Context) (*LaunchConfigurations, error) {
 	var res LaunchConfigurations
 
 	options := &autoscaling.DescribeLaunchConfigurationsInput{
@@ -212,12 +310,16 @@ func (c *AWSClient) GetLaunchConfigurations() (*LaunchConfigurations, error) {
 	}
 
 	for {
-		launchconfigs, err := c.asconn.DescribeLaunchConfigurations(options)
+		launchconfigs, err := c.asconn.DescribeLaunchConfigurationsWithContext(ctx, options)
 		if err != nil {
 			return nil, err
 		}
 
-		res = append(res, launchconfigs.LaunchConfigurations...)
+		for _, v := range launchconfigs.LaunchConfigurations {
+			tmp := &LaunchConfiguration{LaunchConfiguration: v}
+			tmp.ResourceName = c.namer.Name(tmp.nameInfo())
+			res = append(res, tmp)
+		}
 
 		if aws.StringValue(launchconfigs.NextToken) != "" {
 			options.NextToken = launchconfigs.NextToken
@@ -238,7 +340,7 @@ func (src *LaunchConfigurations) WriteHCL(w io.Writer) error {
 	tmpl := `
   {{- if . }}
     {{- range .}}
-    resource "aws_launch_configuration" "{{ .LaunchConfigurationName }}" {
+    resource "aws_launch_configuration" "{{ .ResourceName }}" {
       name = "{{ .LaunchConfigurationName }}"
       image_id = "{{ .ImageId }}"
       instance_type = "{{ .InstanceType }}"
@@ -0,0 +1,249 @@
+package tfit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Severity of an audit finding
+type Severity int
+
+const (
+	SeverityLow Severity = iota
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = []string{"low", "medium", "high", "critical"}
+
+func (s Severity) String() string {
+	if s < SeverityLow || s > SeverityCritical {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+
+	return severityNames[s]
+}
+
+// ParseSeverity parses one of "low", "medium", "high" or "critical"
+func ParseSeverity(s string) (Severity, error) {
+	for i, v := range severityNames {
+		if strings.EqualFold(s, v) {
+			return Severity(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("Unknown severity: %s", s)
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *Severity) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	parsed, err := ParseSeverity(v)
+	if err != nil {
+		return err
+	}
+	*s = parsed
+
+	return nil
+}
+
+// sarifLevel maps the severity to a SARIF result level
+func (s Severity) sarifLevel() string {
+	switch s {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	}
+
+	return "note"
+}
+
+// AuditRule describes what a finding is about
+type AuditRule struct {
+	ID          string
+	Description string
+}
+
+// auditRules are the rules of every audit, keyed by id. They are
+// registered by init functions and only read afterwards.
+var auditRules = map[string]AuditRule{}
+
+func registerAuditRules(rules ...AuditRule) {
+	for _, r := range rules {
+		auditRules[r.ID] = r
+	}
+}
+
+// Finding is a problem found by an audit on a resource
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Type     string   `json:"type"`
+	ID       string   `json:"id"`
+	Address  string   `json:"address"`
+	Message  string   `json:"message"`
+
+	// HCL fixing the problem, when the audit knows it
+	Remediation string `json:"remediation,omitempty"`
+
+	// Rule of a check, which isn't one of the audit rules
+	checkRule *AuditRule
+}
+
+// ruleDescription describes the rule of the finding
+func (f *Finding) ruleDescription() string {
+	if f.checkRule != nil {
+		return f.checkRule.Description
+	}
+
+	return auditRules[f.Rule].Description
+}
+
+type Findings []*Finding
+
+func newFinding(rule string, severity Severity, info NameInfo, namer *Namer, format string, a ...interface{}) *Finding {
+	return &Finding{
+		Rule:     rule,
+		Severity: severity,
+		Type:     info.Type,
+		ID:       info.ID,
+		Address:  info.Type + "." + namer.Name(info),
+		Message:  fmt.Sprintf(format, a...),
+	}
+}
+
+// Filter returns the findings at or above the given severity
+func (f Findings) Filter(min Severity) Findings {
+	res := Findings{}
+	for _, v := range f {
+		if v.Severity >= min {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// Sort orders the findings by decreasing severity, then address
+func (f Findings) Sort() {
+	sort.SliceStable(f, func(i, j int) bool {
+		if f[i].Severity != f[j].Severity {
+			return f[i].Severity > f[j].Severity
+		}
+		if f[i].Address != f[j].Address {
+			return f[i].Address < f[j].Address
+		}
+		return f[i].Rule < f[j].Rule
+	})
+}
+
+// WriteText writes the findings for humans
+func (f Findings) WriteText(w io.Writer) error {
+	if len(f) == 0 {
+		_, err := io.WriteString(w, "No findings\n")
+		return err
+	}
+
+	var lines []string
+	for _, v := range f {
+		lines = append(lines, fmt.Sprintf("[%s] %s (%s): %s [%s]", strings.ToUpper(v.Severity.String()), v.Address, v.ID, v.Message, v.Rule))
+		if len(v.Remediation) > 0 {
+			for _, l := range strings.Split(strings.TrimRight(v.Remediation, "\n"), "\n") {
+				lines = append(lines, "    "+l)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// WriteJSON writes the findings as a JSON array
+func (f Findings) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log
+func (f Findings) WriteSARIF(w io.Writer) error {
+	type text struct {
+		Text string `json:"text"`
+	}
+	type rule struct {
+		ID               string `json:"id"`
+		ShortDescription text   `json:"shortDescription"`
+	}
+	type logicalLocation struct {
+		Name               string `json:"name"`
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+		Kind               string `json:"kind"`
+	}
+	type location struct {
+		LogicalLocations []logicalLocation `json:"logicalLocations"`
+	}
+	type result struct {
+		RuleID     string                 `json:"ruleId"`
+		Level      string                 `json:"level"`
+		Message    text                   `json:"message"`
+		Locations  []location             `json:"locations"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+
+	rules := []rule{}
+	seen := make(map[string]bool)
+	results := []result{}
+	for _, v := range f {
+		if !seen[v.Rule] {
+			seen[v.Rule] = true
+			rules = append(rules, rule{ID: v.Rule, ShortDescription: text{v.ruleDescription()}})
+		}
+
+		props := map[string]interface{}{"severity": v.Severity.String(), "resourceType": v.Type}
+		if len(v.Remediation) > 0 {
+			props["remediation"] = v.Remediation
+		}
+		results = append(results, result{
+			RuleID:  v.Rule,
+			Level:   v.Severity.sarifLevel(),
+			Message: text{v.Message},
+			Locations: []location{{LogicalLocations: []logicalLocation{{
+				Name:               v.Address,
+				FullyQualifiedName: v.ID,
+				Kind:               "resource",
+			}}}},
+			Properties: props,
+		})
+	}
+
+	sarif := map[string]interface{}{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []interface{}{map[string]interface{}{
+			"tool": map[string]interface{}{
+				"driver": map[string]interface{}{
+					"name":           "tfit",
+					"informationUri": "https://github.com/d0m0reg00dthing/tfit",
+					"rules":          rules,
+				},
+			},
+			"results": results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarif)
+}
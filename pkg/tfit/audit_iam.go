@@ -0,0 +1,182 @@
+package tfit
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+const (
+	RuleIAMAdminAccess       = "iam-admin-access"
+	RuleIAMPassRoleWildcard  = "iam-passrole-wildcard"
+	RuleIAMTrustAnyPrincipal = "iam-trust-any-principal"
+	RuleIAMTrustNoExternalId = "iam-trust-external-account-without-external-id"
+	RuleIAMUnusedPolicy      = "iam-unused-policy"
+	RuleIAMUnreadablePolicy  = "iam-unreadable-policy"
+)
+
+func init() {
+	registerAuditRules(
+		AuditRule{RuleIAMAdminAccess, "Policy grants every action on every resource"},
+		AuditRule{RuleIAMPassRoleWildcard, "Policy allows passing any role to a service (iam:PassRole on *)"},
+		AuditRule{RuleIAMTrustAnyPrincipal, "Role can be assumed by any AWS principal"},
+		AuditRule{RuleIAMTrustNoExternalId, "Role can be assumed by another account without an sts:ExternalId condition"},
+		AuditRule{RuleIAMUnusedPolicy, "Customer managed policy is not attached to any role, user or group"},
+		AuditRule{RuleIAMUnreadablePolicy, "Policy document can't be parsed"},
+	)
+}
+
+// AuditIAM analyzes the customer managed policies, the trust policies of
+// the roles and the inline policies of the roles, users and groups
+func (c *AWSClient) AuditIAM() (Findings, error) {
+	return c.AuditIAMWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) AuditIAMWithContext(ctx aws.Context) (Findings, error) {
+	accountId, err := c.GetAccountIdWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := c.GetPoliciesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := c.ListRolesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := c.ListUsersWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := c.ListIAMGroupsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inline, err := c.GetInlinePoliciesWithContext(ctx, roles, users, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := Findings{}
+	findings = append(findings, policies.Audit(c.namer)...)
+	findings = append(findings, roles.Audit(c.namer, aws.StringValue(accountId))...)
+	findings = append(findings, inline.Audit(c.namer)...)
+	findings.Sort()
+
+	return findings, nil
+}
+
+// Audit returns the findings of the customer managed policies
+func (p *Policies) Audit(namer *Namer) Findings {
+	findings := Findings{}
+	for _, v := range *p {
+		info := v.nameInfo()
+		findings = append(findings, auditPermissions(namer, info, "", aws.StringValue(v.Document))...)
+
+		if v.AttachmentCount != nil && aws.Int64Value(v.AttachmentCount) == 0 {
+			findings = append(findings, newFinding(RuleIAMUnusedPolicy, SeverityLow, info, namer,
+				"not attached to any role, user or group"))
+		}
+	}
+	findings.Sort()
+
+	return findings
+}
+
+// Audit returns the findings of the trust policies of the roles.
+// Principals of accountId are trusted.
+func (r *Roles) Audit(namer *Namer, accountId string) Findings {
+	findings := Findings{}
+	for _, v := range *r {
+		info := v.nameInfo()
+
+		doc, err := ParsePolicyDocument(aws.StringValue(v.AssumeRolePolicyDocument))
+		if err != nil {
+			findings = append(findings, newFinding(RuleIAMUnreadablePolicy, SeverityLow, info, namer,
+				"trust policy: %s", err))
+			continue
+		}
+
+		for _, s := range doc.Statements {
+			if !s.AllowsAction("sts:AssumeRole") && !s.AllowsAction("sts:AssumeRoleWithWebIdentity") && !s.AllowsAction("sts:AssumeRoleWithSAML") {
+				continue
+			}
+
+			if s.AnyPrincipal() {
+				severity := SeverityCritical
+				if s.HasCondition() {
+					severity = SeverityHigh
+				}
+				findings = append(findings, newFinding(RuleIAMTrustAnyPrincipal, severity, info, namer,
+					"trust policy allows any AWS principal to assume the role"))
+				continue
+			}
+
+			var external []string
+			for _, account := range s.PrincipalAccounts() {
+				if account != accountId {
+					external = append(external, account)
+				}
+			}
+			if len(external) > 0 && len(s.ConditionValues("sts:ExternalId")) == 0 {
+				findings = append(findings, newFinding(RuleIAMTrustNoExternalId, SeverityHigh, info, namer,
+					"trust policy allows account %s without an sts:ExternalId condition", strings.Join(external, ", ")))
+			}
+		}
+	}
+	findings.Sort()
+
+	return findings
+}
+
+// Audit returns the findings of the inline policies, reported on their owner
+func (ip *InlinePolicies) Audit(namer *Namer) Findings {
+	findings := Findings{}
+	for _, v := range *ip {
+		findings = append(findings, auditPermissions(namer, v.Owner, aws.StringValue(v.PolicyName), aws.StringValue(v.Document))...)
+	}
+	findings.Sort()
+
+	return findings
+}
+
+// auditPermissions reports the dangerous grants of a permissions policy.
+// 'inline' is the name of the policy when it is embedded in 'info'.
+func auditPermissions(namer *Namer, info NameInfo, inline, document string) Findings {
+	prefix := "policy"
+	if len(inline) > 0 {
+		prefix = "inline policy " + inline
+	}
+
+	doc, err := ParsePolicyDocument(document)
+	if err != nil {
+		return Findings{newFinding(RuleIAMUnreadablePolicy, SeverityLow, info, namer, "%s: %s", prefix, err)}
+	}
+
+	var res Findings
+	for _, s := range doc.Statements {
+		if s.Admin() {
+			if len(s.NotAction) > 0 {
+				res = append(res, newFinding(RuleIAMAdminAccess, SeverityCritical, info, namer,
+					"%s grants every action but %s on every resource", prefix, strings.Join(s.NotAction, ", ")))
+				continue
+			}
+			res = append(res, newFinding(RuleIAMAdminAccess, SeverityCritical, info, namer,
+				"%s grants every action on every resource", prefix))
+			continue
+		}
+
+		if s.AllowsAction("iam:PassRole") && s.AnyResource() {
+			res = append(res, newFinding(RuleIAMPassRoleWildcard, SeverityHigh, info, namer,
+				"%s allows passing any role (iam:PassRole on *)", prefix))
+		}
+	}
+
+	return res
+}
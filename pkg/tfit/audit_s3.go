@@ -0,0 +1,255 @@
+package tfit
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	RuleS3NoEncryption        = "s3-no-default-encryption"
+	RuleS3VersioningDisabled  = "s3-versioning-disabled"
+	RuleS3MFADeleteDisabled   = "s3-mfa-delete-disabled"
+	RuleS3NoAccessLogging     = "s3-no-access-logging"
+	RuleS3PublicPolicy        = "s3-public-policy"
+	RuleS3CORSWildcardOrigins = "s3-cors-wildcard-origin"
+)
+
+func init() {
+	registerAuditRules(
+		AuditRule{RuleS3NoEncryption, "Bucket has no default server side encryption"},
+		AuditRule{RuleS3VersioningDisabled, "Bucket versioning is not enabled"},
+		AuditRule{RuleS3MFADeleteDisabled, "Bucket versioning doesn't require MFA to delete"},
+		AuditRule{RuleS3NoAccessLogging, "Bucket access logging is not enabled"},
+		AuditRule{RuleS3PublicPolicy, "Bucket policy grants access to any principal"},
+		AuditRule{RuleS3CORSWildcardOrigins, "Bucket CORS rule allows any origin"},
+	)
+}
+
+// Remediation snippets, rendered with the Bucket
+const (
+	s3EncryptionRemediation = `
+resource "aws_s3_bucket" "{{ .ResourceName }}" {
+  server_side_encryption_configuration {
+    rule {
+      apply_server_side_encryption_by_default {
+        sse_algorithm = "AES256"
+      }
+    }
+  }
+}`
+
+	s3VersioningRemediation = `
+resource "aws_s3_bucket" "{{ .ResourceName }}" {
+  versioning {
+    enabled = true
+    {{- if .Versioning }}{{ if .Versioning.Enabled }}
+    mfa_delete = true
+    {{- end }}{{ end }}
+  }
+}`
+
+	s3LoggingRemediation = `
+resource "aws_s3_bucket" "{{ .ResourceName }}" {
+  logging {
+    target_bucket = "{{ .Name }}-logs"
+    target_prefix = "log/"
+  }
+}`
+
+	s3PolicyRemediation = `
+resource "aws_s3_bucket" "{{ .ResourceName }}" {
+  policy = <<POLICY
+{{ .Policy }}
+POLICY
+}`
+
+	s3PublicAccessBlockRemediation = `
+resource "aws_s3_bucket_public_access_block" "{{ .ResourceName }}" {
+  bucket = "{{ .Name }}"
+
+  block_public_policy     = true
+  restrict_public_buckets = true
+}`
+
+	s3CORSRemediation = `
+resource "aws_s3_bucket" "{{ .ResourceName }}" {
+  {{- range .CORSRules }}
+  cors_rule {
+    allowed_origins = [{{ joinstring "," (origins .AllowedOrigins) }}]
+    allowed_methods = [{{ joinstring "," (StringValueSlice .AllowedMethods) }}]
+    {{- if .AllowedHeaders }}
+    allowed_headers = [{{ joinstring "," (StringValueSlice .AllowedHeaders) }}]
+    {{- end }}
+    {{- if .ExposeHeaders }}
+    expose_headers = [{{ joinstring "," (StringValueSlice .ExposeHeaders) }}]
+    {{- end }}
+    {{- if .MaxAgeSeconds }}
+    max_age_seconds = {{ .MaxAgeSeconds }}
+    {{- end }}
+  }
+  {{- end }}
+}`
+)
+
+// remediation renders a HCL snippet
+func remediation(tmpl string, data interface{}) string {
+	funcMap := template.FuncMap{
+		"joinstring":       joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
+		"origins":          restrictOrigins,
+	}
+
+	t, err := template.New("").Funcs(funcMap).Parse(tmpl)
+	if err != nil {
+		return ""
+	}
+
+	src := bytes.NewBuffer(nil)
+	if err := t.Execute(src, data); err != nil {
+		return ""
+	}
+
+	out := bytes.NewBuffer(nil)
+	if err := HCLFmt(src, out); err != nil {
+		return src.String()
+	}
+
+	return out.String()
+}
+
+// restrictOrigins replaces the wildcard origins by a placeholder
+func restrictOrigins(src []*string) []string {
+	var res []string
+	for _, v := range src {
+		if aws.StringValue(v) == "*" {
+			res = append(res, "https://www.example.com")
+			continue
+		}
+		res = append(res, aws.StringValue(v))
+	}
+
+	return res
+}
+
+// AuditBuckets reports the security posture of the buckets
+func (c *AWSClient) AuditBuckets() (Findings, error) {
+	return c.AuditBucketsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) AuditBucketsWithContext(ctx aws.Context) (Findings, error) {
+	buckets, err := c.GetBucketsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets.Audit(c.namer), nil
+}
+
+// Audit returns the findings of the buckets, with their remediation
+func (b *Buckets) Audit(namer *Namer) Findings {
+	findings := Findings{}
+	for _, v := range *b {
+		findings = append(findings, v.audit(namer)...)
+	}
+	findings.Sort()
+
+	return findings
+}
+
+func (b *Bucket) audit(namer *Namer) Findings {
+	var res Findings
+	info := b.nameInfo()
+	add := func(rule string, severity Severity, fix string, format string, a ...interface{}) {
+		f := newFinding(rule, severity, info, namer, format, a...)
+		f.Remediation = fix
+		res = append(res, f)
+	}
+
+	if b.ServerSideEncryptionConfiguration == nil || len(b.ServerSideEncryptionConfiguration.Rules) == 0 {
+		add(RuleS3NoEncryption, SeverityHigh, remediation(s3EncryptionRemediation, b),
+			"objects are not encrypted by default")
+	}
+
+	switch {
+	case b.Versioning == nil || !aws.BoolValue(b.Versioning.Enabled):
+		add(RuleS3VersioningDisabled, SeverityMedium, remediation(s3VersioningRemediation, b),
+			"versioning is not enabled, deleted or overwritten objects can't be recovered")
+	case !aws.BoolValue(b.Versioning.MFADelete):
+		add(RuleS3MFADeleteDisabled, SeverityLow, remediation(s3VersioningRemediation, b),
+			"MFA delete is not enabled")
+	}
+
+	if b.Logging == nil {
+		add(RuleS3NoAccessLogging, SeverityMedium, remediation(s3LoggingRemediation, b),
+			"access logging is not enabled")
+	}
+
+	if b.Policy != nil {
+		if doc, err := ParsePolicyDocument(aws.StringValue(b.Policy)); err == nil {
+			var public []*PolicyStatement
+			severity := SeverityHigh
+			for _, s := range doc.Statements {
+				if s.Allows() && s.AnyPrincipal() {
+					public = append(public, s)
+					if !s.HasCondition() {
+						severity = SeverityCritical
+					}
+				}
+			}
+
+			if len(public) > 0 {
+				fix := remediation(s3PublicAccessBlockRemediation, b)
+				if policy, ok := doc.Without(public...); ok {
+					fix = remediation(s3PolicyRemediation, struct {
+						ResourceName string
+						Policy       string
+					}{b.ResourceName, policy})
+				}
+				add(RuleS3PublicPolicy, severity, fix,
+					"policy grants %s to any principal", joinActions(public))
+			}
+		}
+	}
+
+	for _, r := range b.CORSRules {
+		if hasWildcardOrigin(r) {
+			add(RuleS3CORSWildcardOrigins, SeverityMedium, remediation(s3CORSRemediation, b),
+				"CORS allows %s from any origin", strings.Join(aws.StringValueSlice(r.AllowedMethods), ", "))
+			break
+		}
+	}
+
+	return res
+}
+
+func hasWildcardOrigin(r *s3.CORSRule) bool {
+	for _, o := range r.AllowedOrigins {
+		if aws.StringValue(o) == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinActions(statements []*PolicyStatement) string {
+	var actions []string
+	seen := make(map[string]bool)
+	for _, s := range statements {
+		for _, a := range s.Action {
+			if !seen[a] {
+				seen[a] = true
+				actions = append(actions, a)
+			}
+		}
+	}
+	if len(actions) == 0 {
+		return "access"
+	}
+
+	return strings.Join(actions, ", ")
+}
@@ -0,0 +1,136 @@
+package tfit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// secureBucket returns a bucket without findings
+func secureBucket() *Bucket {
+	return &Bucket{
+		Name: aws.String("logs"),
+		ServerSideEncryptionConfiguration: &s3.ServerSideEncryptionConfiguration{
+			Rules: []*s3.ServerSideEncryptionRule{{}},
+		},
+		Versioning:   &BucketVersioning{Enabled: aws.Bool(true), MFADelete: aws.Bool(true)},
+		Logging:      &s3.LoggingEnabled{TargetBucket: aws.String("logs-logs")},
+		ResourceName: "logs",
+	}
+}
+
+func TestBucketsAudit(t *testing.T) {
+	namer, err := NewNamer(NamingID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		modify func(b *Bucket)
+		want   []string
+	}{
+		{
+			name:   "secure",
+			modify: func(b *Bucket) {},
+			want:   []string{},
+		},
+		{
+			name:   "defaults",
+			modify: func(b *Bucket) { *b = Bucket{Name: b.Name, ResourceName: b.ResourceName} },
+			want:   []string{"high " + RuleS3NoEncryption, "medium " + RuleS3NoAccessLogging, "medium " + RuleS3VersioningDisabled},
+		},
+		{
+			name:   "no MFA delete",
+			modify: func(b *Bucket) { b.Versioning.MFADelete = aws.Bool(false) },
+			want:   []string{"low " + RuleS3MFADeleteDisabled},
+		},
+		{
+			name: "public policy",
+			modify: func(b *Bucket) {
+				b.Policy = aws.String(`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::logs/*"},
+					{"Effect": "Allow", "Principal": {"AWS": "123456789012"}, "Action": "s3:PutObject", "Resource": "arn:aws:s3:::logs/*"}]}`)
+			},
+			want: []string{"critical " + RuleS3PublicPolicy},
+		},
+		{
+			name: "public policy with condition",
+			modify: func(b *Bucket) {
+				b.Policy = aws.String(`{"Statement": {"Effect": "Allow", "Principal": {"AWS": "*"}, "Action": "s3:GetObject",
+					"Resource": "arn:aws:s3:::logs/*", "Condition": {"IpAddress": {"aws:SourceIp": "10.0.0.0/8"}}}}`)
+			},
+			want: []string{"high " + RuleS3PublicPolicy},
+		},
+		{
+			name: "CORS",
+			modify: func(b *Bucket) {
+				b.CORSRules = []*s3.CORSRule{{
+					AllowedOrigins: aws.StringSlice([]string{"https://example.com", "*"}),
+					AllowedMethods: aws.StringSlice([]string{"GET"}),
+					MaxAgeSeconds:  aws.Int64(300),
+				}}
+			},
+			want: []string{"medium " + RuleS3CORSWildcardOrigins},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := secureBucket()
+			c.modify(b)
+
+			got := []string{}
+			for _, f := range (&Buckets{b}).Audit(namer) {
+				got = append(got, f.Severity.String()+" "+f.Rule)
+
+				if f.Address != "aws_s3_bucket.logs" {
+					t.Errorf("%s: got address %s", f.Rule, f.Address)
+				}
+				if len(f.Remediation) == 0 || strings.Contains(f.Remediation, "<nil>") {
+					t.Errorf("%s: got remediation %q", f.Rule, f.Remediation)
+				}
+				if err := HCLFmt(strings.NewReader(f.Remediation), &strings.Builder{}); err != nil {
+					t.Errorf("%s: %s\n%s", f.Rule, err, f.Remediation)
+				}
+			}
+
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestBucketsAuditRemediation(t *testing.T) {
+	namer, err := NewNamer(NamingID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := secureBucket()
+	b.Policy = aws.String(`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "*"},
+		{"Effect": "Allow", "Principal": {"AWS": "123456789012"}, "Action": "s3:PutObject", "Resource": "*"}]}`)
+	b.CORSRules = []*s3.CORSRule{{AllowedOrigins: aws.StringSlice([]string{"*"}), AllowedMethods: aws.StringSlice([]string{"GET"})}}
+
+	fixes := make(map[string]string)
+	for _, f := range (&Buckets{b}).Audit(namer) {
+		fixes[f.Rule] = f.Remediation
+	}
+
+	if fix := fixes[RuleS3PublicPolicy]; strings.Contains(fix, "s3:GetObject") || !strings.Contains(fix, "s3:PutObject") {
+		t.Errorf("policy remediation should only keep the private statement:\n%s", fix)
+	}
+	if fix := fixes[RuleS3CORSWildcardOrigins]; strings.Contains(fix, `"*"`) || !strings.Contains(fix, "https://www.example.com") {
+		t.Errorf("CORS remediation should replace the wildcard origin:\n%s", fix)
+	}
+
+	b.Policy = aws.String(`{"Statement": {"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "*"}}`)
+	for _, f := range (&Buckets{b}).Audit(namer) {
+		if f.Rule == RuleS3PublicPolicy && !strings.Contains(f.Remediation, "aws_s3_bucket_public_access_block") {
+			t.Errorf("got remediation %s, want a public access block", f.Remediation)
+		}
+	}
+}
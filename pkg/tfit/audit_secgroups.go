@@ -0,0 +1,287 @@
+package tfit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// SensitivePorts are the ports which should never be open to the world
+var SensitivePorts = map[int64]string{
+	21:    "FTP",
+	22:    "SSH",
+	23:    "Telnet",
+	135:   "MSRPC",
+	445:   "SMB",
+	1433:  "MSSQL",
+	1521:  "Oracle",
+	2375:  "Docker",
+	3306:  "MySQL",
+	3389:  "RDP",
+	5432:  "PostgreSQL",
+	5601:  "Kibana",
+	6379:  "Redis",
+	9200:  "Elasticsearch",
+	11211: "Memcached",
+	27017: "MongoDB",
+}
+
+// BroadPortRange is the number of ports above which
+// an ingress rule is considered overly broad
+const BroadPortRange = 100
+
+const (
+	RuleSGWorldOpenAllTraffic = "sg-world-open-all-traffic"
+	RuleSGWorldOpenSensitive  = "sg-world-open-sensitive-port"
+	RuleSGBroadPortRange      = "sg-broad-port-range"
+	RuleSGUnused              = "sg-unused"
+	RuleSGCrossAccountSource  = "sg-cross-account-source"
+)
+
+func init() {
+	registerAuditRules(
+		AuditRule{RuleSGWorldOpenAllTraffic, "Security group allows all traffic from the internet"},
+		AuditRule{RuleSGWorldOpenSensitive, "Security group opens a sensitive port (SSH, RDP, databases, ...) to the internet"},
+		AuditRule{RuleSGBroadPortRange, fmt.Sprintf("Security group ingress rule spans more than %d ports", BroadPortRange)},
+		AuditRule{RuleSGUnused, "Security group is not used by any network interface, instance, ELB, launch configuration or launch template"},
+		AuditRule{RuleSGCrossAccountSource, "Security group allows traffic from a security group of another AWS account"},
+	)
+}
+
+// worldSources returns the "anywhere" CIDR blocks of the rule
+func (r *SecurityGroupRule) worldSources() []string {
+	var res []string
+	for _, v := range r.CIDRBlocks {
+		if aws.StringValue(v) == "0.0.0.0/0" {
+			res = append(res, "0.0.0.0/0")
+		}
+	}
+	for _, v := range r.IPv6CIDRBlock {
+		if aws.StringValue(v) == "::/0" {
+			res = append(res, "::/0")
+		}
+	}
+
+	return res
+}
+
+// ports returns the port range of the rule, all ports
+// for "all traffic" and false for ICMP rules
+func (r *SecurityGroupRule) ports() (int64, int64, bool) {
+	switch aws.StringValue(r.IpProtocol) {
+	case "-1":
+		return 0, 65535, true
+	case "icmp", "1", "icmpv6", "58":
+		return 0, 0, false
+	}
+
+	from, to := aws.Int64Value(r.FromPort), aws.Int64Value(r.ToPort)
+	if r.FromPort == nil || from < 0 {
+		from = 0
+	}
+	if r.ToPort == nil || to < 0 {
+		to = 65535
+	}
+
+	return from, to, true
+}
+
+func (r *SecurityGroupRule) portString() string {
+	from, to, hasPorts := r.ports()
+	switch {
+	case aws.StringValue(r.IpProtocol) == "-1":
+		return "all traffic"
+	case !hasPorts:
+		return aws.StringValue(r.IpProtocol)
+	case from == to:
+		return fmt.Sprintf("%s/%d", aws.StringValue(r.IpProtocol), from)
+	}
+
+	return fmt.Sprintf("%s/%d-%d", aws.StringValue(r.IpProtocol), from, to)
+}
+
+// AuditSecurityGroups flags the exposure of the security groups. The
+// network interfaces (of instances, load balancers, RDS databases, Lambda
+// functions, ...), the ELBs, the launch configurations & the launch
+// templates tell which groups are used.
+func (c *AWSClient) AuditSecurityGroups() (Findings, error) {
+	return c.AuditSecurityGroupsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) AuditSecurityGroupsWithContext(ctx aws.Context) (Findings, error) {
+	accountId, err := c.GetAccountIdWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sgs, err := c.GetSecurityGroupsWithContext(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The groups of the instances are in DescribeInstances, their
+	// attributes & volumes aren't needed
+	instances, err := c.describeInstancesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	elbs, err := c.ListELBsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lcs, err := c.GetLaunchConfigurationsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// An Auto Scaling group may launch either version
+	lts := LaunchTemplates{}
+	for _, version := range []string{LaunchTemplateVersionDefault, LaunchTemplateVersionLatest} {
+		v, err := c.GetLaunchTemplatesWithContext(ctx, version)
+		if err != nil {
+			return nil, err
+		}
+		lts = append(lts, *v...)
+	}
+
+	enis, err := c.getNetworkInterfaceGroupsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return sgs.Audit(c.namer, instances, elbs, lcs, &lts, enis), nil
+}
+
+// getNetworkInterfaceGroupsWithContext returns the ids of the
+// security groups attached to the network interfaces
+func (c *AWSClient) getNetworkInterfaceGroupsWithContext(ctx aws.Context) ([]*string, error) {
+	var res []*string
+	opt := &ec2.DescribeNetworkInterfacesInput{}
+	for {
+		out, err := c.ec2conn.DescribeNetworkInterfacesWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing network interfaces: %s", err)
+		}
+
+		for _, v := range out.NetworkInterfaces {
+			for _, g := range v.Groups {
+				res = append(res, g.GroupId)
+			}
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return res, nil
+}
+
+// Audit returns the findings of the security groups. 'enis' are the
+// groups of the network interfaces.
+func (sg *SecurityGroups) Audit(namer *Namer, instances *Instances, elbs *ELBs, lcs *LaunchConfigurations, lts *LaunchTemplates, enis []*string) Findings {
+	used := make(map[string]bool)
+	for _, id := range enis {
+		used[aws.StringValue(id)] = true
+	}
+	for _, v := range *instances {
+		for _, id := range v.SecurityGroups {
+			used[aws.StringValue(id)] = true
+		}
+	}
+	for _, v := range *elbs {
+		for _, id := range v.SecurityGroups {
+			used[aws.StringValue(id)] = true
+		}
+	}
+	for _, v := range *lcs {
+		// EC2-Classic launch configurations use group names
+		for _, id := range v.SecurityGroups {
+			used[aws.StringValue(id)] = true
+		}
+	}
+	for _, v := range *lts {
+		for _, id := range v.SecurityGroupIds {
+			used[aws.StringValue(id)] = true
+		}
+		// Templates for EC2-Classic or a default VPC use group names
+		for _, id := range v.SecurityGroups {
+			used[aws.StringValue(id)] = true
+		}
+		for _, ni := range v.NetworkInterfaces {
+			for _, id := range ni.Groups {
+				used[aws.StringValue(id)] = true
+			}
+		}
+	}
+
+	findings := Findings{}
+	for _, v := range *sg {
+		info := v.nameInfo()
+
+		for _, r := range v.Ingresses {
+			findings = append(findings, auditIngress(namer, info, r)...)
+		}
+
+		if !used[aws.StringValue(v.GroupId)] && !used[aws.StringValue(v.Name)] && aws.StringValue(v.Name) != "default" {
+			findings = append(findings, newFinding(RuleSGUnused, SeverityLow, info, namer,
+				"not used by any network interface, instance, ELB, launch configuration or launch template"))
+		}
+	}
+	findings.Sort()
+
+	return findings
+}
+
+func auditIngress(namer *Namer, info NameInfo, r *SecurityGroupRule) Findings {
+	var res Findings
+
+	for _, src := range r.SourceSecurityGroups {
+		if s := aws.StringValue(src); strings.Contains(s, "/") {
+			res = append(res, newFinding(RuleSGCrossAccountSource, SeverityMedium, info, namer,
+				"%s allowed from security group %s of account %s", r.portString(), s[strings.Index(s, "/")+1:], s[:strings.Index(s, "/")]))
+		}
+	}
+
+	from, to, hasPorts := r.ports()
+	if !hasPorts {
+		return res
+	}
+
+	world := r.worldSources()
+	if len(world) > 0 && aws.StringValue(r.IpProtocol) == "-1" {
+		return append(res, newFinding(RuleSGWorldOpenAllTraffic, SeverityCritical, info, namer,
+			"all traffic allowed from %s", strings.Join(world, ", ")))
+	}
+
+	if len(world) > 0 {
+		var exposed []string
+		for port := from; port <= to; port++ {
+			if name, ok := SensitivePorts[port]; ok {
+				exposed = append(exposed, fmt.Sprintf("%s (%d)", name, port))
+			}
+		}
+		if len(exposed) > 0 {
+			res = append(res, newFinding(RuleSGWorldOpenSensitive, SeverityHigh, info, namer,
+				"%s allowed from %s exposes %s", r.portString(), strings.Join(world, ", "), strings.Join(exposed, ", ")))
+		}
+	}
+
+	// Rules only allowing other security groups are commonly
+	// wide open (e.g. all traffic between the members of a group)
+	if to-from+1 > BroadPortRange && len(r.CIDRBlocks)+len(r.IPv6CIDRBlock) > 0 {
+		severity := SeverityMedium
+		if len(world) > 0 {
+			severity = SeverityHigh
+		}
+		res = append(res, newFinding(RuleSGBroadPortRange, severity, info, namer,
+			"%s opens %d ports", r.portString(), to-from+1))
+	}
+
+	return res
+}
@@ -0,0 +1,102 @@
+package tfit
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestSecurityGroupsAuditUnused(t *testing.T) {
+	namer, err := NewNamer(NamingID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		instances Instances
+		elbs      ELBs
+		lcs       LaunchConfigurations
+		lts       LaunchTemplates
+		enis      []*string
+		unused    bool
+	}{
+		{name: "unused", unused: true},
+		{name: "instance", instances: Instances{{SecurityGroups: aws.StringSlice([]string{"sg-1"})}}},
+		{name: "elb", elbs: ELBs{{SecurityGroups: aws.StringSlice([]string{"sg-1"})}}},
+		{name: "launch configuration", lcs: LaunchConfigurations{{LaunchConfiguration: &autoscaling.LaunchConfiguration{
+			SecurityGroups: aws.StringSlice([]string{"web"}),
+		}}}},
+		{name: "network interface", enis: aws.StringSlice([]string{"sg-2", "sg-1"})},
+		{name: "launch template", lts: LaunchTemplates{{ResponseLaunchTemplateData: &ec2.ResponseLaunchTemplateData{
+			SecurityGroupIds: aws.StringSlice([]string{"sg-1"}),
+		}}}},
+		{name: "launch template network interface", lts: LaunchTemplates{{ResponseLaunchTemplateData: &ec2.ResponseLaunchTemplateData{
+			NetworkInterfaces: []*ec2.LaunchTemplateInstanceNetworkInterfaceSpecification{{Groups: aws.StringSlice([]string{"sg-1"})}},
+		}}}},
+		{name: "other groups", enis: aws.StringSlice([]string{"sg-2"}), unused: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sgs := SecurityGroups{{GroupId: aws.String("sg-1"), Name: aws.String("web")}}
+			findings := sgs.Audit(namer, &c.instances, &c.elbs, &c.lcs, &c.lts, c.enis)
+
+			unused := false
+			for _, f := range findings {
+				if f.Rule == RuleSGUnused {
+					unused = true
+				}
+			}
+			if unused != c.unused {
+				t.Errorf("got unused %v, want %v", unused, c.unused)
+			}
+		})
+	}
+}
+
+func TestSecurityGroupsAuditDescribeCalls(t *testing.T) {
+	actions := []string{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		action := r.Form.Get("Action")
+		actions = append(actions, action)
+
+		switch {
+		case action == "DescribeNetworkInterfaces" && r.Form.Get("NextToken") == "":
+			fmt.Fprint(w, `<DescribeNetworkInterfacesResponse><networkInterfaceSet><item><groupSet><item><groupId>sg-1</groupId></item></groupSet></item></networkInterfaceSet><nextToken>page-2</nextToken></DescribeNetworkInterfacesResponse>`)
+		case action == "DescribeNetworkInterfaces":
+			fmt.Fprint(w, `<DescribeNetworkInterfacesResponse><networkInterfaceSet><item><groupSet><item><groupId>sg-2</groupId></item></groupSet></item></networkInterfaceSet></DescribeNetworkInterfacesResponse>`)
+		case action == "DescribeInstances":
+			fmt.Fprint(w, `<DescribeInstancesResponse><reservationSet><item><instancesSet><item><instanceId>i-1</instanceId><instanceState><code>16</code></instanceState><monitoring><state>disabled</state></monitoring><groupSet><item><groupId>sg-3</groupId></item></groupSet></item></instancesSet></item></reservationSet></DescribeInstancesResponse>`)
+		default:
+			http.Error(w, "unexpected "+action, http.StatusBadRequest)
+		}
+	})
+
+	enis, err := c.getNetworkInterfaceGroupsWithContext(aws.BackgroundContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := aws.StringValueSlice(enis), []string{"sg-1", "sg-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got groups %v, want %v", got, want)
+	}
+
+	instances, err := c.describeInstancesWithContext(aws.BackgroundContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*instances) != 1 || aws.StringValue((*instances)[0].SecurityGroups[0]) != "sg-3" {
+		t.Errorf("got instances %v", instances)
+	}
+
+	want := []string{"DescribeNetworkInterfaces", "DescribeNetworkInterfaces", "DescribeInstances"}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("got calls %v, want %v", actions, want)
+	}
+}
@@ -0,0 +1,82 @@
+package tfit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+const RuleTagsMissing = "tags-missing"
+
+func init() {
+	registerAuditRules(
+		AuditRule{RuleTagsMissing, "Resource is missing required tags"},
+	)
+}
+
+// AuditTags reports the resources missing one of the required tags (or
+// having it empty). It audits every tagged resource type unless types
+// are given.
+func (c *AWSClient) AuditTags(required []string, types ...string) (Findings, error) {
+	return c.AuditTagsWithContext(aws.BackgroundContext(), required, types...)
+}
+
+func (c *AWSClient) AuditTagsWithContext(ctx aws.Context, required []string, types ...string) (Findings, error) {
+	if len(required) == 0 {
+		return nil, fmt.Errorf("At least one required tag is needed")
+	}
+
+	if len(types) == 0 {
+		types = TaggedResourceTypes
+	}
+	for _, t := range types {
+		if !isTaggedResourceType(t) {
+			return nil, fmt.Errorf("Resource type %s has no tags", t)
+		}
+	}
+
+	cols, err := c.GetAllWithContext(ctx, types...)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := Findings{}
+	for _, col := range cols {
+		if v, ok := col.(tagger); ok {
+			findings = append(findings, auditTags(c.namer, v.taggedResources(), required)...)
+		}
+	}
+	findings.Sort()
+
+	return findings, nil
+}
+
+func isTaggedResourceType(t string) bool {
+	for _, v := range TaggedResourceTypes {
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+func auditTags(namer *Namer, resources []NameInfo, required []string) Findings {
+	var res Findings
+	for _, info := range resources {
+		var missing []string
+		for _, k := range required {
+			if len(strings.TrimSpace(info.Tags[k])) == 0 {
+				missing = append(missing, k)
+			}
+		}
+
+		if len(missing) > 0 {
+			res = append(res, newFinding(RuleTagsMissing, SeverityLow, info, namer,
+				"missing tags: %s", strings.Join(missing, ", ")))
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,800 @@
+package tfit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/parser"
+)
+
+// CheckRule is a rule of the check language, written in HCL:
+//
+//	rule "instance-name-tag" {
+//	  resource_type = "aws_instance"
+//	  severity      = "low"
+//	  description   = "Instances have a Name tag"
+//
+//	  when   { attribute = "tags.env"  equals  = "prod" }
+//	  assert { attribute = "tags.Name" present = true }
+//	}
+//
+// The rule applies to the resources of resource_type matching every
+// 'when' condition and is violated when one of its 'assert' conditions
+// doesn't hold.
+type CheckRule struct {
+	ID           string
+	ResourceType string
+	Severity     Severity
+	Description  string
+	When         []*CheckCondition
+	Assert       []*CheckCondition
+
+	// Where the rule is written, file:line
+	Location string
+}
+
+type CheckRules []*CheckRule
+
+// CheckCondition tests an attribute with one operator:
+//
+//	present = true|false
+//	equals = "v"  not_equals = "v"  one_of = ["a", "b"]
+//	matches = "regexp"  not_matches = "regexp"
+//	contains = "v"  not_contains = "v"  (lists)
+//	gte = 1  lte = 65535  (numbers)
+//
+// or tests the repeated blocks of the resource (ingress, cors_rule, ...)
+// with 'any', 'all' or 'none' and nested 'where' conditions:
+//
+//	assert {
+//	  none = "ingress"
+//	  where { attribute = "cidr_blocks" contains = "0.0.0.0/0" }
+//	  where { attribute = "from_port"   lte      = 22 }
+//	  where { attribute = "to_port"     gte      = 22 }
+//	}
+type CheckCondition struct {
+	Attribute string
+	Operator  string
+	Value     interface{}
+
+	// Quantified conditions
+	Quantifier string
+	Block      string
+	Where      []*CheckCondition
+
+	re *regexp.Regexp
+}
+
+var checkOperators = map[string]bool{
+	"present":      true,
+	"equals":       true,
+	"not_equals":   true,
+	"one_of":       true,
+	"matches":      true,
+	"not_matches":  true,
+	"contains":     true,
+	"not_contains": true,
+	"gte":          true,
+	"lte":          true,
+}
+
+var checkQuantifiers = map[string]bool{
+	"any":  true,
+	"all":  true,
+	"none": true,
+}
+
+// BuiltinCheckRules are example rules for instances, buckets and security
+// groups. The rules of the groups are checked whether they're exported
+// inline, in the default groups or as aws_security_group_rule.
+const BuiltinCheckRules = `
+rule "instance-name-tag" {
+  resource_type = "aws_instance"
+  severity      = "low"
+  description   = "Instances have a Name tag"
+
+  assert {
+    attribute = "tags.Name"
+    present   = true
+  }
+}
+
+rule "instance-in-vpc" {
+  resource_type = "aws_instance"
+  severity      = "medium"
+  description   = "Instances run in a VPC subnet, not in EC2-Classic"
+
+  assert {
+    attribute = "subnet_id"
+    present   = true
+  }
+}
+
+rule "instance-current-generation" {
+  resource_type = "aws_instance"
+  severity      = "low"
+  description   = "Instances don't use a previous generation instance type"
+
+  assert {
+    attribute   = "instance_type"
+    not_matches = "^(t1|m1|m2|m3|c1|c3|cc2|cg1|cr1|g2|hi1|hs1|i2|r3)\\."
+  }
+}
+
+rule "bucket-encryption" {
+  resource_type = "aws_s3_bucket"
+  severity      = "high"
+  description   = "Buckets encrypt the objects by default"
+
+  assert {
+    attribute = "server_side_encryption_configuration.rule.apply_server_side_encryption_by_default.sse_algorithm"
+    present   = true
+  }
+}
+
+rule "bucket-versioning" {
+  resource_type = "aws_s3_bucket"
+  severity      = "medium"
+  description   = "Buckets are versioned"
+
+  assert {
+    attribute = "versioning.enabled"
+    equals    = "true"
+  }
+}
+
+rule "bucket-logging" {
+  resource_type = "aws_s3_bucket"
+  severity      = "low"
+  description   = "Buckets log the requests"
+
+  assert {
+    attribute = "logging.target_bucket"
+    present   = true
+  }
+}
+
+rule "sg-no-world-ssh" {
+  resource_type = "aws_security_group"
+  severity      = "high"
+  description   = "Security groups don't open SSH to the internet"
+
+  assert {
+    none = "ingress"
+    where {
+      attribute = "cidr_blocks"
+      contains  = "0.0.0.0/0"
+    }
+    where {
+      attribute = "from_port"
+      lte       = 22
+    }
+    where {
+      attribute = "to_port"
+      gte       = 22
+    }
+  }
+
+  assert {
+    none = "ingress"
+    where {
+      attribute = "ipv6_cidr_blocks"
+      contains  = "::/0"
+    }
+    where {
+      attribute = "from_port"
+      lte       = 22
+    }
+    where {
+      attribute = "to_port"
+      gte       = 22
+    }
+  }
+}
+
+rule "sg-no-world-all-traffic" {
+  resource_type = "aws_security_group"
+  severity      = "critical"
+  description   = "Security groups don't allow all traffic from the internet"
+
+  assert {
+    none = "ingress"
+    where {
+      attribute = "protocol"
+      equals    = "-1"
+    }
+    where {
+      attribute = "cidr_blocks"
+      contains  = "0.0.0.0/0"
+    }
+  }
+
+  assert {
+    none = "ingress"
+    where {
+      attribute = "protocol"
+      equals    = "-1"
+    }
+    where {
+      attribute = "ipv6_cidr_blocks"
+      contains  = "::/0"
+    }
+  }
+}
+
+rule "default-sg-no-world-ssh" {
+  resource_type = "aws_default_security_group"
+  severity      = "high"
+  description   = "Default security groups don't open SSH to the internet"
+
+  assert {
+    none = "ingress"
+    where {
+      attribute = "cidr_blocks"
+      contains  = "0.0.0.0/0"
+    }
+    where {
+      attribute = "from_port"
+      lte       = 22
+    }
+    where {
+      attribute = "to_port"
+      gte       = 22
+    }
+  }
+
+  assert {
+    none = "ingress"
+    where {
+      attribute = "ipv6_cidr_blocks"
+      contains  = "::/0"
+    }
+    where {
+      attribute = "from_port"
+      lte       = 22
+    }
+    where {
+      attribute = "to_port"
+      gte       = 22
+    }
+  }
+}
+
+rule "default-sg-no-world-all-traffic" {
+  resource_type = "aws_default_security_group"
+  severity      = "critical"
+  description   = "Default security groups don't allow all traffic from the internet"
+
+  assert {
+    none = "ingress"
+    where {
+      attribute = "protocol"
+      equals    = "-1"
+    }
+    where {
+      attribute = "cidr_blocks"
+      contains  = "0.0.0.0/0"
+    }
+  }
+
+  assert {
+    none = "ingress"
+    where {
+      attribute = "protocol"
+      equals    = "-1"
+    }
+    where {
+      attribute = "ipv6_cidr_blocks"
+      contains  = "::/0"
+    }
+  }
+}
+
+rule "sg-rule-no-world-ssh" {
+  resource_type = "aws_security_group_rule"
+  severity      = "high"
+  description   = "Security group rules don't open SSH to the internet"
+
+  when {
+    attribute = "type"
+    equals    = "ingress"
+  }
+  when {
+    attribute = "from_port"
+    lte       = 22
+  }
+  when {
+    attribute = "to_port"
+    gte       = 22
+  }
+
+  assert {
+    attribute    = "cidr_blocks"
+    not_contains = "0.0.0.0/0"
+  }
+  assert {
+    attribute    = "ipv6_cidr_blocks"
+    not_contains = "::/0"
+  }
+}
+
+rule "sg-rule-no-world-all-traffic" {
+  resource_type = "aws_security_group_rule"
+  severity      = "critical"
+  description   = "Security group rules don't allow all traffic from the internet"
+
+  when {
+    attribute = "type"
+    equals    = "ingress"
+  }
+  when {
+    attribute = "protocol"
+    equals    = "-1"
+  }
+
+  assert {
+    attribute    = "cidr_blocks"
+    not_contains = "0.0.0.0/0"
+  }
+  assert {
+    attribute    = "ipv6_cidr_blocks"
+    not_contains = "::/0"
+  }
+}
+`
+
+// ParseCheckRules parses the rules of a HCL document
+func ParseCheckRules(src []byte, filename string) (CheckRules, error) {
+	name := filename
+	if len(name) == 0 {
+		name = "rules"
+	}
+
+	f, err := parser.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing %s: %s", name, err)
+	}
+
+	list, ok := f.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, nil
+	}
+
+	var res CheckRules
+	for _, item := range list.Items {
+		location := fmt.Sprintf("%s:%d", name, item.Pos().Line)
+		if len(item.Keys) != 2 || keyName(item.Keys[0]) != "rule" {
+			return nil, fmt.Errorf("Error parsing %s: expected a `rule \"<id>\" { }` block", location)
+		}
+
+		body, ok := item.Val.(*ast.ObjectType)
+		if !ok {
+			return nil, fmt.Errorf("Error parsing %s: expected a `rule \"<id>\" { }` block", location)
+		}
+
+		r, err := parseCheckRule(keyName(item.Keys[1]), body.List)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing rule %s (%s): %s", keyName(item.Keys[1]), location, err)
+		}
+		r.Location = location
+
+		res = append(res, r)
+	}
+
+	return res, nil
+}
+
+// ReadCheckRules reads the rules of .hcl files, directories being read non-recursively
+func ReadCheckRules(paths ...string) (CheckRules, error) {
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading rules: %s", err)
+		}
+
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(p, "*.hcl"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+
+	var res CheckRules
+	seen := make(map[string]string)
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading %s: %s", file, err)
+		}
+
+		rules, err := ParseCheckRules(src, file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rules {
+			if prev, dup := seen[r.ID]; dup {
+				return nil, fmt.Errorf("Rule %s is defined twice: %s and %s", r.ID, prev, r.Location)
+			}
+			seen[r.ID] = r.Location
+		}
+		res = append(res, rules...)
+	}
+
+	return res, nil
+}
+
+func parseCheckRule(id string, list *ast.ObjectList) (*CheckRule, error) {
+	r := &CheckRule{ID: id}
+	for _, item := range list.Items {
+		key := itemKey(item)
+		switch key {
+		case "resource_type", "severity", "description":
+			v, ok := literalValue(item.Val)
+			s, isString := v.(string)
+			if !ok || !isString {
+				return nil, fmt.Errorf("%s must be a string", key)
+			}
+
+			switch key {
+			case "resource_type":
+				r.ResourceType = s
+			case "description":
+				r.Description = s
+			case "severity":
+				severity, err := ParseSeverity(s)
+				if err != nil {
+					return nil, err
+				}
+				r.Severity = severity
+			}
+		case "when", "assert":
+			body, ok := item.Val.(*ast.ObjectType)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a block", key)
+			}
+			c, err := parseCheckCondition(body.List)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", key, err)
+			}
+
+			if key == "when" {
+				r.When = append(r.When, c)
+			} else {
+				r.Assert = append(r.Assert, c)
+			}
+		default:
+			return nil, fmt.Errorf("unknown attribute %q", key)
+		}
+	}
+
+	if !IsResourceType(stateType(r.ResourceType)) {
+		return nil, fmt.Errorf("unknown resource_type %q", r.ResourceType)
+	}
+	if len(r.Assert) == 0 {
+		return nil, fmt.Errorf("at least one assert block is needed")
+	}
+
+	return r, nil
+}
+
+func parseCheckCondition(list *ast.ObjectList) (*CheckCondition, error) {
+	c := &CheckCondition{}
+	for _, item := range list.Items {
+		key := itemKey(item)
+
+		if key == "where" {
+			body, ok := item.Val.(*ast.ObjectType)
+			if !ok {
+				return nil, fmt.Errorf("where must be a block")
+			}
+			w, err := parseCheckCondition(body.List)
+			if err != nil {
+				return nil, err
+			}
+			c.Where = append(c.Where, w)
+			continue
+		}
+
+		v, ok := literalValue(item.Val)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a literal value", key)
+		}
+
+		switch {
+		case key == "attribute":
+			c.Attribute = fmt.Sprint(v)
+		case checkQuantifiers[key]:
+			if len(c.Quantifier) > 0 {
+				return nil, fmt.Errorf("only one of any, all and none can be used")
+			}
+			c.Quantifier, c.Block = key, fmt.Sprint(v)
+		case checkOperators[key]:
+			if len(c.Operator) > 0 {
+				return nil, fmt.Errorf("only one operator can be used, got %s and %s", c.Operator, key)
+			}
+			c.Operator, c.Value = key, v
+		default:
+			return nil, fmt.Errorf("unknown attribute %q", key)
+		}
+	}
+
+	if len(c.Quantifier) > 0 {
+		if len(c.Where) == 0 || len(c.Operator) > 0 || len(c.Attribute) > 0 {
+			return nil, fmt.Errorf("%s needs where blocks and no attribute or operator", c.Quantifier)
+		}
+		return c, nil
+	}
+
+	if len(c.Attribute) == 0 || len(c.Operator) == 0 || len(c.Where) > 0 {
+		return nil, fmt.Errorf("a condition needs an attribute and an operator")
+	}
+
+	switch c.Operator {
+	case "matches", "not_matches":
+		re, err := regexp.Compile(fmt.Sprint(c.Value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp: %s", err)
+		}
+		c.re = re
+	case "gte", "lte":
+		if _, ok := checkNumber(c.Value); !ok {
+			return nil, fmt.Errorf("%s needs a number", c.Operator)
+		}
+	case "present":
+		if _, ok := c.Value.(bool); !ok {
+			return nil, fmt.Errorf("present needs true or false")
+		}
+	case "one_of":
+		if _, ok := c.Value.([]interface{}); !ok {
+			return nil, fmt.Errorf("one_of needs a list")
+		}
+	}
+
+	return c, nil
+}
+
+func checkNumber(v interface{}) (float64, bool) {
+	f, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+	return f, err == nil
+}
+
+// holds evaluates the condition on the attributes of a resource (or of a block)
+func (c *CheckCondition) holds(attrs map[string]interface{}) bool {
+	if len(c.Quantifier) > 0 {
+		return c.holdsForBlocks(checkBlocks(attrs, c.Block))
+	}
+
+	v, found := attrs[c.Attribute]
+	if c.Operator == "present" {
+		return found == c.Value.(bool)
+	}
+	if !found {
+		// Nothing to compare with: only the negative operators hold
+		return c.Operator == "not_equals" || c.Operator == "not_matches" || c.Operator == "not_contains"
+	}
+
+	switch c.Operator {
+	case "equals":
+		return fmt.Sprint(v) == fmt.Sprint(c.Value)
+	case "not_equals":
+		return fmt.Sprint(v) != fmt.Sprint(c.Value)
+	case "one_of":
+		for _, e := range c.Value.([]interface{}) {
+			if fmt.Sprint(v) == fmt.Sprint(e) {
+				return true
+			}
+		}
+		return false
+	case "matches":
+		return c.re.MatchString(fmt.Sprint(v))
+	case "not_matches":
+		return !c.re.MatchString(fmt.Sprint(v))
+	case "contains", "not_contains":
+		contains := false
+		if l, ok := v.([]interface{}); ok {
+			for _, e := range l {
+				if fmt.Sprint(e) == fmt.Sprint(c.Value) {
+					contains = true
+				}
+			}
+		} else {
+			contains = fmt.Sprint(v) == fmt.Sprint(c.Value)
+		}
+		return contains == (c.Operator == "contains")
+	case "gte", "lte":
+		n, ok := checkNumber(v)
+		if !ok {
+			return false
+		}
+		limit, _ := checkNumber(c.Value)
+		if c.Operator == "gte" {
+			return n >= limit
+		}
+		return n <= limit
+	}
+
+	return false
+}
+
+func (c *CheckCondition) holdsForBlocks(blocks []map[string]interface{}) bool {
+	matching := 0
+	for _, b := range blocks {
+		all := true
+		for _, w := range c.Where {
+			if !w.holds(b) {
+				all = false
+				break
+			}
+		}
+		if all {
+			matching++
+		}
+	}
+
+	switch c.Quantifier {
+	case "any":
+		return matching > 0
+	case "all":
+		return matching == len(blocks)
+	}
+
+	return matching == 0
+}
+
+// checkBlocks returns the blocks of a resource: a list for
+// repeated blocks, the flattened attributes of a single one
+func checkBlocks(attrs map[string]interface{}, name string) []map[string]interface{} {
+	var res []map[string]interface{}
+	if l, ok := attrs[name].([]interface{}); ok {
+		for _, e := range l {
+			if m, ok := e.(map[string]interface{}); ok {
+				res = append(res, m)
+			}
+		}
+		return res
+	}
+
+	single := make(map[string]interface{})
+	for k, v := range attrs {
+		if strings.HasPrefix(k, name+".") {
+			single[strings.TrimPrefix(k, name+".")] = v
+		}
+	}
+	if len(single) > 0 {
+		res = append(res, single)
+	}
+
+	return res
+}
+
+var checkQuantifierNames = map[string]string{
+	"any":  "a",
+	"all":  "every",
+	"none": "no",
+}
+
+var checkOperatorNames = map[string]string{
+	"equals":       "=",
+	"not_equals":   "!=",
+	"one_of":       "in",
+	"contains":     "contains",
+	"not_contains": "doesn't contain",
+	"gte":          ">=",
+	"lte":          "<=",
+}
+
+func (c *CheckCondition) String() string {
+	if len(c.Quantifier) > 0 {
+		var where []string
+		for _, w := range c.Where {
+			where = append(where, w.String())
+		}
+		return fmt.Sprintf("%s %s block where %s", checkQuantifierNames[c.Quantifier], c.Block, strings.Join(where, " and "))
+	}
+
+	switch c.Operator {
+	case "present":
+		if c.Value.(bool) {
+			return c.Attribute + " is set"
+		}
+		return c.Attribute + " is not set"
+	case "matches":
+		return fmt.Sprintf("%s matches /%s/", c.Attribute, c.Value)
+	case "not_matches":
+		return fmt.Sprintf("%s doesn't match /%s/", c.Attribute, c.Value)
+	}
+
+	return fmt.Sprintf("%s %s %s", c.Attribute, checkOperatorNames[c.Operator], jsonValue(c.Value))
+}
+
+// Types returns the resource types to fetch for the rules, the default
+// resources (aws_default_security_group, ...) being fetched with their type
+func (rules CheckRules) Types() []string {
+	seen := make(map[string]bool)
+	var res []string
+	for _, r := range rules {
+		t := stateType(r.ResourceType)
+		if !seen[t] {
+			seen[t] = true
+			res = append(res, t)
+		}
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// Check returns a finding per rule violated by a resource
+func (rules CheckRules) Check(resources Resources) Findings {
+	findings := Findings{}
+	for _, rule := range rules {
+		meta := &AuditRule{rule.ID, rule.Description}
+
+	resources:
+		for _, r := range resources.OfType(rule.ResourceType) {
+			for _, c := range rule.When {
+				if !c.holds(r.Attributes) {
+					continue resources
+				}
+			}
+
+			for _, c := range rule.Assert {
+				if c.holds(r.Attributes) {
+					continue
+				}
+
+				msg := "expected " + c.String()
+				if len(rule.Description) > 0 {
+					msg = rule.Description + ": " + msg
+				}
+				findings = append(findings, &Finding{
+					Rule:     rule.ID,
+					Severity: rule.Severity,
+					Type:     r.Type,
+					ID:       r.ID,
+					Address:  r.Address(),
+					Message:  msg,
+
+					checkRule: meta,
+				})
+			}
+		}
+	}
+	findings.Sort()
+
+	return findings
+}
+
+// CheckResources runs the rules against the resources they apply to
+func (c *AWSClient) CheckResources(rules CheckRules) (Findings, error) {
+	return c.CheckResourcesWithContext(aws.BackgroundContext(), rules)
+}
+
+func (c *AWSClient) CheckResourcesWithContext(ctx aws.Context, rules CheckRules) (Findings, error) {
+	if len(rules) == 0 {
+		return Findings{}, nil
+	}
+
+	resources, err := c.GetResourcesWithContext(ctx, rules.Types()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules.Check(resources), nil
+}
@@ -0,0 +1,283 @@
+package tfit
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCheckRules(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{
+			name: "valid",
+			src: `
+rule "name-tag" {
+  resource_type = "aws_instance"
+  severity      = "low"
+  when   { attribute = "tags.env"  equals  = "prod" }
+  assert { attribute = "tags.Name" present = true }
+}`,
+		},
+		{
+			name: "quantifier",
+			src: `
+rule "no-ssh" {
+  resource_type = "aws_security_group"
+  assert {
+    none = "ingress"
+    where { attribute = "from_port" lte = 22 }
+  }
+}`,
+		},
+		{
+			name: "not a rule",
+			src:  `resource "aws_vpc" "main" {}`,
+			err:  "expected a `rule",
+		},
+		{
+			name: "unknown resource type",
+			src:  `rule "r" { resource_type = "aws_foo" assert { attribute = "a" present = true } }`,
+			err:  `unknown resource_type "aws_foo"`,
+		},
+		{
+			name: "no assert",
+			src:  `rule "r" { resource_type = "aws_vpc" }`,
+			err:  "at least one assert block is needed",
+		},
+		{
+			name: "unknown severity",
+			src:  `rule "r" { resource_type = "aws_vpc" severity = "huge" assert { attribute = "a" present = true } }`,
+			err:  "huge",
+		},
+		{
+			name: "two operators",
+			src:  `rule "r" { resource_type = "aws_vpc" assert { attribute = "a" equals = "b" matches = "c" } }`,
+			err:  "only one operator can be used",
+		},
+		{
+			name: "invalid regexp",
+			src:  `rule "r" { resource_type = "aws_vpc" assert { attribute = "a" matches = "(" } }`,
+			err:  "invalid regexp",
+		},
+		{
+			name: "number operator",
+			src:  `rule "r" { resource_type = "aws_vpc" assert { attribute = "a" gte = "many" } }`,
+			err:  "gte needs a number",
+		},
+		{
+			name: "quantifier without where",
+			src:  `rule "r" { resource_type = "aws_security_group" assert { any = "ingress" } }`,
+			err:  "any needs where blocks",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rules, err := ParseCheckRules([]byte(c.src), "rules.hcl")
+			if len(c.err) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(rules) != 1 || rules[0].Location != "rules.hcl:2" {
+					t.Errorf("got %d rules at %v", len(rules), rules)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("got error %v, want %q", err, c.err)
+			}
+		})
+	}
+}
+
+func TestParseBuiltinCheckRules(t *testing.T) {
+	if _, err := ParseCheckRules([]byte(BuiltinCheckRules), ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuiltinSecurityGroupRules(t *testing.T) {
+	rules, err := ParseCheckRules([]byte(BuiltinCheckRules), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources := mustParseResources(t, `
+resource "aws_security_group" "ipv6" {
+  ingress {
+    from_port        = "22"
+    to_port          = "22"
+    protocol         = "tcp"
+    ipv6_cidr_blocks = ["::/0"]
+  }
+}
+
+resource "aws_security_group" "private" {
+  ingress {
+    from_port   = "22"
+    to_port     = "22"
+    protocol    = "tcp"
+    cidr_blocks = ["10.0.0.0/8"]
+  }
+}
+
+resource "aws_default_security_group" "default" {
+  ingress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = ["0.0.0.0/0"]
+  }
+}
+
+resource "aws_security_group_rule" "ssh" {
+  type        = "ingress"
+  from_port   = 0
+  to_port     = 1024
+  protocol    = "tcp"
+  cidr_blocks = ["0.0.0.0/0"]
+}
+
+resource "aws_security_group_rule" "all" {
+  type             = "ingress"
+  from_port        = 0
+  to_port          = 0
+  protocol         = "-1"
+  ipv6_cidr_blocks = ["::/0"]
+}
+
+resource "aws_security_group_rule" "egress" {
+  type        = "egress"
+  from_port   = 0
+  to_port     = 0
+  protocol    = "-1"
+  cidr_blocks = ["0.0.0.0/0"]
+}`)
+
+	got := make(map[string]bool)
+	for _, f := range rules.Check(resources) {
+		got[f.Rule+" "+f.Address] = true
+	}
+	want := map[string]bool{
+		"sg-no-world-ssh aws_security_group.ipv6":                            true,
+		"default-sg-no-world-all-traffic aws_default_security_group.default": true,
+		"sg-rule-no-world-ssh aws_security_group_rule.ssh":                   true,
+		"sg-rule-no-world-all-traffic aws_security_group_rule.all":           true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// The default groups are fetched as aws_security_group
+	for _, v := range rules.Types() {
+		if !IsResourceType(v) {
+			t.Errorf("can't fetch %s", v)
+		}
+	}
+}
+
+func TestCheckConditionHolds(t *testing.T) {
+	attrs := map[string]interface{}{
+		"instance_type": "t2.micro",
+		"port":          "22",
+		"cidr_blocks":   []interface{}{"10.0.0.0/8", "0.0.0.0/0"},
+		"ingress": []interface{}{
+			map[string]interface{}{"from_port": "22", "cidr_blocks": []interface{}{"0.0.0.0/0"}},
+			map[string]interface{}{"from_port": "443", "cidr_blocks": []interface{}{"10.0.0.0/8"}},
+		},
+		"versioning.enabled": "true",
+	}
+
+	cases := []struct {
+		cond string
+		want bool
+	}{
+		{`attribute = "instance_type" present = true`, true},
+		{`attribute = "ami" present = true`, false},
+		{`attribute = "ami" present = false`, true},
+		{`attribute = "instance_type" equals = "t2.micro"`, true},
+		{`attribute = "instance_type" not_equals = "t2.micro"`, false},
+		{`attribute = "ami" equals = "x"`, false},
+		{`attribute = "ami" not_equals = "x"`, true},
+		{`attribute = "instance_type" one_of = ["t2.micro", "t3.micro"]`, true},
+		{`attribute = "instance_type" matches = "^t2\\."`, true},
+		{`attribute = "instance_type" not_matches = "^t2\\."`, false},
+		{`attribute = "cidr_blocks" contains = "0.0.0.0/0"`, true},
+		{`attribute = "cidr_blocks" not_contains = "0.0.0.0/0"`, false},
+		{`attribute = "port" gte = 22`, true},
+		{`attribute = "port" lte = 21`, false},
+		{`attribute = "instance_type" gte = 1`, false},
+		{`any = "ingress" where { attribute = "from_port" equals = "22" }`, true},
+		{`all = "ingress" where { attribute = "from_port" equals = "22" }`, false},
+		{`none = "ingress" where { attribute = "cidr_blocks" contains = "0.0.0.0/0" }`, false},
+		{`none = "egress" where { attribute = "from_port" equals = "22" }`, true},
+		{`all = "versioning" where { attribute = "enabled" equals = "true" }`, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.cond, func(t *testing.T) {
+			src := `rule "r" { resource_type = "aws_security_group" assert { ` + c.cond + ` } }`
+			rules, err := ParseCheckRules([]byte(src), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := rules[0].Assert[0].holds(attrs); got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rules, err := ParseCheckRules([]byte(`
+rule "iam-admin-access" {
+  resource_type = "aws_instance"
+  severity      = "high"
+  description   = "Production instances have a Name tag"
+
+  when   { attribute = "tags.env"  equals  = "prod" }
+  assert { attribute = "tags.Name" present = true }
+}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources := mustParseResources(t, `
+resource "aws_instance" "web" {
+  tags { env = "prod" }
+}
+
+resource "aws_instance" "named" {
+  tags { env = "prod" Name = "named" }
+}
+
+resource "aws_instance" "dev" {
+  tags { env = "dev" }
+}`)
+
+	findings := rules.Check(resources)
+	if len(findings) != 1 || findings[0].Address != "aws_instance.web" || findings[0].Severity != SeverityHigh {
+		t.Fatalf("got %v, want a finding on aws_instance.web", findings)
+	}
+
+	// The rule of the check doesn't replace the built-in one using the same id
+	builtin := auditRules[RuleIAMAdminAccess].Description
+	if builtin == "Production instances have a Name tag" {
+		t.Error("the check overwrote the audit rule")
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := findings.WriteSARIF(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Production instances have a Name tag") {
+		t.Errorf("the SARIF rules don't describe the check: %s", buf.String())
+	}
+}
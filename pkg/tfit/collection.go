@@ -0,0 +1,418 @@
+package tfit
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// Collection is what the getters return: resources which can be
+// rendered as Terraform configuration
+type Collection interface {
+	WriteHCL(w io.Writer) error
+}
+
+type collector struct {
+	resourceType string
+	get          func(r *getRun) (Collection, error)
+}
+
+// getRun is one GetAll: the collectors of the child resources share
+// the result of their parent's getter, which runs once per GetAll
+type getRun struct {
+	ctx    aws.Context
+	c      *AWSClient
+	wanted map[string]bool
+	cache  map[string]interface{}
+}
+
+func (r *getRun) memo(key string, get func() (interface{}, error)) (interface{}, error) {
+	if v, ok := r.cache[key]; ok {
+		return v, nil
+	}
+
+	v, err := get()
+	if err != nil {
+		return nil, err
+	}
+	r.cache[key] = v
+
+	return v, nil
+}
+
+func (r *getRun) accountId() (*string, error) {
+	v, err := r.memo("account", func() (interface{}, error) { return r.c.GetAccountIdWithContext(r.ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return v.(*string), nil
+}
+
+func (r *getRun) vpcs() (*VPCs, error) {
+	v, err := r.memo("aws_vpc", func() (interface{}, error) { return r.c.GetVPCsWithContext(r.ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return v.(*VPCs), nil
+}
+
+func (r *getRun) routeTables() (*RouteTables, error) {
+	v, err := r.memo("aws_route_table", func() (interface{}, error) {
+		rtb, err := r.c.GetRouteTablesWithContext(r.ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.wanted["aws_route"] {
+			r.cache["aws_route"] = rtb.SplitRoutes()
+		}
+		if r.wanted["aws_vpn_gateway_route_propagation"] {
+			r.cache["aws_vpn_gateway_route_propagation"] = rtb.SplitPropagations()
+		}
+		return rtb, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*RouteTables), nil
+}
+
+func (r *getRun) transitGatewayRouteTables() (*TransitGatewayRouteTables, error) {
+	v, err := r.memo("aws_ec2_transit_gateway_route_table", func() (interface{}, error) {
+		return r.c.GetTransitGatewayRouteTablesWithContext(r.ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*TransitGatewayRouteTables), nil
+}
+
+func (r *getRun) vpnGateways() (*VPNGateways, error) {
+	v, err := r.memo("aws_vpn_gateway", func() (interface{}, error) { return r.c.GetVPNGatewaysWithContext(r.ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return v.(*VPNGateways), nil
+}
+
+func (r *getRun) vpnConnections() (*VPNConnections, error) {
+	v, err := r.memo("aws_vpn_connection", func() (interface{}, error) { return r.c.GetVPNConnectionsWithContext(r.ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return v.(*VPNConnections), nil
+}
+
+func (r *getRun) securityGroups() (*SecurityGroups, error) {
+	v, err := r.memo("aws_security_group", func() (interface{}, error) {
+		accountId, err := r.accountId()
+		if err != nil {
+			return nil, err
+		}
+
+		sg, err := r.c.GetSecurityGroupsWithContext(r.ctx, accountId)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.wanted["aws_security_group_rule"] {
+			r.cache["aws_security_group_rule"] = sg.SplitRules()
+		}
+		return sg, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*SecurityGroups), nil
+}
+
+func (r *getRun) volumes() (*Volumes, error) {
+	v, err := r.memo("aws_ebs_volume", func() (interface{}, error) { return r.c.GetVolumesWithContext(r.ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Volumes), nil
+}
+
+// collectors lists every getter, keyed by the Terraform
+// resource type of what it returns
+var collectors = []collector{
+	{"aws_vpc", func(r *getRun) (Collection, error) { return r.vpcs() }},
+	{"aws_vpc_ipv4_cidr_block_association", func(r *getRun) (Collection, error) {
+		vpcs, err := r.vpcs()
+		if err != nil {
+			return nil, err
+		}
+		return vpcs.CIDRBlockAssociations(), nil
+	}},
+	{"aws_vpc_dhcp_options", func(r *getRun) (Collection, error) {
+		return r.c.GetDHCPOptionsSetsWithContext(r.ctx)
+	}},
+	{"aws_vpc_dhcp_options_association", func(r *getRun) (Collection, error) {
+		vpcs, err := r.vpcs()
+		if err != nil {
+			return nil, err
+		}
+		return vpcs.DHCPOptionsAssociations(), nil
+	}},
+	{"aws_flow_log", func(r *getRun) (Collection, error) { return r.c.GetFlowLogsWithContext(r.ctx) }},
+	{"aws_subnet", func(r *getRun) (Collection, error) { return r.c.GetSubnetsWithContext(r.ctx) }},
+	{"aws_route_table", func(r *getRun) (Collection, error) { return r.routeTables() }},
+	{"aws_route_table_association", func(r *getRun) (Collection, error) {
+		rtb, err := r.routeTables()
+		if err != nil {
+			return nil, err
+		}
+		return rtb.Associations(), nil
+	}},
+	{"aws_main_route_table_association", func(r *getRun) (Collection, error) {
+		rtb, err := r.routeTables()
+		if err != nil {
+			return nil, err
+		}
+		return rtb.MainAssociations(), nil
+	}},
+	{"aws_route", func(r *getRun) (Collection, error) {
+		if _, err := r.routeTables(); err != nil {
+			return nil, err
+		}
+		return r.cache["aws_route"].(*Routes), nil
+	}},
+	{"aws_vpn_gateway_route_propagation", func(r *getRun) (Collection, error) {
+		if _, err := r.routeTables(); err != nil {
+			return nil, err
+		}
+		return r.cache["aws_vpn_gateway_route_propagation"].(*VPNGatewayRoutePropagations), nil
+	}},
+	{"aws_network_acl", func(r *getRun) (Collection, error) { return r.c.GetNetworkACLsWithContext(r.ctx) }},
+	{"aws_internet_gateway", func(r *getRun) (Collection, error) {
+		return r.c.GetInternetGatewaysWithContext(r.ctx)
+	}},
+	{"aws_nat_gateway", func(r *getRun) (Collection, error) { return r.c.GetNATGatewaysWithContext(r.ctx) }},
+	{"aws_egress_only_internet_gateway", func(r *getRun) (Collection, error) {
+		return r.c.GetEgressOnlyInternetGatewaysWithContext(r.ctx)
+	}},
+	{"aws_vpc_peering_connection", func(r *getRun) (Collection, error) {
+		accountId, err := r.accountId()
+		if err != nil {
+			return nil, err
+		}
+		return r.c.GetVPCPeeringConnectionsWithContext(r.ctx, accountId)
+	}},
+	{"aws_vpc_endpoint", func(r *getRun) (Collection, error) { return r.c.GetVPCEndpointsWithContext(r.ctx) }},
+	{"aws_vpc_endpoint_service", func(r *getRun) (Collection, error) {
+		return r.c.GetVPCEndpointServicesWithContext(r.ctx)
+	}},
+	{"aws_ec2_transit_gateway", func(r *getRun) (Collection, error) {
+		accountId, err := r.accountId()
+		if err != nil {
+			return nil, err
+		}
+		return r.c.GetTransitGatewaysWithContext(r.ctx, accountId)
+	}},
+	{"aws_ec2_transit_gateway_vpc_attachment", func(r *getRun) (Collection, error) {
+		accountId, err := r.accountId()
+		if err != nil {
+			return nil, err
+		}
+		return r.c.GetTransitGatewayVPCAttachmentsWithContext(r.ctx, accountId)
+	}},
+	{"aws_ec2_transit_gateway_route_table", func(r *getRun) (Collection, error) {
+		return r.transitGatewayRouteTables()
+	}},
+	{"aws_ec2_transit_gateway_route", func(r *getRun) (Collection, error) {
+		rtbs, err := r.transitGatewayRouteTables()
+		if err != nil {
+			return nil, err
+		}
+		return rtbs.Routes(), nil
+	}},
+	{"aws_vpn_gateway", func(r *getRun) (Collection, error) { return r.vpnGateways() }},
+	{"aws_vpn_gateway_attachment", func(r *getRun) (Collection, error) {
+		vgws, err := r.vpnGateways()
+		if err != nil {
+			return nil, err
+		}
+		return vgws.Attachments(), nil
+	}},
+	{"aws_customer_gateway", func(r *getRun) (Collection, error) {
+		return r.c.GetCustomerGatewaysWithContext(r.ctx)
+	}},
+	{"aws_vpn_connection", func(r *getRun) (Collection, error) { return r.vpnConnections() }},
+	{"aws_vpn_connection_route", func(r *getRun) (Collection, error) {
+		vpns, err := r.vpnConnections()
+		if err != nil {
+			return nil, err
+		}
+		return vpns.Routes(), nil
+	}},
+	{"aws_eip", func(r *getRun) (Collection, error) { return r.c.GetEIPsWithContext(r.ctx) }},
+	{"aws_security_group", func(r *getRun) (Collection, error) { return r.securityGroups() }},
+	{"aws_security_group_rule", func(r *getRun) (Collection, error) {
+		if _, err := r.securityGroups(); err != nil {
+			return nil, err
+		}
+		return r.cache["aws_security_group_rule"].(*StandaloneSecurityGroupRules), nil
+	}},
+	{"aws_instance", func(r *getRun) (Collection, error) { return r.c.GetInstancesWithContext(r.ctx) }},
+	{"aws_ebs_volume", func(r *getRun) (Collection, error) { return r.volumes() }},
+	{"aws_volume_attachment", func(r *getRun) (Collection, error) {
+		volumes, err := r.volumes()
+		if err != nil {
+			return nil, err
+		}
+		return volumes.Attachments(), nil
+	}},
+	{"aws_dlm_lifecycle_policy", func(r *getRun) (Collection, error) {
+		return r.c.GetLifecyclePoliciesWithContext(r.ctx)
+	}},
+	{"aws_launch_template", func(r *getRun) (Collection, error) {
+		return r.c.GetLaunchTemplatesWithContext(r.ctx, LaunchTemplateVersionDefault)
+	}},
+	{"aws_elb", func(r *getRun) (Collection, error) { return r.c.ListELBsWithContext(r.ctx) }},
+	{"aws_launch_configuration", func(r *getRun) (Collection, error) {
+		return r.c.GetLaunchConfigurationsWithContext(r.ctx)
+	}},
+	{"aws_autoscaling_group", func(r *getRun) (Collection, error) {
+		return r.c.GetAutoScalingGroupsWithContext(r.ctx)
+	}},
+	{"aws_iam_policy", func(r *getRun) (Collection, error) { return r.c.GetPoliciesWithContext(r.ctx) }},
+	{"aws_iam_role", func(r *getRun) (Collection, error) { return r.c.ListRolesWithContext(r.ctx) }},
+	{"aws_iam_user", func(r *getRun) (Collection, error) { return r.c.ListUsersWithContext(r.ctx) }},
+	{"aws_iam_group", func(r *getRun) (Collection, error) { return r.c.ListIAMGroupsWithContext(r.ctx) }},
+	{"aws_route53_zone", func(r *getRun) (Collection, error) { return r.c.GetHostZonesWithContext(r.ctx) }},
+	{"aws_route53_record", func(r *getRun) (Collection, error) {
+		return r.c.GetAllResourceRecordSetsWithContext(r.ctx)
+	}},
+	{"aws_s3_bucket", func(r *getRun) (Collection, error) { return r.c.GetBucketsWithContext(r.ctx) }},
+}
+
+// splitTypes are the resource types exported as attributes of their
+// parent by default: their getters only run when they're asked for,
+// they're then removed from the parent resources
+var splitTypes = map[string]bool{
+	"aws_route":                         true,
+	"aws_vpn_gateway_route_propagation": true,
+	"aws_security_group_rule":           true,
+}
+
+// ResourceTypes returns the Terraform resource types tfit can export
+func ResourceTypes() []string {
+	res := make([]string, len(collectors))
+	for i, v := range collectors {
+		res[i] = v.resourceType
+	}
+
+	return res
+}
+
+// defaultResourceTypes returns the resource types GetAll
+// exports when it isn't given any
+func defaultResourceTypes() []string {
+	var res []string
+	for _, v := range collectors {
+		if !splitTypes[v.resourceType] {
+			res = append(res, v.resourceType)
+		}
+	}
+
+	return res
+}
+
+// IsResourceType tells whether tfit can export the resource type
+func IsResourceType(t string) bool {
+	for _, v := range collectors {
+		if v.resourceType == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetAll calls every getter, or the getters of the given resource types.
+// The split types (aws_route, ...) are only fetched when they're given.
+func (c *AWSClient) GetAll(types ...string) ([]Collection, error) {
+	return c.GetAllWithContext(aws.BackgroundContext(), types...)
+}
+
+func (c *AWSClient) GetAllWithContext(ctx aws.Context, types ...string) ([]Collection, error) {
+	wanted := make(map[string]bool, len(types))
+	for _, t := range types {
+		if !IsResourceType(t) {
+			return nil, fmt.Errorf("Unsupported resource type: %s (supported: %s)", t, strings.Join(ResourceTypes(), ", "))
+		}
+		wanted[t] = true
+	}
+
+	r := &getRun{ctx: ctx, c: c, wanted: wanted, cache: make(map[string]interface{})}
+	var res []Collection
+	for _, v := range collectors {
+		if len(wanted) > 0 && !wanted[v.resourceType] {
+			continue
+		}
+		if len(wanted) == 0 && splitTypes[v.resourceType] {
+			continue
+		}
+
+		c.logf(LevelInfo, "fetching resources", "type", v.resourceType)
+		col, err := v.get(r)
+		if err != nil {
+			return nil, fmt.Errorf("Error fetching %s: %s", v.resourceType, err)
+		}
+		res = append(res, col)
+	}
+
+	return res, nil
+}
+
+// GetResources returns the resources of every getter (or of the given
+// resource types) as they would be rendered, with their AWS id
+func (c *AWSClient) GetResources(types ...string) (Resources, error) {
+	return c.GetResourcesWithContext(aws.BackgroundContext(), types...)
+}
+
+func (c *AWSClient) GetResourcesWithContext(ctx aws.Context, types ...string) (Resources, error) {
+	cols, err := c.GetAllWithContext(ctx, types...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.CollectionResources(cols...)
+}
+
+// CollectionResources returns the resources of collections returned
+// by the getters as they are rendered, with their AWS id
+func (c *AWSClient) CollectionResources(cols ...Collection) (Resources, error) {
+	var res Resources
+	for _, col := range cols {
+		rs, err := renderResources(col)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range rs {
+			r.ID, _ = c.namer.ID(r.Type, r.Name)
+		}
+		res = append(res, rs...)
+	}
+
+	return res, nil
+}
+
+// GetAccountId returns the id of the AWS account the client is using
+func (c *AWSClient) GetAccountId() (*string, error) {
+	return c.GetAccountIdWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetAccountIdWithContext(ctx aws.Context) (*string, error) {
+	output, err := c.stsconn.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("Error calling GetCallerIdentity: %s", err)
+	}
+
+	return output.Account, nil
+}
@@ -0,0 +1,128 @@
+package tfit
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestGetAllSharesTheParentGetters(t *testing.T) {
+	calls := make(map[string]int)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		calls[r.Form.Get("Action")]++
+		fmt.Fprint(w, `<Response></Response>`)
+	})
+
+	types := []string{
+		"aws_vpc", "aws_vpc_ipv4_cidr_block_association", "aws_vpc_dhcp_options_association",
+		"aws_route_table", "aws_route_table_association", "aws_main_route_table_association",
+		"aws_route", "aws_vpn_gateway_route_propagation",
+	}
+	cols, err := c.GetAllWithContext(aws.BackgroundContext(), types...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cols) != len(types) {
+		t.Errorf("got %d collections for %d types", len(cols), len(types))
+	}
+
+	want := map[string]int{
+		"DescribeVpcs":                     1,
+		"DescribeVpcClassicLink":           1,
+		"DescribeVpcClassicLinkDnsSupport": 1,
+		"DescribeRouteTables":              1,
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("got calls %v, want %v", calls, want)
+	}
+
+	// Each GetAll fetches the resources again
+	if _, err := c.GetAllWithContext(aws.BackgroundContext(), "aws_route_table"); err != nil {
+		t.Fatal(err)
+	}
+	if calls["DescribeRouteTables"] != 2 {
+		t.Errorf("got %d DescribeRouteTables calls", calls["DescribeRouteTables"])
+	}
+}
+
+func TestGetAllSplitsTheChildTypes(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<DescribeRouteTablesResponse><routeTableSet><item>
+<routeTableId>rtb-1</routeTableId><vpcId>vpc-1</vpcId>
+<routeSet>
+<item><destinationCidrBlock>10.0.0.0/16</destinationCidrBlock><gatewayId>local</gatewayId><origin>CreateRouteTable</origin></item>
+<item><destinationCidrBlock>0.0.0.0/0</destinationCidrBlock><gatewayId>igw-1</gatewayId><origin>CreateRoute</origin></item>
+</routeSet>
+<propagatingVgwSet><item><gatewayId>vgw-1</gatewayId></item></propagatingVgwSet>
+</item></routeTableSet></DescribeRouteTablesResponse>`)
+	})
+
+	cols, err := c.GetAllWithContext(aws.BackgroundContext(), "aws_route_table", "aws_route")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rtb := *cols[0].(*RouteTables)
+	if len(rtb[0].Routes) != 0 || len(rtb[0].PropagatingVgws) != 1 {
+		t.Errorf("got %d routes and %d propagations in the table", len(rtb[0].Routes), len(rtb[0].PropagatingVgws))
+	}
+	if routes := *cols[1].(*Routes); len(routes) != 1 {
+		t.Errorf("got %d routes", len(routes))
+	}
+
+	// Alone, the routes are left in the tables they're taken from
+	cols, err = c.GetAllWithContext(aws.BackgroundContext(), "aws_route")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if routes := *cols[0].(*Routes); len(routes) != 1 {
+		t.Errorf("got %d routes", len(routes))
+	}
+
+	for _, v := range defaultResourceTypes() {
+		if v == "aws_route" || v == "aws_vpn_gateway_route_propagation" {
+			t.Errorf("%s is fetched by default", v)
+		}
+	}
+}
+
+func TestGetAllSecurityGroupRules(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("Action") == "GetCallerIdentity" {
+			fmt.Fprint(w, `<GetCallerIdentityResponse><GetCallerIdentityResult><Account>123456789012</Account></GetCallerIdentityResult></GetCallerIdentityResponse>`)
+			return
+		}
+		fmt.Fprint(w, `<DescribeSecurityGroupsResponse><securityGroupInfo><item>
+<groupId>sg-1</groupId><groupName>web</groupName><vpcId>vpc-1</vpcId><ownerId>123456789012</ownerId>
+<ipPermissions><item><ipProtocol>tcp</ipProtocol><fromPort>22</fromPort><toPort>22</toPort>
+<ipRanges><item><cidrIp>0.0.0.0/0</cidrIp></item></ipRanges></item></ipPermissions>
+</item></securityGroupInfo></DescribeSecurityGroupsResponse>`)
+	})
+
+	cols, err := c.GetAllWithContext(aws.BackgroundContext(), "aws_security_group", "aws_security_group_rule")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sg := *cols[0].(*SecurityGroups); len(sg[0].Ingresses) != 0 {
+		t.Errorf("got %d inline rules", len(sg[0].Ingresses))
+	}
+	rs := mustRenderHCL(t, cols[1])
+	if len(rs) != 1 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{"type": "ingress", "from_port": "22", "cidr_blocks": "[0.0.0.0/0]"})
+
+	// The groups fetched alone keep their rules
+	cols, err = c.GetAllWithContext(aws.BackgroundContext(), "aws_security_group")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sg := *cols[0].(*SecurityGroups); len(sg[0].Ingresses) != 1 {
+		t.Errorf("got %d inline rules", len(sg[0].Ingresses))
+	}
+}
@@ -2,16 +2,21 @@ package tfit
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	awsclient "github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 
 	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/dlm"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/route53"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sts"
 )
 
 type Config struct {
@@ -21,6 +26,29 @@ type Config struct {
 	Profile   string
 	Token     string
 	Region    string
+
+	// Concurrency is the number of AWS calls a getter may run
+	// at the same time (Default to DefaultConcurrency)
+	Concurrency int
+
+	// RateLimits overrides DefaultRateLimits, keyed by
+	// SDK service name (ec2, iam, route53, ...)
+	RateLimits map[string]float64
+
+	// Retry policy for throttled & transient errors (Default to
+	// DefaultMaxRetries, DefaultMinRetryDelay and DefaultMaxRetryDelay)
+	MaxRetries    int
+	MinRetryDelay time.Duration
+	MaxRetryDelay time.Duration
+
+	// Naming is the strategy used to name the exported resources,
+	// one of NamingID, NamingNameTag (default) or NamingTemplate
+	Naming         string
+	NamingTemplate string
+
+	// Logger receives the logs of the client (Default to warnings
+	// and errors written to StdErr as key=value pairs)
+	Logger Logger
 }
 
 type AWSClient struct {
@@ -30,13 +58,29 @@ type AWSClient struct {
 	asconn  *autoscaling.AutoScaling
 	s3conn  *s3.S3
 	elbconn *elb.ELB
+	stsconn *sts.STS
+	dlmconn *dlm.DLM
+
+	region   string
+	pool     *workerPool
+	retryer  *retryer
+	namer    *Namer
+	logger   Logger
+	progress *progress
 }
 
 func (c *Config) Client() (*AWSClient, error) {
-	var client AWSClient
+	client := AWSClient{region: c.Region, logger: c.logger(), progress: newProgress()}
 	creds := GetCredentials(c)
 
-	sess, err := session.NewSession(&aws.Config{Credentials: creds})
+	namer, err := NewNamer(c.Naming, c.NamingTemplate)
+	if err != nil {
+		return nil, err
+	}
+	client.namer = namer
+
+	client.retryer = newRetryer(c)
+	sess, err := session.NewSession(request.WithRetryer(&aws.Config{Credentials: creds}, client.retryer))
 	if err != nil {
 		return nil, fmt.Errorf("Error creating AWS session: %s", err)
 	}
@@ -48,6 +92,34 @@ func (c *Config) Client() (*AWSClient, error) {
 	client.ec2conn = ec2.New(sess, aws.NewConfig().WithRegion(c.Region))
 	client.asconn = autoscaling.New(sess, aws.NewConfig().WithRegion(c.Region))
 	client.elbconn = elb.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.stsconn = sts.New(sess, aws.NewConfig().WithRegion(c.Region))
+	client.dlmconn = dlm.New(sess, aws.NewConfig().WithRegion(c.Region))
+
+	client.pool = newWorkerPool(c.Concurrency)
+	for _, svc := range []*awsclient.Client{
+		client.r53conn.Client,
+		client.iamconn.Client,
+		client.s3conn.Client,
+		client.ec2conn.Client,
+		client.asconn.Client,
+		client.elbconn.Client,
+		client.stsconn.Client,
+		client.dlmconn.Client,
+	} {
+		setRateLimit(svc, c.RateLimits)
+		client.trackProgress(svc)
+	}
 
 	return &client, nil
 }
+
+// Namer returns the Namer which named the resources returned by the getters
+func (c *AWSClient) Namer() *Namer {
+	return c.namer
+}
+
+// RetryStats returns how many retries each AWS service (keyed by
+// SDK service name) needed so far
+func (c *AWSClient) RetryStats() map[string]RetryStats {
+	return c.retryer.snapshot()
+}
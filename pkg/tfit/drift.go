@@ -0,0 +1,291 @@
+package tfit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// identityAttributes are the attributes identifying a resource in AWS
+// when the configuration doesn't use the name tfit gives it. The first
+// one is required, the others default to "".
+var identityAttributes = map[string][]string{
+	"aws_vpc":                                {"tags.Name"},
+	"aws_vpc_ipv4_cidr_block_association":    {"vpc_id", "cidr_block"},
+	"aws_vpc_dhcp_options":                   {"tags.Name"},
+	"aws_vpc_dhcp_options_association":       {"vpc_id"},
+	"aws_flow_log":                           {"traffic_type", "vpc_id", "subnet_id", "eni_id"},
+	"aws_subnet":                             {"tags.Name"},
+	"aws_route_table":                        {"tags.Name"},
+	"aws_default_route_table":                {"default_route_table_id"},
+	"aws_route_table_association":            {"subnet_id"},
+	"aws_main_route_table_association":       {"vpc_id"},
+	"aws_route":                              {"route_table_id", "destination_cidr_block", "destination_ipv6_cidr_block"},
+	"aws_network_acl":                        {"tags.Name"},
+	"aws_default_network_acl":                {"default_network_acl_id"},
+	"aws_internet_gateway":                   {"tags.Name"},
+	"aws_nat_gateway":                        {"tags.Name"},
+	"aws_egress_only_internet_gateway":       {"vpc_id"},
+	"aws_vpc_peering_connection":             {"vpc_id", "peer_vpc_id"},
+	"aws_vpc_peering_connection_accepter":    {"vpc_peering_connection_id"},
+	"aws_vpc_endpoint":                       {"vpc_id", "service_name"},
+	"aws_ec2_transit_gateway":                {"tags.Name"},
+	"aws_ec2_transit_gateway_vpc_attachment": {"tags.Name"},
+	"aws_ec2_transit_gateway_route_table":    {"tags.Name"},
+	"aws_ec2_transit_gateway_route":          {"transit_gateway_route_table_id", "destination_cidr_block"},
+	"aws_vpn_gateway":                        {"tags.Name"},
+	"aws_vpn_gateway_attachment":             {"vpn_gateway_id", "vpc_id"},
+	"aws_vpn_gateway_route_propagation":      {"vpn_gateway_id", "route_table_id"},
+	"aws_customer_gateway":                   {"ip_address", "bgp_asn"},
+	"aws_vpn_connection":                     {"tags.Name"},
+	"aws_vpn_connection_route":               {"vpn_connection_id", "destination_cidr_block"},
+	"aws_eip":                                {"tags.Name"},
+	"aws_security_group":                     {"name"},
+	"aws_default_security_group":             {"vpc_id"},
+	"aws_instance":                           {"tags.Name"},
+	"aws_ebs_volume":                         {"tags.Name"},
+	"aws_volume_attachment":                  {"device_name", "volume_id", "instance_id"},
+	"aws_dlm_lifecycle_policy":               {"description"},
+	"aws_elb":                                {"name"},
+	"aws_launch_configuration":               {"name"},
+	"aws_launch_template":                    {"name"},
+	"aws_autoscaling_group":                  {"name"},
+	"aws_iam_policy":                         {"name"},
+	"aws_iam_role":                           {"name"},
+	"aws_iam_user":                           {"name"},
+	"aws_iam_group":                          {"name"},
+	"aws_route53_zone":                       {"name"},
+	"aws_route53_record":                     {"name", "type", "set_identifier"},
+	"aws_s3_bucket":                          {"bucket"},
+}
+
+// identity returns the key matching a configured resource with
+// the live one, or "" when the configuration doesn't set it literally
+func (r *Resource) identity() string {
+	attrs, ok := identityAttributes[r.Type]
+	if !ok {
+		return ""
+	}
+
+	values := make([]string, len(attrs))
+	for i, a := range attrs {
+		v, found := r.Attributes[a]
+		if !found {
+			if i == 0 {
+				return ""
+			}
+			continue
+		}
+
+		values[i] = driftCanonical(r.Type, a, v)
+	}
+
+	return r.Type + "|" + strings.Join(values, "|")
+}
+
+// idKey matches a resource with the one of the same AWS id, whatever
+// the type it's exported as (aws_default_security_group, ...)
+func (r *Resource) idKey() string {
+	return stateType(r.Type) + "|" + strings.ToLower(r.ID)
+}
+
+// driftCanonical is canonicalValue, ignoring the final dot
+// of the fully qualified names returned by Route53
+func driftCanonical(resourceType, attr string, v interface{}) string {
+	s := canonicalValue(v)
+	if strings.HasPrefix(resourceType, "aws_route53_") && attr == "name" {
+		s = strings.TrimSuffix(s, ".")
+	}
+
+	return s
+}
+
+// AttributeDrift is an attribute whose live value differs
+// from the literal value of the configuration
+type AttributeDrift struct {
+	Address    string      `json:"address"`
+	ID         string      `json:"id,omitempty"`
+	Location   string      `json:"location,omitempty"`
+	Attribute  string      `json:"attribute"`
+	Configured interface{} `json:"configured"`
+	Live       interface{} `json:"live"`
+}
+
+// DriftReport compares the Terraform configuration with AWS
+type DriftReport struct {
+	// Resources existing in AWS but not in the configuration
+	Unmanaged Resources `json:"unmanaged"`
+
+	// Resources of the configuration which no longer exist in AWS
+	Missing Resources `json:"missing"`
+
+	Changed []*AttributeDrift `json:"changed"`
+}
+
+// Drift compares the resources of the configuration with the live ones.
+// Resources are matched by AWS id when the configured ones have one (see
+// State.ResolveIDs), else by their identifying attributes (bucket, name,
+// Name tag, ...) then by address. Only the resource types tfit exports
+// are compared and only the literal attributes of the configuration
+// which tfit renders as well. 'types' are the resource types which
+// were fetched (Default to the types GetAll fetches), the default
+// resources (aws_default_security_group, ...) are compared with the
+// type they are fetched with.
+func Drift(config, live Resources, types ...string) *DriftReport {
+	report := &DriftReport{
+		Unmanaged: Resources{},
+		Missing:   Resources{},
+		Changed:   []*AttributeDrift{},
+	}
+
+	if len(types) == 0 {
+		types = defaultResourceTypes()
+	}
+	compared := make(map[string]bool, len(types))
+	for _, t := range types {
+		compared[stateType(t)] = true
+	}
+
+	byID := make(map[string]*Resource)
+	byIdentity := make(map[string]Resources)
+	byAddress := make(map[string]*Resource)
+	for _, r := range live {
+		if len(r.ID) > 0 {
+			byID[r.idKey()] = r
+		}
+		if id := r.identity(); len(id) > 0 {
+			byIdentity[id] = append(byIdentity[id], r)
+		}
+		byAddress[r.Address()] = r
+	}
+
+	matched := make(map[*Resource]bool)
+	for _, cr := range config {
+		if !compared[stateType(cr.Type)] {
+			continue
+		}
+
+		// The state tells which AWS resource a configured one is
+		var lr *Resource
+		if len(cr.ID) > 0 {
+			if r, ok := byID[cr.idKey()]; ok && !matched[r] {
+				lr = r
+			}
+		} else if candidates := byIdentity[cr.identity()]; len(candidates) == 1 && !matched[candidates[0]] {
+			lr = candidates[0]
+		} else if r, ok := byAddress[cr.Address()]; ok && !matched[r] {
+			lr = r
+		}
+
+		if lr == nil {
+			report.Missing = append(report.Missing, cr)
+			continue
+		}
+		matched[lr] = true
+
+		keys := make([]string, 0, len(cr.Attributes))
+		for k := range cr.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			lv, ok := lr.Attributes[k]
+			if !ok {
+				continue
+			}
+			if driftCanonical(cr.Type, k, cr.Attributes[k]) != driftCanonical(cr.Type, k, lv) {
+				report.Changed = append(report.Changed, &AttributeDrift{
+					Address:    cr.Address(),
+					ID:         lr.ID,
+					Location:   cr.Location(),
+					Attribute:  k,
+					Configured: cr.Attributes[k],
+					Live:       lv,
+				})
+			}
+		}
+	}
+
+	for _, r := range live {
+		if !matched[r] {
+			report.Unmanaged = append(report.Unmanaged, r)
+		}
+	}
+
+	return report
+}
+
+// HasDrift tells whether the configuration and AWS differ
+func (d *DriftReport) HasDrift() bool {
+	return len(d.Unmanaged) > 0 || len(d.Missing) > 0 || len(d.Changed) > 0
+}
+
+// WriteText writes the report for humans
+func (d *DriftReport) WriteText(w io.Writer) error {
+	var lines []string
+	if !d.HasDrift() {
+		lines = append(lines, "No drift: the configuration matches AWS")
+	}
+
+	if len(d.Unmanaged) > 0 {
+		lines = append(lines, fmt.Sprintf("Not in the configuration (%d):", len(d.Unmanaged)))
+		for _, r := range d.Unmanaged {
+			lines = append(lines, fmt.Sprintf("  + %s (%s)", r.Address(), r.ID))
+		}
+	}
+
+	if len(d.Missing) > 0 {
+		lines = append(lines, fmt.Sprintf("No longer in AWS (%d):", len(d.Missing)))
+		for _, r := range d.Missing {
+			lines = append(lines, fmt.Sprintf("  - %s (%s)", r.Address(), r.Location()))
+		}
+	}
+
+	if len(d.Changed) > 0 {
+		lines = append(lines, fmt.Sprintf("Changed attributes (%d):", len(d.Changed)))
+		for _, v := range d.Changed {
+			lines = append(lines, fmt.Sprintf("  ~ %s.%s: %s => %s (%s)", v.Address, v.Attribute,
+				driftValue(v.Configured), driftValue(v.Live), v.Location))
+		}
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// WriteJSON writes the report as JSON, e.g. for CI
+func (d *DriftReport) WriteJSON(w io.Writer) error {
+	type resource struct {
+		Address  string `json:"address"`
+		ID       string `json:"id,omitempty"`
+		Location string `json:"location,omitempty"`
+	}
+	view := func(rs Resources) []resource {
+		res := make([]resource, len(rs))
+		for i, r := range rs {
+			res[i] = resource{Address: r.Address(), ID: r.ID, Location: r.Location()}
+		}
+		return res
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Drift     bool              `json:"drift"`
+		Unmanaged []resource        `json:"unmanaged"`
+		Missing   []resource        `json:"missing"`
+		Changed   []*AttributeDrift `json:"changed"`
+	}{d.HasDrift(), view(d.Unmanaged), view(d.Missing), d.Changed})
+}
+
+func driftValue(v interface{}) string {
+	s := canonicalValue(v)
+	if len(s) > 60 {
+		s = s[:57] + "..."
+	}
+
+	return fmt.Sprintf("%q", s)
+}
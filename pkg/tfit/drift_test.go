@@ -0,0 +1,239 @@
+package tfit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func mustParseResources(t *testing.T, src string) Resources {
+	t.Helper()
+	res, err := ParseResources([]byte(src), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res
+}
+
+func addresses(rs Resources) []string {
+	res := []string{}
+	for _, r := range rs {
+		res = append(res, r.Address())
+	}
+
+	return res
+}
+
+func TestDrift(t *testing.T) {
+	cases := []struct {
+		name      string
+		config    string
+		live      string
+		types     []string
+		unmanaged []string
+		missing   []string
+		changed   []string
+	}{
+		{
+			name: "no drift",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  acl = "private"
+}`,
+			live: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  acl = "private"
+}`,
+			unmanaged: []string{},
+			missing:   []string{},
+			changed:   []string{},
+		},
+		{
+			name: "matched by identity",
+			config: `
+resource "aws_s3_bucket" "main" {
+  bucket = "logs"
+  acl = "private"
+}`,
+			live: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  acl = "public-read"
+}`,
+			unmanaged: []string{},
+			missing:   []string{},
+			changed:   []string{"aws_s3_bucket.main.acl"},
+		},
+		{
+			name: "matched by address",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "${var.bucket}"
+  acl = "private"
+}`,
+			live: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  acl = "private"
+}`,
+			unmanaged: []string{},
+			missing:   []string{},
+			changed:   []string{},
+		},
+		{
+			name: "unmanaged and missing",
+			config: `
+resource "aws_s3_bucket" "old" {
+  bucket = "old"
+}`,
+			live: `
+resource "aws_s3_bucket" "new" {
+  bucket = "new"
+}`,
+			unmanaged: []string{"aws_s3_bucket.new"},
+			missing:   []string{"aws_s3_bucket.old"},
+			changed:   []string{},
+		},
+		{
+			name: "types which were not fetched are ignored",
+			config: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_iam_user" "admin" {
+  name = "admin"
+}`,
+			live: `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}`,
+			types:     []string{"aws_s3_bucket"},
+			unmanaged: []string{},
+			missing:   []string{},
+			changed:   []string{},
+		},
+		{
+			name: "final dot of route53 names",
+			config: `
+resource "aws_route53_zone" "main" {
+  name = "example.com"
+}`,
+			live: `
+resource "aws_route53_zone" "example_com" {
+  name = "example.com."
+}`,
+			unmanaged: []string{},
+			missing:   []string{},
+			changed:   []string{},
+		},
+		{
+			name: "default resources",
+			config: `
+resource "aws_default_security_group" "default" {
+  vpc_id = "vpc-1"
+}
+
+resource "aws_default_route_table" "main" {
+  default_route_table_id = "rtb-1"
+}
+
+resource "aws_default_network_acl" "main" {
+  default_network_acl_id = "acl-1"
+}`,
+			live: `
+resource "aws_default_security_group" "vpc_1_default" {
+  vpc_id = "vpc-1"
+}
+
+resource "aws_default_route_table" "rtb_1" {
+  default_route_table_id = "rtb-1"
+}
+
+resource "aws_default_network_acl" "acl_1" {
+  default_network_acl_id = "acl-1"
+}`,
+			types:     []string{"aws_security_group", "aws_route_table", "aws_network_acl"},
+			unmanaged: []string{},
+			missing:   []string{},
+			changed:   []string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			report := Drift(mustParseResources(t, c.config), mustParseResources(t, c.live), c.types...)
+
+			if got := addresses(report.Unmanaged); !reflect.DeepEqual(got, c.unmanaged) {
+				t.Errorf("unmanaged: got %v, want %v", got, c.unmanaged)
+			}
+			if got := addresses(report.Missing); !reflect.DeepEqual(got, c.missing) {
+				t.Errorf("missing: got %v, want %v", got, c.missing)
+			}
+			changed := []string{}
+			for _, v := range report.Changed {
+				changed = append(changed, v.Address+"."+v.Attribute)
+			}
+			if !reflect.DeepEqual(changed, c.changed) {
+				t.Errorf("changed: got %v, want %v", changed, c.changed)
+			}
+			if want := len(c.unmanaged)+len(c.missing)+len(c.changed) > 0; report.HasDrift() != want {
+				t.Errorf("HasDrift: got %v, want %v", report.HasDrift(), want)
+			}
+		})
+	}
+}
+
+func TestDriftWithIDs(t *testing.T) {
+	config := mustParseResources(t, `
+resource "aws_s3_bucket" "logs" {
+  bucket = "old-logs"
+  acl = "private"
+}
+
+resource "aws_security_group" "default" {
+  vpc_id = "vpc-1"
+}
+
+resource "aws_s3_bucket" "gone" {
+  bucket = "assets"
+}`)
+	config[0].ID = "logs-123"
+	config[1].ID = "sg-1"
+	config[2].ID = "assets-old"
+
+	live := mustParseResources(t, `
+resource "aws_s3_bucket" "logs-123" {
+  bucket = "logs-123"
+  acl = "public-read"
+}
+
+resource "aws_s3_bucket" "assets" {
+  bucket = "assets"
+}
+
+resource "aws_default_security_group" "sg-1" {
+  vpc_id = "vpc-1"
+}`)
+	live[0].ID = "logs-123"
+	live[1].ID = "assets"
+	live[2].ID = "SG-1"
+
+	report := Drift(config, live)
+	if got, want := addresses(report.Missing), []string{"aws_s3_bucket.gone"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("missing: got %v, want %v", got, want)
+	}
+	if got, want := addresses(report.Unmanaged), []string{"aws_s3_bucket.assets"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unmanaged: got %v, want %v", got, want)
+	}
+
+	changed := []string{}
+	for _, v := range report.Changed {
+		changed = append(changed, v.Address+"."+v.Attribute+" "+v.ID)
+	}
+	if want := []string{"aws_s3_bucket.logs.acl logs-123", "aws_s3_bucket.logs.bucket logs-123"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed: got %v, want %v", changed, want)
+	}
+}
@@ -0,0 +1,490 @@
+package tfit
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dlm"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// The root volume of an instance and the volumes deleted with it are
+// exported as blocks of the aws_instance, the other ones as
+// aws_ebs_volume & aws_volume_attachment so that Terraform never
+// manages a volume twice.
+//
+// throughput can't be exported: the vendored SDK predates gp3 volumes.
+
+//**************** EBS Volume ****************
+
+type Volume struct {
+	VolumeID         *string
+	AvailabilityZone *string
+	Size             *int64
+	VolumeType       *string
+	Iops             *int64
+	Encrypted        *bool
+	KMSKeyID         *string
+	SnapshotID       *string
+	Tags             *Tags
+
+	Attachments []*VolumeAttachment
+
+	ResourceName string
+}
+
+type Volumes []*Volume
+
+// VolumeAttachment attaches a volume to an instance
+type VolumeAttachment struct {
+	DeviceName *string
+	VolumeID   *string
+	InstanceID *string
+
+	// Tags of the volume, used to name the attachment
+	Tags *Tags
+
+	ResourceName string
+}
+
+type VolumeAttachments []*VolumeAttachment
+
+// provisionedIOPSTypes are the volume types whose IOPS are configured,
+// the IOPS of the other types follow their size
+var provisionedIOPSTypes = map[string]bool{
+	ec2.VolumeTypeIo1: true,
+	"io2":             true,
+	"gp3":             true,
+}
+
+func (v *Volume) set(src *ec2.Volume) {
+	v.VolumeID = src.VolumeId
+	v.AvailabilityZone = src.AvailabilityZone
+	v.Size = src.Size
+	v.VolumeType = src.VolumeType
+	if provisionedIOPSTypes[aws.StringValue(src.VolumeType)] {
+		v.Iops = src.Iops
+	}
+	v.Encrypted = src.Encrypted
+	v.KMSKeyID = src.KmsKeyId
+	if len(aws.StringValue(src.SnapshotId)) > 0 {
+		v.SnapshotID = src.SnapshotId
+	}
+	v.Tags = &Tags{}
+	v.Tags.setTags(src.Tags)
+}
+
+func (v *Volume) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_ebs_volume",
+		ID:   aws.StringValue(v.VolumeID),
+		Tags: v.Tags.values(),
+	}
+}
+
+func (v *Volumes) addToGraph(g *Graph) {
+	for _, vol := range *v {
+		g.addNode(vol.nameInfo())
+	}
+}
+
+func (v *Volumes) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, vol := range *v {
+		res = append(res, vol.nameInfo())
+	}
+
+	return res
+}
+
+func (v *Volumes) addMissingTags(tags map[string]string) {
+	for _, vol := range *v {
+		if vol.Tags == nil {
+			vol.Tags = &Tags{}
+		}
+		vol.Tags.addMissing(tags)
+	}
+}
+
+// Attachments returns the attachments of the volumes
+func (v *Volumes) Attachments() *VolumeAttachments {
+	res := VolumeAttachments{}
+	for _, vol := range *v {
+		res = append(res, vol.Attachments...)
+	}
+
+	return &res
+}
+
+// id is the one Terraform gives to the attachment: a hash
+// of the device name, the instance & the volume
+func (a *VolumeAttachment) id() string {
+	key := fmt.Sprintf("%s-%s-%s-", aws.StringValue(a.DeviceName), aws.StringValue(a.InstanceID), aws.StringValue(a.VolumeID))
+	return fmt.Sprintf("vai-%d", crc32.ChecksumIEEE([]byte(key)))
+}
+
+func (a *VolumeAttachment) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_volume_attachment",
+		ID:   a.id(),
+		Name: aws.StringValue(a.VolumeID),
+		Tags: a.Tags.values(),
+	}
+}
+
+func (a *VolumeAttachments) addToGraph(g *Graph) {
+	for _, v := range *a {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "volume", "aws_ebs_volume", aws.StringValue(v.VolumeID))
+		g.addEdge(n, "instance", "aws_instance", aws.StringValue(v.InstanceID))
+	}
+}
+
+func (c *AWSClient) GetVolumes() (*Volumes, error) {
+	return c.GetVolumesWithContext(aws.BackgroundContext())
+}
+
+// GetVolumesWithContext returns the volumes which aws_instance doesn't
+// own, with their attachments to the instances which aren't terminated
+func (c *AWSClient) GetVolumesWithContext(ctx aws.Context) (*Volumes, error) {
+	roots, err := c.getInstanceRootDevicesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Volumes{}
+	opt := &ec2.DescribeVolumesInput{}
+	for {
+		out, err := c.ec2conn.DescribeVolumesWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing volumes: %s", err)
+		}
+
+		for _, v := range out.Volumes {
+			vol := &Volume{}
+			vol.set(v)
+
+			owned := false
+			for _, a := range v.Attachments {
+				root, ok := roots[aws.StringValue(a.InstanceId)]
+				if !ok {
+					continue
+				}
+				if aws.StringValue(a.Device) == root || aws.BoolValue(a.DeleteOnTermination) {
+					owned = true
+					break
+				}
+
+				vol.Attachments = append(vol.Attachments, &VolumeAttachment{
+					DeviceName: a.Device,
+					VolumeID:   a.VolumeId,
+					InstanceID: a.InstanceId,
+					Tags:       vol.Tags,
+				})
+			}
+
+			if !owned {
+				res = append(res, vol)
+			}
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	for _, v := range res {
+		v.ResourceName = c.namer.Name(v.nameInfo())
+		for _, a := range v.Attachments {
+			a.ResourceName = c.namer.Name(a.nameInfo())
+		}
+	}
+
+	return &res, nil
+}
+
+// getInstanceRootDevicesWithContext returns the root device name
+// of the instances which aren't terminated, by instance id
+func (c *AWSClient) getInstanceRootDevicesWithContext(ctx aws.Context) (map[string]string, error) {
+	res := make(map[string]string)
+	opt := &ec2.DescribeInstancesInput{}
+	for {
+		out, err := c.ec2conn.DescribeInstancesWithContext(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rsv := range out.Reservations {
+			for _, i := range rsv.Instances {
+				if aws.StringValue(i.State.Name) == ec2.InstanceStateNameTerminated {
+					continue
+				}
+				res[aws.StringValue(i.InstanceId)] = aws.StringValue(i.RootDeviceName)
+			}
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return res, nil
+}
+
+func (v *Volumes) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_ebs_volume" "{{ .ResourceName }}" {
+    availability_zone = "{{ .AvailabilityZone }}"
+    size = {{ .Size }}
+    {{- if .VolumeType }}
+    type = "{{ .VolumeType }}"
+    {{- end }}
+    {{- if .Iops }}
+    iops = {{ .Iops }}
+    {{- end }}
+    {{- if .Encrypted }}
+    encrypted = {{ .Encrypted }}
+    {{- end }}
+    {{- if .KMSKeyID }}
+    kms_key_id = "{{ .KMSKeyID }}"
+    {{- end }}
+    {{- if .SnapshotID }}
+    snapshot_id = "{{ .SnapshotID }}"
+    {{- end }}
+    {{- if .Tags }}
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, v)
+}
+
+func (a *VolumeAttachments) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_volume_attachment" "{{ .ResourceName }}" {
+    device_name = "{{ .DeviceName }}"
+    volume_id = "{{ .VolumeID }}"
+    instance_id = "{{ .InstanceID }}"
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, a)
+}
+
+//**************** DLM Lifecycle Policy ****************
+
+// LifecyclePolicy is a Data Lifecycle Manager policy
+// taking snapshots of the tagged volumes
+type LifecyclePolicy struct {
+	PolicyID         *string
+	Description      *string
+	ExecutionRoleARN *string
+	State            *string
+	ResourceTypes    []*string
+	Schedules        []*LifecycleSchedule
+	TargetTags       map[string]string
+
+	ResourceName string
+}
+
+type LifecycleSchedule struct {
+	Name         *string
+	CopyTags     *bool
+	Interval     *int64
+	IntervalUnit *string
+	Times        []*string
+	RetainCount  *int64
+	TagsToAdd    map[string]string
+}
+
+type LifecyclePolicies []*LifecyclePolicy
+
+func dlmTags(src []*dlm.Tag) map[string]string {
+	res := make(map[string]string, len(src))
+	for _, v := range src {
+		res[aws.StringValue(v.Key)] = aws.StringValue(v.Value)
+	}
+
+	return res
+}
+
+func (p *LifecyclePolicy) set(src *dlm.LifecyclePolicy) {
+	p.PolicyID = src.PolicyId
+	p.Description = src.Description
+	p.ExecutionRoleARN = src.ExecutionRoleArn
+	p.State = src.State
+	if src.PolicyDetails == nil {
+		return
+	}
+
+	p.ResourceTypes = src.PolicyDetails.ResourceTypes
+	p.TargetTags = dlmTags(src.PolicyDetails.TargetTags)
+	for _, v := range src.PolicyDetails.Schedules {
+		// Terraform needs an interval and a count, the schedules
+		// without them (cron expressions, ...) can't be exported
+		if v.CreateRule == nil || v.CreateRule.Interval == nil || v.RetainRule == nil || v.RetainRule.Count == nil {
+			continue
+		}
+
+		schedule := &LifecycleSchedule{
+			Name:         v.Name,
+			CopyTags:     v.CopyTags,
+			TagsToAdd:    dlmTags(v.TagsToAdd),
+			Interval:     v.CreateRule.Interval,
+			IntervalUnit: v.CreateRule.IntervalUnit,
+			Times:        v.CreateRule.Times,
+			RetainCount:  v.RetainRule.Count,
+		}
+		p.Schedules = append(p.Schedules, schedule)
+	}
+}
+
+// iamRoleName is the name of the execution role, the id of aws_iam_role
+func (p *LifecyclePolicy) iamRoleName() string {
+	arn := aws.StringValue(p.ExecutionRoleARN)
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+func (p *LifecyclePolicy) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_dlm_lifecycle_policy",
+		ID:   aws.StringValue(p.PolicyID),
+		Name: aws.StringValue(p.Description),
+	}
+}
+
+func (p *LifecyclePolicies) addToGraph(g *Graph) {
+	for _, v := range *p {
+		n := g.addNode(v.nameInfo())
+		if v.ExecutionRoleARN != nil {
+			g.addEdge(n, "execution_role_arn", "aws_iam_role", v.iamRoleName())
+		}
+	}
+}
+
+func (c *AWSClient) GetLifecyclePolicies() (*LifecyclePolicies, error) {
+	return c.GetLifecyclePoliciesWithContext(aws.BackgroundContext())
+}
+
+// GetLifecyclePoliciesWithContext returns the lifecycle policies, but
+// the ones in error which Terraform can't manage
+func (c *AWSClient) GetLifecyclePoliciesWithContext(ctx aws.Context) (*LifecyclePolicies, error) {
+	out, err := c.dlmconn.GetLifecyclePoliciesWithContext(ctx, &dlm.GetLifecyclePoliciesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("Error listing lifecycle policies: %s", err)
+	}
+
+	var summaries []*dlm.LifecyclePolicySummary
+	for _, v := range out.Policies {
+		if aws.StringValue(v.State) != dlm.GettablePolicyStateValuesError {
+			summaries = append(summaries, v)
+		}
+	}
+
+	policies := make([]*LifecyclePolicy, len(summaries))
+	err = c.pool.run(ctx, len(summaries), func(ctx aws.Context, i int) error {
+		out, err := c.dlmconn.GetLifecyclePolicyWithContext(ctx, &dlm.GetLifecyclePolicyInput{
+			PolicyId: summaries[i].PolicyId,
+		})
+		if err != nil {
+			return fmt.Errorf("Error getting lifecycle policy %s: %s", aws.StringValue(summaries[i].PolicyId), err)
+		}
+
+		p := &LifecyclePolicy{}
+		p.set(out.Policy)
+		policies[i] = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := LifecyclePolicies{}
+	for _, p := range policies {
+		p.ResourceName = c.namer.Name(p.nameInfo())
+		res = append(res, p)
+	}
+
+	return &res, nil
+}
+
+func (p *LifecyclePolicies) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformList": makeTerraformList,
+	}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_dlm_lifecycle_policy" "{{ .ResourceName }}" {
+    description = "{{ .Description }}"
+    execution_role_arn = "{{ .ExecutionRoleARN }}"
+    state = "{{ .State }}"
+
+    policy_details {
+      resource_types = [{{ .ResourceTypes | makeTerraformList }}]
+      {{- range .Schedules }}
+
+      schedule {
+        name = "{{ .Name }}"
+
+        create_rule {
+          interval = {{ .Interval }}
+          {{- if .IntervalUnit }}
+          interval_unit = "{{ .IntervalUnit }}"
+          {{- end }}
+          {{- if .Times }}
+          times = [{{ .Times | makeTerraformList }}]
+          {{- end }}
+        }
+
+        retain_rule {
+          count = {{ .RetainCount }}
+        }
+        {{- if .TagsToAdd }}
+
+        tags_to_add {
+          {{- range $k, $v := .TagsToAdd }}
+          "{{ $k }}" = "{{ $v }}"
+          {{- end }}
+        }
+        {{- end }}
+        {{- if .CopyTags }}
+        copy_tags = {{ .CopyTags }}
+        {{- end }}
+      }
+      {{- end }}
+
+      target_tags {
+        {{- range $k, $v := .TargetTags }}
+        "{{ $k }}" = "{{ $v }}"
+        {{- end }}
+      }
+    }
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, p)
+}
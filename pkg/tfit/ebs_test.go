@@ -0,0 +1,135 @@
+package tfit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dlm"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestVolumeSet(t *testing.T) {
+	cases := []struct {
+		name     string
+		src      ec2.Volume
+		iops     int64
+		snapshot string
+	}{
+		{"gp2", ec2.Volume{VolumeType: aws.String("gp2"), Iops: aws.Int64(300), SnapshotId: aws.String("")}, 0, ""},
+		{"io1", ec2.Volume{VolumeType: aws.String("io1"), Iops: aws.Int64(1000), SnapshotId: aws.String("snap-1")}, 1000, "snap-1"},
+		{"io2", ec2.Volume{VolumeType: aws.String("io2"), Iops: aws.Int64(2000)}, 2000, ""},
+		{"gp3", ec2.Volume{VolumeType: aws.String("gp3"), Iops: aws.Int64(3000)}, 3000, ""},
+		{"st1", ec2.Volume{VolumeType: aws.String("st1"), Iops: aws.Int64(500)}, 0, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			v := &Volume{}
+			v.set(&c.src)
+			if aws.Int64Value(v.Iops) != c.iops || aws.StringValue(v.SnapshotID) != c.snapshot {
+				t.Errorf("got iops %d & snapshot %q, want %d & %q", aws.Int64Value(v.Iops), aws.StringValue(v.SnapshotID), c.iops, c.snapshot)
+			}
+		})
+	}
+}
+
+func TestVolumesWriteHCL(t *testing.T) {
+	tags := &Tags{"Name": aws.String("data")}
+	attachment := &VolumeAttachment{
+		DeviceName:   aws.String("/dev/sdf"),
+		VolumeID:     aws.String("vol-1"),
+		InstanceID:   aws.String("i-1"),
+		Tags:         tags,
+		ResourceName: "data",
+	}
+	volumes := &Volumes{
+		{
+			VolumeID:         aws.String("vol-1"),
+			AvailabilityZone: aws.String("eu-west-1a"),
+			Size:             aws.Int64(100),
+			VolumeType:       aws.String("io1"),
+			Iops:             aws.Int64(1000),
+			Encrypted:        aws.Bool(true),
+			KMSKeyID:         aws.String("arn:aws:kms:eu-west-1:123456789012:key/1"),
+			Tags:             tags,
+			Attachments:      []*VolumeAttachment{attachment},
+			ResourceName:     "data",
+		},
+		{
+			VolumeID:         aws.String("vol-2"),
+			AvailabilityZone: aws.String("eu-west-1a"),
+			Size:             aws.Int64(8),
+			ResourceName:     "vol-2",
+		},
+	}
+
+	rs := mustRenderHCL(t, volumes)
+	if len(rs) != 2 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"size":       "100",
+		"type":       "io1",
+		"iops":       "1000",
+		"encrypted":  "true",
+		"kms_key_id": "arn:aws:kms:eu-west-1:123456789012:key/1",
+		"tags.Name":  "data",
+	})
+	checkAttributes(t, rs[1], map[string]string{"type": "", "iops": "", "encrypted": "", "tags.Name": ""})
+
+	rs = mustRenderHCL(t, volumes.Attachments())
+	if len(rs) != 1 || rs[0].Address() != "aws_volume_attachment.data" {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{"device_name": "/dev/sdf", "volume_id": "vol-1", "instance_id": "i-1"})
+
+	if id := attachment.id(); !strings.HasPrefix(id, "vai-") || id != attachment.id() {
+		t.Errorf("got id %s", id)
+	}
+}
+
+func TestLifecyclePoliciesWriteHCL(t *testing.T) {
+	p := &LifecyclePolicy{ResourceName: "daily"}
+	p.set(&dlm.LifecyclePolicy{
+		PolicyId:         aws.String("policy-1"),
+		Description:      aws.String("daily"),
+		ExecutionRoleArn: aws.String("arn:aws:iam::123456789012:role/dlm"),
+		State:            aws.String("ENABLED"),
+		PolicyDetails: &dlm.PolicyDetails{
+			ResourceTypes: aws.StringSlice([]string{"VOLUME"}),
+			TargetTags:    []*dlm.Tag{{Key: aws.String("backup"), Value: aws.String("true")}},
+			Schedules: []*dlm.Schedule{
+				{
+					Name:       aws.String("every 12h"),
+					CopyTags:   aws.Bool(true),
+					CreateRule: &dlm.CreateRule{Interval: aws.Int64(12), IntervalUnit: aws.String("HOURS"), Times: aws.StringSlice([]string{"03:00"})},
+					RetainRule: &dlm.RetainRule{Count: aws.Int64(14)},
+					TagsToAdd:  []*dlm.Tag{{Key: aws.String("type"), Value: aws.String("dlm")}},
+				},
+				{Name: aws.String("no interval"), CreateRule: &dlm.CreateRule{}, RetainRule: &dlm.RetainRule{Count: aws.Int64(1)}},
+				{Name: aws.String("no count"), CreateRule: &dlm.CreateRule{Interval: aws.Int64(24)}},
+			},
+		},
+	})
+	if p.iamRoleName() != "dlm" {
+		t.Errorf("got role %s", p.iamRoleName())
+	}
+
+	rs := mustRenderHCL(t, &LifecyclePolicies{p})
+	if len(rs) != 1 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"description":                                  "daily",
+		"state":                                        "ENABLED",
+		"policy_details.resource_types":                "[VOLUME]",
+		"policy_details.target_tags.backup":            "true",
+		"policy_details.schedule.name":                 "every 12h",
+		"policy_details.schedule.copy_tags":            "true",
+		"policy_details.schedule.create_rule.interval": "12",
+		"policy_details.schedule.create_rule.times":    "[03:00]",
+		"policy_details.schedule.retain_rule.count":    "14",
+		"policy_details.schedule.tags_to_add.type":     "dlm",
+	})
+}
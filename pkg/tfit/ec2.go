@@ -2,8 +2,10 @@ package tfit
 
 import (
 	"fmt"
+	"hash/crc32"
 	"io"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -24,6 +26,53 @@ type Instance struct {
 	SubnetID           *string
 	VpcID              *string
 	Tags               map[*string]*string
+
+	AvailabilityZone    *string
+	Tenancy             *string
+	PlacementGroup      *string
+	HostID              *string
+	PrivateIP           *string
+	SecondaryPrivateIPs []*string
+	AssociatePublicIP   *bool
+	CPUCoreCount        *int64
+	CPUThreadsPerCore   *int64
+
+	// Read with DescribeInstanceAttribute & DescribeInstanceCreditSpecifications.
+	// metadata_options can't be exported: the vendored SDK predates
+	// the instance metadata options.
+	UserData              *string
+	DisableAPITermination *bool
+	ShutdownBehavior      *string
+	CPUCredits            *string
+
+	RootDeviceName        *string
+	RootBlockDevice       *InstanceBlockDevice
+	EBSBlockDevices       []*InstanceBlockDevice
+	EphemeralBlockDevices []*EphemeralBlockDevice
+
+	ResourceName string
+}
+
+// InstanceBlockDevice is an EBS volume attached to an instance
+type InstanceBlockDevice struct {
+	DeviceName          *string
+	VolumeID            *string
+	DeleteOnTermination *bool
+
+	// From DescribeVolumes
+	VolumeType *string
+	VolumeSize *int64
+	Iops       *int64
+	Encrypted  *bool
+	KMSKeyID   *string
+	SnapshotID *string
+}
+
+// EphemeralBlockDevice is an instance store volume of the AMI
+type EphemeralBlockDevice struct {
+	DeviceName  *string
+	VirtualName *string
+	NoDevice    bool
 }
 
 // A group of Instance
@@ -50,7 +99,7 @@ func (i *Instance) set(src *ec2.Instance) error {
 	// Build []*string from []*ec2.GroupIdentifier
 	if src.SecurityGroups != nil {
 		for _, sg := range src.SecurityGroups {
-			i.SecurityGroups = append(i.SecurityGroups, sg.GroupName)
+			i.SecurityGroups = append(i.SecurityGroups, sg.GroupId)
 		}
 	}
 
@@ -58,6 +107,57 @@ func (i *Instance) set(src *ec2.Instance) error {
 	i.SubnetID = src.SubnetId
 	i.VpcID = src.VpcId
 
+	if src.Placement != nil {
+		i.AvailabilityZone = src.Placement.AvailabilityZone
+		i.HostID = src.Placement.HostId
+		if len(aws.StringValue(src.Placement.GroupName)) > 0 {
+			i.PlacementGroup = src.Placement.GroupName
+		}
+		if aws.StringValue(src.Placement.Tenancy) != ec2.TenancyDefault {
+			i.Tenancy = src.Placement.Tenancy
+		}
+	}
+
+	i.PrivateIP = src.PrivateIpAddress
+	for _, eni := range src.NetworkInterfaces {
+		if eni.Attachment == nil || aws.Int64Value(eni.Attachment.DeviceIndex) != 0 {
+			continue
+		}
+		for _, ip := range eni.PrivateIpAddresses {
+			if !aws.BoolValue(ip.Primary) {
+				i.SecondaryPrivateIPs = append(i.SecondaryPrivateIPs, ip.PrivateIpAddress)
+			}
+		}
+	}
+	if i.SubnetID != nil {
+		i.AssociatePublicIP = aws.Bool(src.PublicIpAddress != nil)
+	}
+
+	if src.CpuOptions != nil {
+		i.CPUCoreCount = src.CpuOptions.CoreCount
+		i.CPUThreadsPerCore = src.CpuOptions.ThreadsPerCore
+	}
+
+	i.RootDeviceName = src.RootDeviceName
+	for _, v := range src.BlockDeviceMappings {
+		if v.Ebs == nil {
+			continue
+		}
+
+		d := &InstanceBlockDevice{
+			DeviceName:          v.DeviceName,
+			VolumeID:            v.Ebs.VolumeId,
+			DeleteOnTermination: v.Ebs.DeleteOnTermination,
+		}
+		// The volumes which outlive the instance are exported
+		// as aws_ebs_volume & aws_volume_attachment
+		if aws.StringValue(v.DeviceName) == aws.StringValue(src.RootDeviceName) {
+			i.RootBlockDevice = d
+		} else if aws.BoolValue(v.Ebs.DeleteOnTermination) {
+			i.EBSBlockDevices = append(i.EBSBlockDevices, d)
+		}
+	}
+
 	// Build map[*]*string from []*ec2.Tag
 	if src.Tags != nil {
 		i.Tags = make(map[*string]*string)
@@ -69,6 +169,38 @@ func (i *Instance) set(src *ec2.Instance) error {
 	return nil
 }
 
+func (i *Instance) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_instance",
+		ID:   aws.StringValue(i.InstanceID),
+		Tags: ptrTagValues(i.Tags),
+	}
+}
+
+func (i *Instances) addToGraph(g *Graph) {
+	for _, v := range *i {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VpcID))
+		g.addEdge(n, "subnet", "aws_subnet", aws.StringValue(v.SubnetID))
+		g.addEdges(n, "security_group", "aws_security_group", v.SecurityGroups)
+	}
+}
+
+func (i *Instances) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *i {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (i *Instances) addMissingTags(tags map[string]string) {
+	for _, v := range *i {
+		v.Tags = addMissingPtrTags(v.Tags, tags)
+	}
+}
+
 func (i *Instances) set(src []*ec2.Instance) {
 	if src == nil {
 		return
@@ -89,12 +221,36 @@ func (i *Instances) set(src []*ec2.Instance) {
 
 // DescribeAllInstances ...
 func (c *AWSClient) GetInstances() (*Instances, error) {
-	ec2conn := c.ec2conn
+	return c.GetInstancesWithContext(aws.BackgroundContext())
+}
+
+// GetInstancesWithContext is the same as GetInstances with the addition of
+// the ability to pass a context for cancellation
+func (c *AWSClient) GetInstancesWithContext(ctx aws.Context) (*Instances, error) {
+	instances, err := c.describeInstancesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.getInstancesDetailsWithContext(ctx, instances); err != nil {
+		return nil, err
+	}
+
+	for _, v := range *instances {
+		v.ResourceName = c.namer.Name(v.nameInfo())
+	}
+
+	return instances, nil
+}
+
+// describeInstancesWithContext returns the instances as DescribeInstances
+// describes them, without their details nor their names
+func (c *AWSClient) describeInstancesWithContext(ctx aws.Context) (*Instances, error) {
 	instances := &Instances{}
 
 	opt := &ec2.DescribeInstancesInput{}
 	for {
-		out, err := ec2conn.DescribeInstances(opt)
+		out, err := c.ec2conn.DescribeInstancesWithContext(ctx, opt)
 		if err != nil {
 			return nil, err
 		}
@@ -103,29 +259,221 @@ func (c *AWSClient) GetInstances() (*Instances, error) {
 			instances.set(rsv.Instances)
 		}
 
-		if out.NextToken != nil {
-			opt.NextToken = out.NextToken
-			fmt.Println(aws.StringValue(opt.NextToken))
-		} else {
-			//fmt.Println("Breaking.......")
+		if out.NextToken == nil {
 			break
 		}
+		opt.NextToken = out.NextToken
 	}
 
 	return instances, nil
 }
 
+// getInstancesDetailsWithContext fills what DescribeInstances doesn't return:
+// the attributes, the volumes, the ephemeral devices of the AMIs & the CPU credits
+func (c *AWSClient) getInstancesDetailsWithContext(ctx aws.Context, instances *Instances) error {
+	err := c.pool.run(ctx, len(*instances), func(ctx aws.Context, k int) error {
+		return c.getInstanceAttributesWithContext(ctx, (*instances)[k])
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.getInstanceVolumesWithContext(ctx, instances); err != nil {
+		return err
+	}
+
+	if err := c.getInstanceEphemeralDevicesWithContext(ctx, instances); err != nil {
+		return err
+	}
+
+	return c.getInstanceCreditsWithContext(ctx, instances)
+}
+
+func (c *AWSClient) getInstanceAttributesWithContext(ctx aws.Context, i *Instance) error {
+	attributes := []string{
+		ec2.InstanceAttributeNameUserData,
+		ec2.InstanceAttributeNameDisableApiTermination,
+		ec2.InstanceAttributeNameInstanceInitiatedShutdownBehavior,
+	}
+
+	for _, attr := range attributes {
+		out, err := c.ec2conn.DescribeInstanceAttributeWithContext(ctx, &ec2.DescribeInstanceAttributeInput{
+			InstanceId: i.InstanceID,
+			Attribute:  aws.String(attr),
+		})
+		if err != nil {
+			return fmt.Errorf("Error reading %s of instance %s: %s", attr, aws.StringValue(i.InstanceID), err)
+		}
+
+		switch attr {
+		case ec2.InstanceAttributeNameUserData:
+			if out.UserData != nil && len(aws.StringValue(out.UserData.Value)) > 0 {
+				i.UserData = out.UserData.Value
+			}
+		case ec2.InstanceAttributeNameDisableApiTermination:
+			if out.DisableApiTermination != nil {
+				i.DisableAPITermination = out.DisableApiTermination.Value
+			}
+		case ec2.InstanceAttributeNameInstanceInitiatedShutdownBehavior:
+			if out.InstanceInitiatedShutdownBehavior != nil {
+				i.ShutdownBehavior = out.InstanceInitiatedShutdownBehavior.Value
+			}
+		}
+	}
+
+	return nil
+}
+
+// getInstanceVolumesWithContext describes the EBS volumes of the instances
+func (c *AWSClient) getInstanceVolumesWithContext(ctx aws.Context, instances *Instances) error {
+	devices := make(map[string]*InstanceBlockDevice)
+	var ids []*string
+	for _, i := range *instances {
+		all := i.EBSBlockDevices
+		if i.RootBlockDevice != nil {
+			all = append([]*InstanceBlockDevice{i.RootBlockDevice}, all...)
+		}
+		for _, d := range all {
+			devices[aws.StringValue(d.VolumeID)] = d
+			ids = append(ids, d.VolumeID)
+		}
+	}
+
+	chunks := chunkStrings(ids, 100)
+	return c.pool.run(ctx, len(chunks), func(ctx aws.Context, k int) error {
+		// A filter (unlike VolumeIds) doesn't fail on a volume deleted meanwhile
+		opt := &ec2.DescribeVolumesInput{
+			Filters: []*ec2.Filter{{Name: aws.String("volume-id"), Values: chunks[k]}},
+		}
+		for {
+			out, err := c.ec2conn.DescribeVolumesWithContext(ctx, opt)
+			if err != nil {
+				return err
+			}
+
+			for _, v := range out.Volumes {
+				d := devices[aws.StringValue(v.VolumeId)]
+				d.VolumeType = v.VolumeType
+				d.VolumeSize = v.Size
+				if provisionedIOPSTypes[aws.StringValue(v.VolumeType)] {
+					d.Iops = v.Iops
+				}
+				d.Encrypted = v.Encrypted
+				d.KMSKeyID = v.KmsKeyId
+				if len(aws.StringValue(v.SnapshotId)) > 0 {
+					d.SnapshotID = v.SnapshotId
+				}
+			}
+
+			if out.NextToken == nil {
+				return nil
+			}
+			opt.NextToken = out.NextToken
+		}
+	})
+}
+
+// getInstanceEphemeralDevicesWithContext reads the instance store
+// volumes, which only the block device mappings of the AMIs know
+func (c *AWSClient) getInstanceEphemeralDevicesWithContext(ctx aws.Context, instances *Instances) error {
+	byImage := make(map[string][]*Instance)
+	var ids []*string
+	for _, i := range *instances {
+		id := aws.StringValue(i.ImageID)
+		if _, ok := byImage[id]; !ok {
+			ids = append(ids, i.ImageID)
+		}
+		byImage[id] = append(byImage[id], i)
+	}
+
+	var mu sync.Mutex
+	chunks := chunkStrings(ids, 100)
+	return c.pool.run(ctx, len(chunks), func(ctx aws.Context, k int) error {
+		// A filter (unlike ImageIds) doesn't fail on a deregistered AMI
+		out, err := c.ec2conn.DescribeImagesWithContext(ctx, &ec2.DescribeImagesInput{
+			Filters: []*ec2.Filter{{Name: aws.String("image-id"), Values: chunks[k]}},
+		})
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, img := range out.Images {
+			for _, m := range img.BlockDeviceMappings {
+				if m.Ebs != nil {
+					continue
+				}
+
+				d := &EphemeralBlockDevice{DeviceName: m.DeviceName, VirtualName: m.VirtualName}
+				if m.NoDevice != nil {
+					d.NoDevice = true
+				}
+				for _, i := range byImage[aws.StringValue(img.ImageId)] {
+					i.EphemeralBlockDevices = append(i.EphemeralBlockDevices, d)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// Instance types with CPU credits
+var burstableInstanceTypes = []string{"t2.", "t3.", "t3a."}
+
+func (c *AWSClient) getInstanceCreditsWithContext(ctx aws.Context, instances *Instances) error {
+	byID := make(map[string]*Instance)
+	var ids []*string
+	for _, i := range *instances {
+		for _, prefix := range burstableInstanceTypes {
+			if strings.HasPrefix(aws.StringValue(i.InstanceType), prefix) {
+				byID[aws.StringValue(i.InstanceID)] = i
+				ids = append(ids, i.InstanceID)
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	chunks := chunkStrings(ids, 100)
+	return c.pool.run(ctx, len(chunks), func(ctx aws.Context, k int) error {
+		opt := &ec2.DescribeInstanceCreditSpecificationsInput{
+			Filters: []*ec2.Filter{{Name: aws.String("instance-id"), Values: chunks[k]}},
+		}
+		for {
+			out, err := c.ec2conn.DescribeInstanceCreditSpecificationsWithContext(ctx, opt)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			for _, v := range out.InstanceCreditSpecifications {
+				if i, ok := byID[aws.StringValue(v.InstanceId)]; ok {
+					i.CPUCredits = v.CpuCredits
+				}
+			}
+			mu.Unlock()
+
+			if out.NextToken == nil {
+				return nil
+			}
+			opt.NextToken = out.NextToken
+		}
+	})
+}
+
 // Render will render terraform format from 'Instances'
 func (i *Instances) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"joinstring":       joinStringSlice,
 		"StringValueSlice": aws.StringValueSlice,
+		"userData":         userDataHCL,
 	}
 
 	tmpl := `
 	{{ if . }}
 		{{ range . }}
-	resource "aws_instance" "{{ .InstanceID }}_instance" {
+	resource "aws_instance" "{{ .ResourceName }}" {
 		ami = "{{ .ImageID }}"
 		instance_type = "{{ .InstanceType }}"
 		{{- if .EbsOptimized }}
@@ -150,6 +498,109 @@ func (i *Instances) WriteHCL(w io.Writer) error {
     {{- $secgroup := StringValueSlice .SecurityGroups }}
     vpc_security_group_ids = [{{ $secgroup | joinstring "," }}]
     {{- end}}
+    {{- if .AvailabilityZone }}
+    availability_zone = "{{ .AvailabilityZone }}"
+    {{- end }}
+    {{- if .PlacementGroup }}
+    placement_group = "{{ .PlacementGroup }}"
+    {{- end }}
+    {{- if .Tenancy }}
+    tenancy = "{{ .Tenancy }}"
+    {{- end }}
+    {{- if .HostID }}
+    host_id = "{{ .HostID }}"
+    {{- end }}
+    {{- if .PrivateIP }}
+    private_ip = "{{ .PrivateIP }}"
+    {{- end }}
+    {{- if .SecondaryPrivateIPs }}
+    secondary_private_ips = [{{ StringValueSlice .SecondaryPrivateIPs | joinstring "," }}]
+    {{- end }}
+    {{- if .AssociatePublicIP }}
+    associate_public_ip_address = {{ .AssociatePublicIP }}
+    {{- end }}
+    {{- if .DisableAPITermination }}
+    disable_api_termination = {{ .DisableAPITermination }}
+    {{- end }}
+    {{- if .ShutdownBehavior }}
+    instance_initiated_shutdown_behavior = "{{ .ShutdownBehavior }}"
+    {{- end }}
+    {{- if .CPUCoreCount }}
+    cpu_core_count = {{ .CPUCoreCount }}
+    {{- end }}
+    {{- if .CPUThreadsPerCore }}
+    cpu_threads_per_core = {{ .CPUThreadsPerCore }}
+    {{- end }}
+    {{- if .UserData }}
+    {{ userData .UserData }}
+    {{- end }}
+    {{- if .CPUCredits }}
+
+    credit_specification {
+      cpu_credits = "{{ .CPUCredits }}"
+    }
+    {{- end }}
+    {{- with .RootBlockDevice }}
+
+    root_block_device {
+      {{- if .VolumeType }}
+      volume_type = "{{ .VolumeType }}"
+      {{- end }}
+      {{- if .VolumeSize }}
+      volume_size = {{ .VolumeSize }}
+      {{- end }}
+      {{- if .Iops }}
+      iops = {{ .Iops }}
+      {{- end }}
+      {{- if .Encrypted }}
+      encrypted = {{ .Encrypted }}
+      {{- end }}
+      {{- if .KMSKeyID }}
+      kms_key_id = "{{ .KMSKeyID }}"
+      {{- end }}
+      {{- if .DeleteOnTermination }}
+      delete_on_termination = {{ .DeleteOnTermination }}
+      {{- end }}
+    }
+    {{- end }}
+    {{- range .EBSBlockDevices }}
+
+    ebs_block_device {
+      device_name = "{{ .DeviceName }}"
+      {{- if .SnapshotID }}
+      snapshot_id = "{{ .SnapshotID }}"
+      {{- end }}
+      {{- if .VolumeType }}
+      volume_type = "{{ .VolumeType }}"
+      {{- end }}
+      {{- if .VolumeSize }}
+      volume_size = {{ .VolumeSize }}
+      {{- end }}
+      {{- if .Iops }}
+      iops = {{ .Iops }}
+      {{- end }}
+      {{- if .Encrypted }}
+      encrypted = {{ .Encrypted }}
+      {{- end }}
+      {{- if .KMSKeyID }}
+      kms_key_id = "{{ .KMSKeyID }}"
+      {{- end }}
+      {{- if .DeleteOnTermination }}
+      delete_on_termination = {{ .DeleteOnTermination }}
+      {{- end }}
+    }
+    {{- end }}
+    {{- range .EphemeralBlockDevices }}
+
+    ephemeral_block_device {
+      device_name = "{{ .DeviceName }}"
+      {{- if .NoDevice }}
+      no_device = true
+      {{- else }}
+      virtual_name = "{{ .VirtualName }}"
+      {{- end }}
+    }
+    {{- end }}
     {{if .Tags}}
     tags {
       {{range $k, $v := .Tags}}
@@ -183,18 +634,56 @@ type VPC struct {
 
 	//describe-vpc-classic-link-dns-support
 	EnableClassicLinkDnsSupport *bool
+
+	// The secondary IPv4 CIDR blocks & the DHCP options set of the VPC
+	CIDRBlockAssociations  []*VPCIPv4CIDRBlockAssociation
+	DHCPOptionsAssociation *VPCDHCPOptionsAssociation
+
+	ResourceName string
 }
 
 type VPCs []*VPC
 
-func (c *AWSClient) setVPCAttribute(vpc *VPC, classicLink *ec2.DescribeVpcClassicLinkOutput, classicLinkDnsSupport *ec2.DescribeVpcClassicLinkDnsSupportOutput) error {
+func (vpc *VPC) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_vpc",
+		ID:   aws.StringValue(vpc.VPCId),
+		Tags: vpc.Tags.values(),
+	}
+}
+
+func (vpcs *VPCs) addToGraph(g *Graph) {
+	for _, v := range *vpcs {
+		g.addNode(v.nameInfo())
+	}
+}
+
+func (vpcs *VPCs) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *vpcs {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (vpcs *VPCs) addMissingTags(tags map[string]string) {
+	for _, v := range *vpcs {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (c *AWSClient) setVPCAttribute(ctx aws.Context, vpc *VPC, classicLink *ec2.DescribeVpcClassicLinkOutput, classicLinkDnsSupport *ec2.DescribeVpcClassicLinkDnsSupportOutput) error {
 	opt := &ec2.DescribeVpcAttributeInput{
 		VpcId: vpc.VPCId,
 	}
 
 	// EnableDnsHostnames
 	opt = opt.SetAttribute("enableDnsHostnames")
-	output, err := c.ec2conn.DescribeVpcAttribute(opt)
+	output, err := c.ec2conn.DescribeVpcAttributeWithContext(ctx, opt)
 	if err != nil {
 		return err
 	}
@@ -202,7 +691,7 @@ func (c *AWSClient) setVPCAttribute(vpc *VPC, classicLink *ec2.DescribeVpcClassi
 
 	// EnableDnsSupport
 	opt = opt.SetAttribute("enableDnsSupport")
-	output, err = c.ec2conn.DescribeVpcAttribute(opt)
+	output, err = c.ec2conn.DescribeVpcAttributeWithContext(ctx, opt)
 	if err != nil {
 		return err
 	}
@@ -228,24 +717,30 @@ func (c *AWSClient) setVPCAttribute(vpc *VPC, classicLink *ec2.DescribeVpcClassi
 }
 
 func (c *AWSClient) GetVPCs() (*VPCs, error) {
+	return c.GetVPCsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetVPCsWithContext(ctx aws.Context) (*VPCs, error) {
 	res := VPCs{}
 
-	basicInfo, err := c.ec2conn.DescribeVpcs(&ec2.DescribeVpcsInput{})
+	basicInfo, err := c.ec2conn.DescribeVpcsWithContext(ctx, &ec2.DescribeVpcsInput{})
 	if err != nil {
 		return nil, err
 	}
 
-	classicLink, err := c.ec2conn.DescribeVpcClassicLink(&ec2.DescribeVpcClassicLinkInput{})
+	classicLink, err := c.ec2conn.DescribeVpcClassicLinkWithContext(ctx, &ec2.DescribeVpcClassicLinkInput{})
 	if err != nil {
 		return nil, err
 	}
 
-	classicLinkDnsSupport, err := c.ec2conn.DescribeVpcClassicLinkDnsSupport(&ec2.DescribeVpcClassicLinkDnsSupportInput{})
+	classicLinkDnsSupport, err := c.ec2conn.DescribeVpcClassicLinkDnsSupportWithContext(ctx, &ec2.DescribeVpcClassicLinkDnsSupportInput{})
 	if err != nil {
 		return nil, err
 	}
 
-	for _, v := range basicInfo.Vpcs {
+	res = make(VPCs, len(basicInfo.Vpcs))
+	err = c.pool.run(ctx, len(basicInfo.Vpcs), func(ctx aws.Context, i int) error {
+		v := basicInfo.Vpcs[i]
 		vpc := VPC{
 			CIDRBlock:       v.CidrBlock,
 			InstanceTenancy: v.InstanceTenancy,
@@ -258,12 +753,29 @@ func (c *AWSClient) GetVPCs() (*VPCs, error) {
 		if len(v.Ipv6CidrBlockAssociationSet) > 0 {
 			vpc.AssignGeneratedIPv6CIDRBlock = aws.Bool(true)
 		}
-		err = c.setVPCAttribute(&vpc, classicLink, classicLinkDnsSupport)
-		if err != nil {
-			return nil, err
+		vpc.setCIDRBlockAssociations(v.CidrBlockAssociationSet)
+		if id := aws.StringValue(v.DhcpOptionsId); id != "" && id != "default" {
+			vpc.DHCPOptionsAssociation = &VPCDHCPOptionsAssociation{VPCID: v.VpcId, DHCPOptionsID: v.DhcpOptionsId}
+		}
+		if err := c.setVPCAttribute(ctx, &vpc, classicLink, classicLinkDnsSupport); err != nil {
+			return err
 		}
 
-		res = append(res, &vpc)
+		res[i] = &vpc
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range res {
+		v.ResourceName = c.namer.Name(v.nameInfo())
+		for _, a := range v.CIDRBlockAssociations {
+			a.ResourceName = c.namer.Name(a.nameInfo(v.ResourceName))
+		}
+		if a := v.DHCPOptionsAssociation; a != nil {
+			a.ResourceName = c.namer.Name(a.nameInfo(v.ResourceName))
+		}
 	}
 
 	return &res, nil
@@ -275,7 +787,7 @@ func (vpcs *VPCs) WriteHCL(w io.Writer) error {
 	tmpl := `
 	{{ if . }}
 		{{- range . }}
-	resource "aws_vpc" "{{ index .Tags "Name" }}" {
+	resource "aws_vpc" "{{ .ResourceName }}" {
     cidr_block = "{{ .CIDRBlock }}"
     {{- if .InstanceTenancy }}
     instance_tenancy = "{{ .InstanceTenancy}}"
@@ -322,6 +834,7 @@ type Subnet struct {
 	AvailabilityZone            *string
 	SubnetId                    *string
 	AssignIpv6AddressOnCreation *bool
+	ResourceName                string
 }
 
 type Subnets []*Subnet
@@ -340,8 +853,45 @@ func (s *Subnet) setSubnet(src *ec2.Subnet) {
 	}
 }
 
+func (s *Subnet) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_subnet",
+		ID:   aws.StringValue(s.SubnetId),
+		Tags: s.Tags.values(),
+	}
+}
+
+func (s *Subnets) addToGraph(g *Graph) {
+	for _, v := range *s {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCId))
+	}
+}
+
+func (s *Subnets) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *s {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (s *Subnets) addMissingTags(tags map[string]string) {
+	for _, v := range *s {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
 func (c *AWSClient) GetSubnets() (*Subnets, error) {
-	data, err := c.ec2conn.DescribeSubnets(&ec2.DescribeSubnetsInput{})
+	return c.GetSubnetsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetSubnetsWithContext(ctx aws.Context) (*Subnets, error) {
+	data, err := c.ec2conn.DescribeSubnetsWithContext(ctx, &ec2.DescribeSubnetsInput{})
 	if err != nil {
 		return nil, err
 	}
@@ -350,6 +900,7 @@ func (c *AWSClient) GetSubnets() (*Subnets, error) {
 	for _, v := range data.Subnets {
 		tmp := &Subnet{}
 		tmp.setSubnet(v)
+		tmp.ResourceName = c.namer.Name(tmp.nameInfo())
 		output = append(output, tmp)
 	}
 
@@ -362,7 +913,7 @@ func (s *Subnets) WriteHCL(w io.Writer) error {
 	tmpl := `
 	{{ if . }}
 		{{- range . }}
-	resource "aws_subnet" "{{ .SubnetId}}" {
+	resource "aws_subnet" "{{ .ResourceName }}" {
     vpc_id = "{{ .VPCId}}"
 
     {{- if .AvailabilityZone}}
@@ -409,6 +960,14 @@ type SecurityGroup struct {
 	VPCId       *string
 	Ingresses   []*SecurityGroupRule
 	Egresses    []*SecurityGroupRule
+
+	// The default group of a VPC is exported as aws_default_security_group
+	Default bool
+
+	// The rules as aws_security_group_rule, see SplitRules
+	rules []*StandaloneSecurityGroupRule
+
+	ResourceName string
 }
 
 type SecurityGroups []*SecurityGroup
@@ -430,6 +989,7 @@ func (sg *SecurityGroup) setSecurityGroup(src *ec2.SecurityGroup, AccountId *str
 	sg.Tags = &Tags{}
 	sg.Tags.setTags(src.Tags)
 	sg.VPCId = src.VpcId
+	sg.Default = aws.StringValue(src.GroupName) == "default" && src.VpcId != nil
 
 	for _, v := range src.IpPermissions {
 		var tmp SecurityGroupRule
@@ -444,6 +1004,56 @@ func (sg *SecurityGroup) setSecurityGroup(src *ec2.SecurityGroup, AccountId *str
 	}
 }
 
+func (sg *SecurityGroup) nameInfo() NameInfo {
+	t := "aws_security_group"
+	if sg.Default {
+		t = "aws_default_security_group"
+	}
+
+	return NameInfo{
+		Type: t,
+		ID:   aws.StringValue(sg.GroupId),
+		Name: aws.StringValue(sg.Name),
+		Tags: sg.Tags.values(),
+	}
+}
+
+func (sg *SecurityGroups) addToGraph(g *Graph) {
+	for _, v := range *sg {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCId))
+
+		// Groups of other accounts are written "account/group"
+		for _, rules := range [][]*SecurityGroupRule{v.Ingresses, v.Egresses} {
+			for _, r := range rules {
+				for _, src := range r.SourceSecurityGroups {
+					if !strings.Contains(aws.StringValue(src), "/") {
+						g.addEdge(n, "source_security_group", "aws_security_group", aws.StringValue(src))
+					}
+				}
+			}
+		}
+	}
+}
+
+func (sg *SecurityGroups) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *sg {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (sg *SecurityGroups) addMissingTags(tags map[string]string) {
+	for _, v := range *sg {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
 func (r *SecurityGroupRule) setRule(src *ec2.IpPermission, AccountId *string) {
 	r.FromPort = src.FromPort
 	r.ToPort = src.ToPort
@@ -471,11 +1081,15 @@ func (r *SecurityGroupRule) setRule(src *ec2.IpPermission, AccountId *string) {
 }
 
 func (c *AWSClient) GetSecurityGroups(AccountId *string) (*SecurityGroups, error) {
+	return c.GetSecurityGroupsWithContext(aws.BackgroundContext(), AccountId)
+}
+
+func (c *AWSClient) GetSecurityGroupsWithContext(ctx aws.Context, AccountId *string) (*SecurityGroups, error) {
 	opt := ec2.DescribeSecurityGroupsInput{}
 	var output SecurityGroups
 
 	for {
-		data, err := c.ec2conn.DescribeSecurityGroups(&opt)
+		data, err := c.ec2conn.DescribeSecurityGroupsWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
@@ -483,6 +1097,7 @@ func (c *AWSClient) GetSecurityGroups(AccountId *string) (*SecurityGroups, error
 		for _, v := range data.SecurityGroups {
 			tmp := SecurityGroup{}
 			tmp.setSecurityGroup(v, AccountId)
+			tmp.ResourceName = c.namer.Name(tmp.nameInfo())
 			output = append([]*SecurityGroup(output), &tmp)
 		}
 
@@ -492,21 +1107,25 @@ func (c *AWSClient) GetSecurityGroups(AccountId *string) (*SecurityGroups, error
 			break
 		}
 	}
+	output.setStandaloneRules(c.namer)
 
 	return &output, nil
 }
 
 func (sg *SecurityGroups) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
-		"makeTerraformResourceName": makeTerraformResourceName,
-		"joinStringSlice":           joinStringSlice,
-		"StringValueSlice":          aws.StringValueSlice,
+		"joinStringSlice":  joinStringSlice,
+		"StringValueSlice": aws.StringValueSlice,
 	}
 
 	tmpl := `
 	{{ if . }}
 		{{- range . }}
-	resource "aws_security_group" "{{ makeTerraformResourceName .Name }}" {
+    {{- if .Default }}
+	resource "aws_default_security_group" "{{ .ResourceName }}" {
+    vpc_id = "{{ .VPCId }}"
+    {{- else }}
+	resource "aws_security_group" "{{ .ResourceName }}" {
     name = "{{ .Name }}"
 
     {{- if .Description }}
@@ -516,6 +1135,7 @@ func (sg *SecurityGroups) WriteHCL(w io.Writer) error {
     {{- if .VPCId}}
     vpc_id = "{{ .VPCId }}"
     {{- end}}
+    {{- end }}
 
     {{- if .Tags }}
     tags {
@@ -613,6 +1233,7 @@ func (sg *SecurityGroups) WriteHCL(w io.Writer) error {
 //**************** BEGIN Route Table ****************
 
 type Route struct {
+	RouteTableID                *string
 	CIDRBlock                   *string
 	IPv6CIDRBlock               *string
 	VpcPeeringConnectionId      *string
@@ -622,6 +1243,9 @@ type Route struct {
 	InstanceId                  *string
 	GatewayId                   *string
 	EgressOnlyInternetGatewayId *string
+
+	// Set when the route is a standalone aws_route
+	ResourceName string
 }
 
 func (r *Route) setRoute(src *ec2.Route) *Route {
@@ -644,20 +1268,100 @@ type RouteTable struct {
 	Id              *string
 	Routes          []*Route
 	PropagatingVgws []*string
+	ResourceName    string
+
+	// The original default route table of the VPC is exported as
+	// aws_default_route_table. When another table replaced it as the main
+	// one, that table has a MainAssociation.
+	Main            bool
+	Default         bool
+	Associations    []*RouteTableAssociation
+	MainAssociation *MainRouteTableAssociation
+	mainAssociation *string
+
+	// The propagating VGWs as aws_vpn_gateway_route_propagation
+	propagations []*VPNGatewayRoutePropagation
+}
+
+// RouteTableAssociation associates a subnet with a route table. The
+// vendored SDK predates the associations with gateways.
+type RouteTableAssociation struct {
+	AssociationID *string
+	RouteTableID  *string
+	SubnetID      *string
+
+	ResourceName string
 }
 
+type RouteTableAssociations []*RouteTableAssociation
+
+// MainRouteTableAssociation makes a route table other than
+// the original default one the main route table of its VPC
+type MainRouteTableAssociation struct {
+	AssociationID *string
+	VPCID         *string
+	RouteTableID  *string
+
+	ResourceName string
+}
+
+type MainRouteTableAssociations []*MainRouteTableAssociation
+
+// Routes are the routes split out of their route tables
+type Routes []*Route
+
+// VPNGatewayRoutePropagation propagates the routes of a VPN gateway to a route table
+type VPNGatewayRoutePropagation struct {
+	VPNGatewayID *string
+	RouteTableID *string
+
+	ResourceName string
+}
+
+type VPNGatewayRoutePropagations []*VPNGatewayRoutePropagation
+
 func (r *RouteTable) setRoutes(src []*ec2.Route) *RouteTable {
 	for _, v := range src {
-		tmp := Route{}
+		// Like Terraform, skip the local route, the propagated
+		// ones & the ones of the VPC endpoints
+		if aws.StringValue(v.GatewayId) == "local" ||
+			aws.StringValue(v.Origin) == ec2.RouteOriginEnableVgwRoutePropagation ||
+			strings.HasPrefix(aws.StringValue(v.GatewayId), "vpce-") {
+			continue
+		}
+
+		tmp := Route{RouteTableID: r.Id}
 		r.Routes = append(r.Routes, tmp.setRoute(v))
 	}
 
 	return r
 }
 
+func (r *RouteTable) setAssociations(src []*ec2.RouteTableAssociation) *RouteTable {
+	for _, v := range src {
+		if aws.BoolValue(v.Main) {
+			r.Main = true
+			r.mainAssociation = v.RouteTableAssociationId
+			continue
+		}
+
+		r.Associations = append(r.Associations, &RouteTableAssociation{
+			AssociationID: v.RouteTableAssociationId,
+			RouteTableID:  v.RouteTableId,
+			SubnetID:      v.SubnetId,
+		})
+	}
+
+	return r
+}
+
 func (r *RouteTable) setPropagatingVgws(src []*ec2.PropagatingVgw) *RouteTable {
 	for _, prgw := range src {
 		r.PropagatingVgws = append(r.PropagatingVgws, prgw.GatewayId)
+		r.propagations = append(r.propagations, &VPNGatewayRoutePropagation{
+			VPNGatewayID: prgw.GatewayId,
+			RouteTableID: r.Id,
+		})
 	}
 
 	return r
@@ -682,24 +1386,268 @@ func (r *RouteTable) setRouteTable(src *ec2.RouteTable) *RouteTable {
 	r = r.setPropagatingVgws(src.PropagatingVgws)
 	r = r.setRoutes(src.Routes)
 	r = r.setTags(src.Tags)
+	r = r.setAssociations(src.Associations)
 
 	return r
 }
 
 type RouteTables []*RouteTable
 
+// pristine tells whether the route table is still as AWS creates
+// the default one: no tags, routes, propagations nor subnets
+func (r *RouteTable) pristine() bool {
+	return len(r.Tags) == 0 && len(r.Routes) == 0 && len(r.propagations) == 0 && len(r.Associations) == 0
+}
+
+// setDefaults finds the original default route table of each VPC. The API
+// only tells the main table: another one replaced the default table when
+// the main table isn't pristine and exactly one other table of the VPC is.
+func (rtb *RouteTables) setDefaults() {
+	byVPC := make(map[string]RouteTables)
+	for _, v := range *rtb {
+		byVPC[aws.StringValue(v.VpcId)] = append(byVPC[aws.StringValue(v.VpcId)], v)
+	}
+
+	for _, tables := range byVPC {
+		var main *RouteTable
+		var pristine RouteTables
+		for _, v := range tables {
+			if v.Main {
+				main = v
+			} else if v.pristine() {
+				pristine = append(pristine, v)
+			}
+		}
+		if main == nil {
+			continue
+		}
+
+		if len(pristine) != 1 || main.pristine() {
+			main.Default = true
+			continue
+		}
+
+		pristine[0].Default = true
+		main.MainAssociation = &MainRouteTableAssociation{
+			AssociationID: main.mainAssociation,
+			VPCID:         main.VpcId,
+			RouteTableID:  main.Id,
+		}
+	}
+}
+
+func (r *RouteTable) nameInfo() NameInfo {
+	t := "aws_route_table"
+	if r.Default {
+		t = "aws_default_route_table"
+	}
+
+	return NameInfo{
+		Type: t,
+		ID:   aws.StringValue(r.Id),
+		Tags: resourceTagValues(r.Tags),
+	}
+}
+
+func (a *RouteTableAssociation) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_route_table_association",
+		ID:   aws.StringValue(a.AssociationID),
+		Name: aws.StringValue(a.SubnetID),
+	}
+}
+
+func (a *MainRouteTableAssociation) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_main_route_table_association",
+		ID:   aws.StringValue(a.AssociationID),
+		Name: aws.StringValue(a.VPCID),
+	}
+}
+
+// destination is the CIDR block the route matches
+func (r *Route) destination() string {
+	if r.CIDRBlock != nil {
+		return aws.StringValue(r.CIDRBlock)
+	}
+
+	return aws.StringValue(r.IPv6CIDRBlock)
+}
+
+// id is the one Terraform gives to the route: the route
+// table id followed by a hash of the destination
+func (r *Route) id() string {
+	return fmt.Sprintf("r-%s%d", aws.StringValue(r.RouteTableID), crc32.ChecksumIEEE([]byte(r.destination())))
+}
+
+func (r *Route) nameInfo(table string) NameInfo {
+	return NameInfo{
+		Type: "aws_route",
+		ID:   r.id(),
+		Name: table + "-" + r.destination(),
+	}
+}
+
+func (p *VPNGatewayRoutePropagation) nameInfo(table string) NameInfo {
+	return NameInfo{
+		Type: "aws_vpn_gateway_route_propagation",
+		ID:   aws.StringValue(p.VPNGatewayID) + "_" + aws.StringValue(p.RouteTableID),
+		Name: table + "-" + aws.StringValue(p.VPNGatewayID),
+	}
+}
+
+func (r *Route) targets() []*string {
+	return []*string{r.GatewayId, r.NatGatewayId, r.InstanceId, r.VpcPeeringConnectionId,
+		r.TransitGatewayId, r.NetworkInterfaceId, r.EgressOnlyInternetGatewayId}
+}
+
+func (rtb *RouteTables) addToGraph(g *Graph) {
+	for _, v := range *rtb {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VpcId))
+
+		for _, r := range v.Routes {
+			for _, id := range r.targets() {
+				g.addEdge(n, "route", routeTargetType(aws.StringValue(id)), aws.StringValue(id))
+			}
+		}
+		g.addEdges(n, "propagating_vgw", "aws_vpn_gateway", v.PropagatingVgws)
+	}
+}
+
+func (a *RouteTableAssociations) addToGraph(g *Graph) {
+	for _, v := range *a {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "route_table", "aws_route_table", aws.StringValue(v.RouteTableID))
+		g.addEdge(n, "subnet", "aws_subnet", aws.StringValue(v.SubnetID))
+	}
+}
+
+func (a *MainRouteTableAssociations) addToGraph(g *Graph) {
+	for _, v := range *a {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCID))
+		g.addEdge(n, "route_table", "aws_route_table", aws.StringValue(v.RouteTableID))
+	}
+}
+
+func (r *Routes) addToGraph(g *Graph) {
+	for _, v := range *r {
+		n := g.addNode(v.nameInfo(""))
+		g.addEdge(n, "route_table", "aws_route_table", aws.StringValue(v.RouteTableID))
+		for _, id := range v.targets() {
+			g.addEdge(n, "target", routeTargetType(aws.StringValue(id)), aws.StringValue(id))
+		}
+	}
+}
+
+func (p *VPNGatewayRoutePropagations) addToGraph(g *Graph) {
+	for _, v := range *p {
+		n := g.addNode(v.nameInfo(""))
+		g.addEdge(n, "vpn_gateway", "aws_vpn_gateway", aws.StringValue(v.VPNGatewayID))
+		g.addEdge(n, "route_table", "aws_route_table", aws.StringValue(v.RouteTableID))
+	}
+}
+
+// Associations returns the associations of the route tables with subnets
+func (rtb *RouteTables) Associations() *RouteTableAssociations {
+	res := RouteTableAssociations{}
+	for _, v := range *rtb {
+		res = append(res, v.Associations...)
+	}
+
+	return &res
+}
+
+// MainAssociations returns the associations making a table other than
+// the original default one the main route table of its VPC
+func (rtb *RouteTables) MainAssociations() *MainRouteTableAssociations {
+	res := MainRouteTableAssociations{}
+	for _, v := range *rtb {
+		if v.MainAssociation != nil {
+			res = append(res, v.MainAssociation)
+		}
+	}
+
+	return &res
+}
+
+// Routes returns the routes of the route tables as aws_route,
+// leaving the tables as they are
+func (rtb *RouteTables) Routes() *Routes {
+	res := Routes{}
+	for _, v := range *rtb {
+		res = append(res, v.Routes...)
+	}
+
+	return &res
+}
+
+// Propagations returns the propagating VGWs of the route tables as
+// aws_vpn_gateway_route_propagation, leaving the tables as they are
+func (rtb *RouteTables) Propagations() *VPNGatewayRoutePropagations {
+	res := VPNGatewayRoutePropagations{}
+	for _, v := range *rtb {
+		res = append(res, v.propagations...)
+	}
+
+	return &res
+}
+
+// SplitRoutes removes the routes from the route tables and returns them,
+// to be exported as aws_route
+func (rtb *RouteTables) SplitRoutes() *Routes {
+	res := rtb.Routes()
+	for _, v := range *rtb {
+		v.Routes = nil
+	}
+
+	return res
+}
+
+// SplitPropagations removes the propagating VGWs from the route tables and
+// returns them, to be exported as aws_vpn_gateway_route_propagation
+func (rtb *RouteTables) SplitPropagations() *VPNGatewayRoutePropagations {
+	res := rtb.Propagations()
+	for _, v := range *rtb {
+		v.PropagatingVgws = nil
+	}
+
+	return res
+}
+
+func (rtb *RouteTables) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *rtb {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (rtb *RouteTables) addMissingTags(tags map[string]string) {
+	for _, v := range *rtb {
+		v.Tags = addMissingResourceTags(v.Tags, tags)
+	}
+}
+
 func (c *AWSClient) GetRouteTables() (*RouteTables, error) {
+	return c.GetRouteTablesWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetRouteTablesWithContext(ctx aws.Context) (*RouteTables, error) {
 	opt := ec2.DescribeRouteTablesInput{}
 	res := RouteTables{}
 	for {
-		output, err := c.ec2conn.DescribeRouteTables(&opt)
+		output, err := c.ec2conn.DescribeRouteTablesWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, rtb := range output.RouteTables {
 			rtbTemp := &RouteTable{}
-			res = append(res, rtbTemp.setRouteTable(rtb))
+			rtbTemp = rtbTemp.setRouteTable(rtb)
+			res = append(res, rtbTemp)
 		}
 
 		if output.NextToken == nil {
@@ -709,6 +1657,24 @@ func (c *AWSClient) GetRouteTables() (*RouteTables, error) {
 		}
 	}
 
+	// The type of the tables depends on the other tables of their VPC
+	res.setDefaults()
+	for _, v := range res {
+		v.ResourceName = c.namer.Name(v.nameInfo())
+		for _, a := range v.Associations {
+			a.ResourceName = c.namer.Name(a.nameInfo())
+		}
+		if a := v.MainAssociation; a != nil {
+			a.ResourceName = c.namer.Name(a.nameInfo())
+		}
+		for _, r := range v.Routes {
+			r.ResourceName = c.namer.Name(r.nameInfo(v.ResourceName))
+		}
+		for _, p := range v.propagations {
+			p.ResourceName = c.namer.Name(p.nameInfo(v.ResourceName))
+		}
+	}
+
 	return &res, nil
 }
 
@@ -720,4 +1686,28 @@ func (rtb *RouteTables) WriteHCL(w io.Writer) error {
 	return renderHCL(w, EC2_ROUTE_TABLE, funcMap, rtb)
 }
 
+func (a *RouteTableAssociations) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	return renderHCL(w, EC2_ROUTE_TABLE_ASSOCIATION, funcMap, a)
+}
+
+func (a *MainRouteTableAssociations) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	return renderHCL(w, EC2_MAIN_ROUTE_TABLE_ASSOCIATION, funcMap, a)
+}
+
+func (r *Routes) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	return renderHCL(w, EC2_ROUTE, funcMap, r)
+}
+
+func (p *VPNGatewayRoutePropagations) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	return renderHCL(w, EC2_VPN_GATEWAY_ROUTE_PROPAGATION, funcMap, p)
+}
+
 //**************** END Route Table ****************
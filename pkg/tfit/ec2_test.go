@@ -0,0 +1,311 @@
+package tfit
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestInstancesWriteHCL(t *testing.T) {
+	instances := &Instances{
+		{
+			InstanceID:          aws.String("i-1"),
+			ImageID:             aws.String("ami-1"),
+			InstanceType:        aws.String("t3.micro"),
+			SubnetID:            aws.String("subnet-1"),
+			SecurityGroups:      aws.StringSlice([]string{"sg-1", "sg-2"}),
+			PrivateIP:           aws.String("10.0.0.10"),
+			SecondaryPrivateIPs: aws.StringSlice([]string{"10.0.0.11"}),
+			AssociatePublicIP:   aws.Bool(true),
+			CPUCoreCount:        aws.Int64(1),
+			CPUThreadsPerCore:   aws.Int64(2),
+			CPUCredits:          aws.String("unlimited"),
+			ShutdownBehavior:    aws.String("stop"),
+			UserData:            aws.String(base64.StdEncoding.EncodeToString([]byte("#!/bin/sh\necho ${HOME}\n"))),
+			RootBlockDevice: &InstanceBlockDevice{
+				DeviceName:          aws.String("/dev/xvda"),
+				VolumeType:          aws.String("gp3"),
+				VolumeSize:          aws.Int64(8),
+				Iops:                aws.Int64(4000),
+				Encrypted:           aws.Bool(true),
+				KMSKeyID:            aws.String("arn:aws:kms:eu-west-1:123456789012:key/1"),
+				DeleteOnTermination: aws.Bool(true),
+			},
+			EBSBlockDevices: []*InstanceBlockDevice{{
+				DeviceName: aws.String("/dev/sdf"),
+				VolumeType: aws.String("io1"),
+				VolumeSize: aws.Int64(100),
+				Iops:       aws.Int64(1000),
+				Encrypted:  aws.Bool(true),
+			}},
+			EphemeralBlockDevices: []*EphemeralBlockDevice{
+				{DeviceName: aws.String("/dev/sdb"), VirtualName: aws.String("ephemeral0")},
+				{DeviceName: aws.String("/dev/sdc"), NoDevice: true},
+			},
+			Tags:         map[*string]*string{aws.String("Name"): aws.String("web")},
+			ResourceName: "web",
+		},
+		{
+			InstanceID:   aws.String("i-2"),
+			ImageID:      aws.String("ami-1"),
+			InstanceType: aws.String("t2.micro"),
+			UserData:     aws.String(base64.StdEncoding.EncodeToString([]byte("echo hi"))),
+			ResourceName: "bare",
+		},
+	}
+
+	rs := mustRenderHCL(t, instances)
+	if len(rs) != 2 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+
+	checkAttributes(t, rs[0], map[string]string{
+		"ami":                                  "ami-1",
+		"vpc_security_group_ids":               "[sg-1 sg-2]",
+		"secondary_private_ips":                "[10.0.0.11]",
+		"associate_public_ip_address":          "true",
+		"cpu_core_count":                       "1",
+		"cpu_threads_per_core":                 "2",
+		"credit_specification.cpu_credits":     "unlimited",
+		"instance_initiated_shutdown_behavior": "stop",
+		"root_block_device.volume_type":        "gp3",
+		"root_block_device.volume_size":        "8",
+		"root_block_device.iops":               "4000",
+		"root_block_device.encrypted":          "true",
+		"root_block_device.kms_key_id":         "arn:aws:kms:eu-west-1:123456789012:key/1",
+		"ebs_block_device.device_name":         "/dev/sdf",
+		"ebs_block_device.iops":                "1000",
+		"ebs_block_device.encrypted":           "true",
+		"ebs_block_device.kms_key_id":          "",
+		"ephemeral_block_device":               "[map[device_name:/dev/sdb virtual_name:ephemeral0] map[device_name:/dev/sdc no_device:true]]",
+		"tags.Name":                            "web",
+		"user_data":                            "",
+	})
+	checkAttributes(t, rs[1], map[string]string{
+		"user_data":                        "echo hi",
+		"subnet_id":                        "",
+		"root_block_device.volume_type":    "",
+		"cpu_core_count":                   "",
+		"ebs_block_device.device_name":     "",
+		"credit_specification.cpu_credits": "",
+	})
+}
+
+func testRouteTables() *RouteTables {
+	src := []*ec2.RouteTable{
+		{
+			RouteTableId: aws.String("rtb-1"),
+			VpcId:        aws.String("vpc-1"),
+			Associations: []*ec2.RouteTableAssociation{{Main: aws.Bool(true), RouteTableId: aws.String("rtb-1")}},
+			Routes: []*ec2.Route{
+				{DestinationCidrBlock: aws.String("10.0.0.0/16"), GatewayId: aws.String("local")},
+				{DestinationCidrBlock: aws.String("0.0.0.0/0"), GatewayId: aws.String("igw-1")},
+			},
+		},
+		{
+			RouteTableId: aws.String("rtb-2"),
+			VpcId:        aws.String("vpc-1"),
+			Tags:         []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("private")}},
+			Associations: []*ec2.RouteTableAssociation{{
+				RouteTableAssociationId: aws.String("rtbassoc-1"),
+				RouteTableId:            aws.String("rtb-2"),
+				SubnetId:                aws.String("subnet-1"),
+			}},
+			PropagatingVgws: []*ec2.PropagatingVgw{{GatewayId: aws.String("vgw-1")}},
+			Routes: []*ec2.Route{
+				{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-1")},
+				{DestinationIpv6CidrBlock: aws.String("::/0"), EgressOnlyInternetGatewayId: aws.String("eigw-1")},
+				{DestinationCidrBlock: aws.String("192.168.0.0/16"), GatewayId: aws.String("vgw-1"), Origin: aws.String(ec2.RouteOriginEnableVgwRoutePropagation)},
+				{DestinationPrefixListId: aws.String("pl-1"), GatewayId: aws.String("vpce-1")},
+			},
+		},
+	}
+
+	return newTestRouteTables(src)
+}
+
+// newTestRouteTables names the route tables after their id
+func newTestRouteTables(src []*ec2.RouteTable) *RouteTables {
+	res := RouteTables{}
+	for _, v := range src {
+		res = append(res, (&RouteTable{}).setRouteTable(v))
+	}
+	res.setDefaults()
+
+	for _, rtb := range res {
+		rtb.ResourceName = aws.StringValue(rtb.Id)
+		if a := rtb.MainAssociation; a != nil {
+			a.ResourceName = aws.StringValue(a.VPCID)
+		}
+		for _, a := range rtb.Associations {
+			a.ResourceName = aws.StringValue(a.SubnetID)
+		}
+		for _, r := range rtb.Routes {
+			r.ResourceName = r.nameInfo(rtb.ResourceName).Name
+		}
+		for _, p := range rtb.propagations {
+			p.ResourceName = p.nameInfo(rtb.ResourceName).Name
+		}
+	}
+
+	return &res
+}
+
+func TestRouteTablesWriteHCL(t *testing.T) {
+	rtbs := testRouteTables()
+
+	rs := mustRenderHCL(t, rtbs)
+	if got, want := addresses(rs), []string{"aws_default_route_table.rtb-1", "aws_route_table.rtb-2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"default_route_table_id": "rtb-1",
+		"vpc_id":                 "",
+		"route.cidr_block":       "0.0.0.0/0",
+		"route.gateway_id":       "igw-1",
+	})
+	checkAttributes(t, rs[1], map[string]string{
+		"vpc_id":           "vpc-1",
+		"tags.Name":        "private",
+		"propagating_vgws": "[vgw-1]",
+		"route": "[map[cidr_block:0.0.0.0/0 nat_gateway_id:nat-1] " +
+			"map[egress_only_gateway_id:eigw-1 ipv6_cidr_block:::/0]]",
+	})
+
+	rs = mustRenderHCL(t, rtbs.Associations())
+	if got, want := addresses(rs), []string{"aws_route_table_association.subnet-1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	checkAttributes(t, rs[0], map[string]string{"subnet_id": "subnet-1", "route_table_id": "rtb-2"})
+
+	routes := rtbs.SplitRoutes()
+	rs = mustRenderHCL(t, routes)
+	if got, want := addresses(rs), []string{
+		"aws_route.rtb-1-0.0.0.0/0",
+		"aws_route.rtb-2-0.0.0.0/0",
+		"aws_route.rtb-2-::/0",
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	checkAttributes(t, rs[2], map[string]string{
+		"route_table_id":              "rtb-2",
+		"destination_ipv6_cidr_block": "::/0",
+		"egress_only_gateway_id":      "eigw-1",
+		"destination_cidr_block":      "",
+	})
+
+	propagations := rtbs.SplitPropagations()
+	rs = mustRenderHCL(t, propagations)
+	if got, want := addresses(rs), []string{"aws_vpn_gateway_route_propagation.rtb-2-vgw-1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	checkAttributes(t, rs[0], map[string]string{"vpn_gateway_id": "vgw-1", "route_table_id": "rtb-2"})
+
+	rs = mustRenderHCL(t, rtbs)
+	if _, ok := rs[1].Attributes["route"]; ok {
+		t.Error("the split routes are still rendered in the route table")
+	}
+	if _, ok := rs[1].Attributes["propagating_vgws"]; ok {
+		t.Error("the split propagations are still rendered in the route table")
+	}
+}
+
+func TestRouteTablesRoutes(t *testing.T) {
+	rtbs := testRouteTables()
+
+	if got := len(*rtbs.Routes()); got != 3 {
+		t.Errorf("got %d routes", got)
+	}
+	if got := len(*rtbs.Propagations()); got != 1 {
+		t.Errorf("got %d propagations", got)
+	}
+	if len((*rtbs)[1].Routes) != 2 || len((*rtbs)[1].PropagatingVgws) != 1 {
+		t.Error("the routes were removed from the route tables")
+	}
+	if got := len(*rtbs.MainAssociations()); got != 0 {
+		t.Errorf("got %d main associations", got)
+	}
+}
+
+func TestRouteTablesMainAssociation(t *testing.T) {
+	main := func(table string) []*ec2.RouteTableAssociation {
+		return []*ec2.RouteTableAssociation{{Main: aws.Bool(true), RouteTableAssociationId: aws.String("rtbassoc-main"), RouteTableId: aws.String(table)}}
+	}
+	igw := []*ec2.Route{
+		{DestinationCidrBlock: aws.String("10.0.0.0/16"), GatewayId: aws.String("local")},
+		{DestinationCidrBlock: aws.String("0.0.0.0/0"), GatewayId: aws.String("igw-1")},
+	}
+	local := []*ec2.Route{{DestinationCidrBlock: aws.String("10.0.0.0/16"), GatewayId: aws.String("local")}}
+
+	cases := []struct {
+		name  string
+		src   []*ec2.RouteTable
+		want  []string
+		assoc bool
+	}{
+		{
+			"default is main",
+			[]*ec2.RouteTable{
+				{RouteTableId: aws.String("rtb-1"), VpcId: aws.String("vpc-1"), Associations: main("rtb-1"), Routes: local},
+				{RouteTableId: aws.String("rtb-2"), VpcId: aws.String("vpc-1"), Routes: igw},
+			},
+			[]string{"aws_default_route_table.rtb-1", "aws_route_table.rtb-2"},
+			false,
+		},
+		{
+			"replaced default",
+			[]*ec2.RouteTable{
+				{RouteTableId: aws.String("rtb-1"), VpcId: aws.String("vpc-1"), Routes: local},
+				{RouteTableId: aws.String("rtb-2"), VpcId: aws.String("vpc-1"), Associations: main("rtb-2"), Routes: igw},
+			},
+			[]string{"aws_default_route_table.rtb-1", "aws_route_table.rtb-2"},
+			true,
+		},
+		{
+			"ambiguous",
+			[]*ec2.RouteTable{
+				{RouteTableId: aws.String("rtb-1"), VpcId: aws.String("vpc-1"), Routes: local},
+				{RouteTableId: aws.String("rtb-2"), VpcId: aws.String("vpc-1"), Associations: main("rtb-2"), Routes: igw},
+				{RouteTableId: aws.String("rtb-3"), VpcId: aws.String("vpc-1"), Routes: local},
+			},
+			[]string{"aws_route_table.rtb-1", "aws_default_route_table.rtb-2", "aws_route_table.rtb-3"},
+			false,
+		},
+		{
+			"other VPC",
+			[]*ec2.RouteTable{
+				{RouteTableId: aws.String("rtb-1"), VpcId: aws.String("vpc-2"), Routes: local},
+				{RouteTableId: aws.String("rtb-2"), VpcId: aws.String("vpc-1"), Associations: main("rtb-2"), Routes: igw},
+			},
+			[]string{"aws_route_table.rtb-1", "aws_default_route_table.rtb-2"},
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rtbs := newTestRouteTables(c.src)
+			if got := addresses(mustRenderHCL(t, rtbs)); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+
+			rs := mustRenderHCL(t, rtbs.MainAssociations())
+			if !c.assoc {
+				if len(rs) != 0 {
+					t.Errorf("got %v", addresses(rs))
+				}
+				return
+			}
+			if got, want := addresses(rs), []string{"aws_main_route_table_association.vpc-1"}; !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			checkAttributes(t, rs[0], map[string]string{"vpc_id": "vpc-1", "route_table_id": "rtb-2"})
+			if id := (*rtbs)[1].MainAssociation.nameInfo().ID; id != "rtbassoc-main" {
+				t.Errorf("got id %s", id)
+			}
+		})
+	}
+}
@@ -34,6 +34,7 @@ type ELBAccessLog struct {
 
 type ELB struct {
 	Name                      *string
+	DNSName                   *string
 	AvailabilityZones         []*string
 	SecurityGroups            []*string
 	Subnets                   []*string
@@ -48,10 +49,54 @@ type ELB struct {
 	IdleTimeout               *int64
 
 	Tags map[string]*string
+
+	ResourceName string
 }
 
 type ELBs []*ELB
 
+func (e *ELB) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_elb",
+		ID:   aws.StringValue(e.Name),
+		Name: aws.StringValue(e.Name),
+		Tags: tagValues(e.Tags),
+	}
+}
+
+func (elb *ELBs) addToGraph(g *Graph) {
+	for _, v := range *elb {
+		n := g.addNode(v.nameInfo())
+		g.addEdges(n, "subnet", "aws_subnet", v.Subnets)
+		g.addEdges(n, "instance", "aws_instance", v.Instances)
+		g.addEdges(n, "security_group", "aws_security_group", v.SecurityGroups)
+		if v.AccessLog != nil {
+			g.addEdge(n, "access_logs", "aws_s3_bucket", aws.StringValue(v.AccessLog.S3BucketName))
+		}
+
+		if v.DNSName != nil {
+			g.elbDNS[normalizeDNSName(aws.StringValue(v.DNSName))] = aws.StringValue(v.Name)
+		}
+	}
+}
+
+func (elb *ELBs) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *elb {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (elb *ELBs) addMissingTags(tags map[string]string) {
+	for _, v := range *elb {
+		t := Tags(v.Tags)
+		t.addMissing(tags)
+		v.Tags = t
+	}
+}
+
 func (e *ELB) setInstances(src []*elb.Instance) {
 	if src != nil {
 		for _, v := range src {
@@ -108,7 +153,7 @@ func (e *ELB) setAccessLog(src *elb.AccessLog) {
 	}
 }
 
-func (e *ELB) setELBAttributes(src *elb.LoadBalancerDescription, c *AWSClient) error {
+func (e *ELB) setELBAttributes(ctx aws.Context, src *elb.LoadBalancerDescription, c *AWSClient) error {
 	e.setInstances(src.Instances)
 	e.setHealthCheck(src.HealthCheck)
 
@@ -122,7 +167,7 @@ func (e *ELB) setELBAttributes(src *elb.LoadBalancerDescription, c *AWSClient) e
 	e.setListener(src.ListenerDescriptions)
 
 	opt := elb.DescribeLoadBalancerAttributesInput{LoadBalancerName: e.Name}
-	data, err := c.elbconn.DescribeLoadBalancerAttributes(&opt)
+	data, err := c.elbconn.DescribeLoadBalancerAttributesWithContext(ctx, &opt)
 	if err != nil {
 		return err
 	}
@@ -145,7 +190,7 @@ func (e *ELB) setELBAttributes(src *elb.LoadBalancerDescription, c *AWSClient) e
 	describeTagsOpt := elb.DescribeTagsInput{
 		LoadBalancerNames: []*string{e.Name},
 	}
-	tagsOutput, err := c.elbconn.DescribeTags(&describeTagsOpt)
+	tagsOutput, err := c.elbconn.DescribeTagsWithContext(ctx, &describeTagsOpt)
 	if err != nil {
 		return err
 	}
@@ -161,29 +206,43 @@ func (e *ELB) setELBAttributes(src *elb.LoadBalancerDescription, c *AWSClient) e
 }
 
 func (c *AWSClient) ListELBs() (*ELBs, error) {
+	return c.ListELBsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) ListELBsWithContext(ctx aws.Context) (*ELBs, error) {
 	opt := elb.DescribeLoadBalancersInput{}
 	var output ELBs
 	for {
-		data, err := c.elbconn.DescribeLoadBalancers(&opt)
+		data, err := c.elbconn.DescribeLoadBalancersWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
 
-		for _, v := range data.LoadBalancerDescriptions {
+		page := make(ELBs, len(data.LoadBalancerDescriptions))
+		err = c.pool.run(ctx, len(data.LoadBalancerDescriptions), func(ctx aws.Context, i int) error {
+			v := data.LoadBalancerDescriptions[i]
 			tmp := ELB{
 				Name:              v.LoadBalancerName,
+				DNSName:           v.DNSName,
 				AvailabilityZones: v.AvailabilityZones,
 				SecurityGroups:    v.SecurityGroups,
 				Subnets:           v.Subnets,
 			}
 
-			err := tmp.setELBAttributes(v, c)
-			if err != nil {
-				return nil, err
+			if err := tmp.setELBAttributes(ctx, v, c); err != nil {
+				return err
 			}
-			output = append(output, &tmp)
 
+			page[i] = &tmp
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range page {
+			v.ResourceName = c.namer.Name(v.nameInfo())
 		}
+		output = append(output, page...)
 
 		if data.NextMarker != nil {
 			opt.Marker = data.NextMarker
@@ -205,7 +264,7 @@ func (elb *ELBs) WriteHCL(w io.Writer) error {
 	tmpl := `
 	{{ if . }}
 		{{ range . }}
-	resource "aws_elb" "{{ .Name }}" {
+	resource "aws_elb" "{{ .ResourceName }}" {
     name = "{{ .Name }}"
 
     {{- if .AvailabilityZones }}
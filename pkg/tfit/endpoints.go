@@ -0,0 +1,275 @@
+package tfit
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// The vendored SDK doesn't return the tags of the
+// VPC endpoints & of the endpoint services
+
+// Endpoints & services in these states are gone or going
+var deadVPCEndpointStates = []string{
+	ec2.StateDeleting,
+	ec2.StateDeleted,
+	ec2.StateRejected,
+	ec2.StateFailed,
+	ec2.StateExpired,
+}
+
+func isDeadVPCEndpointState(state *string) bool {
+	for _, v := range deadVPCEndpointStates {
+		if strings.EqualFold(aws.StringValue(state), v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//**************** VPC Endpoint ****************
+
+type VPCEndpoint struct {
+	VPCEndpointID     *string
+	VPCID             *string
+	ServiceName       *string
+	VPCEndpointType   *string
+	Policy            *string
+	RouteTableIDs     []*string
+	SubnetIDs         []*string
+	SecurityGroupIDs  []*string
+	PrivateDNSEnabled *bool
+
+	ResourceName string
+}
+
+type VPCEndpoints []*VPCEndpoint
+
+func (vpce *VPCEndpoint) set(src *ec2.VpcEndpoint) {
+	vpce.VPCEndpointID = src.VpcEndpointId
+	vpce.VPCID = src.VpcId
+	vpce.ServiceName = src.ServiceName
+	vpce.VPCEndpointType = src.VpcEndpointType
+	if len(aws.StringValue(src.PolicyDocument)) > 0 {
+		vpce.Policy = src.PolicyDocument
+	}
+	vpce.RouteTableIDs = src.RouteTableIds
+	vpce.SubnetIDs = src.SubnetIds
+	for _, v := range src.Groups {
+		vpce.SecurityGroupIDs = append(vpce.SecurityGroupIDs, v.GroupId)
+	}
+
+	if aws.StringValue(src.VpcEndpointType) == ec2.VpcEndpointTypeInterface {
+		vpce.PrivateDNSEnabled = src.PrivateDnsEnabled
+	}
+}
+
+func (vpce *VPCEndpoint) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_vpc_endpoint",
+		ID:   aws.StringValue(vpce.VPCEndpointID),
+		Name: aws.StringValue(vpce.ServiceName),
+	}
+}
+
+func (vpces *VPCEndpoints) addToGraph(g *Graph) {
+	for _, v := range *vpces {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCID))
+		g.addEdges(n, "route_table", "aws_route_table", v.RouteTableIDs)
+		g.addEdges(n, "subnet", "aws_subnet", v.SubnetIDs)
+		g.addEdges(n, "security_group", "aws_security_group", v.SecurityGroupIDs)
+	}
+}
+
+func (c *AWSClient) GetVPCEndpoints() (*VPCEndpoints, error) {
+	return c.GetVPCEndpointsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetVPCEndpointsWithContext(ctx aws.Context) (*VPCEndpoints, error) {
+	res := VPCEndpoints{}
+	opt := &ec2.DescribeVpcEndpointsInput{}
+	for {
+		out, err := c.ec2conn.DescribeVpcEndpointsWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing VPC endpoints: %s", err)
+		}
+
+		for _, v := range out.VpcEndpoints {
+			if isDeadVPCEndpointState(v.State) {
+				continue
+			}
+
+			vpce := &VPCEndpoint{}
+			vpce.set(v)
+			vpce.ResourceName = c.namer.Name(vpce.nameInfo())
+			res = append(res, vpce)
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+func (vpces *VPCEndpoints) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformList": makeTerraformList,
+		"prettyJSON":        prettyJSON,
+	}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_vpc_endpoint" "{{ .ResourceName }}" {
+    vpc_id = "{{ .VPCID }}"
+    service_name = "{{ .ServiceName }}"
+    vpc_endpoint_type = "{{ .VPCEndpointType }}"
+    {{- if .RouteTableIDs }}
+    route_table_ids = [{{ .RouteTableIDs | makeTerraformList }}]
+    {{- end }}
+    {{- if .SubnetIDs }}
+    subnet_ids = [{{ .SubnetIDs | makeTerraformList }}]
+    {{- end }}
+    {{- if .SecurityGroupIDs }}
+    security_group_ids = [{{ .SecurityGroupIDs | makeTerraformList }}]
+    {{- end }}
+    {{- if .PrivateDNSEnabled }}
+    private_dns_enabled = {{ .PrivateDNSEnabled }}
+    {{- end }}
+    {{- if .Policy }}
+    policy = <<POLICY
+{{ prettyJSON .Policy }}
+POLICY
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, vpces)
+}
+
+//**************** VPC Endpoint Service ****************
+
+type VPCEndpointService struct {
+	ServiceID               *string
+	ServiceName             *string
+	AcceptanceRequired      *bool
+	NetworkLoadBalancerARNs []*string
+	AllowedPrincipals       []*string
+
+	ResourceName string
+}
+
+type VPCEndpointServices []*VPCEndpointService
+
+func (s *VPCEndpointService) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_vpc_endpoint_service",
+		ID:   aws.StringValue(s.ServiceID),
+		Name: aws.StringValue(s.ServiceName),
+	}
+}
+
+func (s *VPCEndpointServices) addToGraph(g *Graph) {
+	for _, v := range *s {
+		n := g.addNode(v.nameInfo())
+		g.addEdges(n, "network_load_balancer", "aws_lb", v.NetworkLoadBalancerARNs)
+	}
+}
+
+func (c *AWSClient) GetVPCEndpointServices() (*VPCEndpointServices, error) {
+	return c.GetVPCEndpointServicesWithContext(aws.BackgroundContext())
+}
+
+// GetVPCEndpointServicesWithContext returns the endpoint services of the
+// account with the principals allowed to connect to them
+func (c *AWSClient) GetVPCEndpointServicesWithContext(ctx aws.Context) (*VPCEndpointServices, error) {
+	res := VPCEndpointServices{}
+	opt := &ec2.DescribeVpcEndpointServiceConfigurationsInput{}
+	for {
+		out, err := c.ec2conn.DescribeVpcEndpointServiceConfigurationsWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing VPC endpoint services: %s", err)
+		}
+
+		for _, v := range out.ServiceConfigurations {
+			if isDeadVPCEndpointState(v.ServiceState) {
+				continue
+			}
+
+			res = append(res, &VPCEndpointService{
+				ServiceID:               v.ServiceId,
+				ServiceName:             v.ServiceName,
+				AcceptanceRequired:      v.AcceptanceRequired,
+				NetworkLoadBalancerARNs: v.NetworkLoadBalancerArns,
+			})
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	err := c.pool.run(ctx, len(res), func(ctx aws.Context, i int) error {
+		return c.getVPCEndpointServicePermissionsWithContext(ctx, res[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range res {
+		v.ResourceName = c.namer.Name(v.nameInfo())
+	}
+
+	return &res, nil
+}
+
+func (c *AWSClient) getVPCEndpointServicePermissionsWithContext(ctx aws.Context, s *VPCEndpointService) error {
+	opt := &ec2.DescribeVpcEndpointServicePermissionsInput{ServiceId: s.ServiceID}
+	for {
+		out, err := c.ec2conn.DescribeVpcEndpointServicePermissionsWithContext(ctx, opt)
+		if err != nil {
+			return fmt.Errorf("Error describing the permissions of VPC endpoint service %s: %s", aws.StringValue(s.ServiceID), err)
+		}
+
+		for _, v := range out.AllowedPrincipals {
+			s.AllowedPrincipals = append(s.AllowedPrincipals, v.Principal)
+		}
+
+		if out.NextToken == nil {
+			return nil
+		}
+		opt.NextToken = out.NextToken
+	}
+}
+
+func (s *VPCEndpointServices) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformList": makeTerraformList,
+	}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_vpc_endpoint_service" "{{ .ResourceName }}" {
+    acceptance_required = {{ .AcceptanceRequired }}
+    network_load_balancer_arns = [{{ .NetworkLoadBalancerARNs | makeTerraformList }}]
+    {{- if .AllowedPrincipals }}
+    allowed_principals = [{{ .AllowedPrincipals | makeTerraformList }}]
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, s)
+}
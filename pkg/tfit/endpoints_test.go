@@ -0,0 +1,102 @@
+package tfit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestIsDeadVPCEndpointState(t *testing.T) {
+	cases := []struct {
+		state string
+		want  bool
+	}{
+		{"available", false},
+		{"pendingAcceptance", false},
+		{"deleted", true},
+		{"Deleting", true},
+		{"rejected", true},
+	}
+
+	for _, c := range cases {
+		if got := isDeadVPCEndpointState(aws.String(c.state)); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.state, got, c.want)
+		}
+	}
+}
+
+func TestVPCEndpointsWriteHCL(t *testing.T) {
+	src := []*ec2.VpcEndpoint{
+		{
+			VpcEndpointId:     aws.String("vpce-1"),
+			VpcId:             aws.String("vpc-1"),
+			ServiceName:       aws.String("com.amazonaws.eu-west-1.s3"),
+			VpcEndpointType:   aws.String(ec2.VpcEndpointTypeGateway),
+			PolicyDocument:    aws.String(`{"Statement":[{"Effect":"Allow","Principal":"*","Action":"*","Resource":"*"}]}`),
+			RouteTableIds:     aws.StringSlice([]string{"rtb-1"}),
+			PrivateDnsEnabled: aws.Bool(false),
+		},
+		{
+			VpcEndpointId:     aws.String("vpce-2"),
+			VpcId:             aws.String("vpc-1"),
+			ServiceName:       aws.String("com.amazonaws.eu-west-1.ssm"),
+			VpcEndpointType:   aws.String(ec2.VpcEndpointTypeInterface),
+			PolicyDocument:    aws.String(""),
+			SubnetIds:         aws.StringSlice([]string{"subnet-1", "subnet-2"}),
+			Groups:            []*ec2.SecurityGroupIdentifier{{GroupId: aws.String("sg-1")}},
+			PrivateDnsEnabled: aws.Bool(true),
+		},
+	}
+
+	vpces := VPCEndpoints{}
+	for _, v := range src {
+		vpce := &VPCEndpoint{}
+		vpce.set(v)
+		vpce.ResourceName = aws.StringValue(v.VpcEndpointId)
+		vpces = append(vpces, vpce)
+	}
+
+	rs := mustRenderHCL(t, &vpces)
+	if len(rs) != 2 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"vpc_endpoint_type":   "Gateway",
+		"route_table_ids":     "[rtb-1]",
+		"private_dns_enabled": "",
+	})
+	if !strings.Contains(rs[0].Attributes["policy"].(string), `"Effect": "Allow"`) {
+		t.Errorf("got policy %v", rs[0].Attributes["policy"])
+	}
+	checkAttributes(t, rs[1], map[string]string{
+		"vpc_endpoint_type":   "Interface",
+		"subnet_ids":          "[subnet-1 subnet-2]",
+		"security_group_ids":  "[sg-1]",
+		"private_dns_enabled": "true",
+		"policy":              "",
+	})
+}
+
+func TestVPCEndpointServicesWriteHCL(t *testing.T) {
+	services := &VPCEndpointServices{{
+		ServiceID:               aws.String("vpce-svc-1"),
+		ServiceName:             aws.String("com.amazonaws.vpce.eu-west-1.vpce-svc-1"),
+		AcceptanceRequired:      aws.Bool(true),
+		NetworkLoadBalancerARNs: aws.StringSlice([]string{"arn:aws:elasticloadbalancing:eu-west-1:111111111111:loadbalancer/net/api/1"}),
+		AllowedPrincipals:       aws.StringSlice([]string{"arn:aws:iam::222222222222:root"}),
+		ResourceName:            "api",
+	}}
+
+	rs := mustRenderHCL(t, services)
+	if got, want := addresses(rs), []string{"aws_vpc_endpoint_service.api"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"acceptance_required":        "true",
+		"network_load_balancer_arns": "[arn:aws:elasticloadbalancing:eu-west-1:111111111111:loadbalancer/net/api/1]",
+		"allowed_principals":         "[arn:aws:iam::222222222222:root]",
+	})
+}
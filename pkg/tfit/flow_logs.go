@@ -0,0 +1,157 @@
+package tfit
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+//**************** Flow Log ****************
+
+// FlowLog captures the traffic of a VPC, a subnet or a network interface.
+// The vendored SDK doesn't return the tags of the flow logs.
+type FlowLog struct {
+	FlowLogID          *string
+	ResourceID         *string
+	TrafficType        *string
+	LogDestinationType *string
+	LogDestination     *string
+	LogGroupName       *string
+	IAMRoleARN         *string
+
+	ResourceName string
+}
+
+type FlowLogs []*FlowLog
+
+func (fl *FlowLog) set(src *ec2.FlowLog) {
+	fl.FlowLogID = src.FlowLogId
+	fl.ResourceID = src.ResourceId
+	fl.TrafficType = src.TrafficType
+	fl.LogDestinationType = src.LogDestinationType
+	fl.IAMRoleARN = src.DeliverLogsPermissionArn
+
+	// The logs of CloudWatch go to a log group, the others to an ARN
+	if aws.StringValue(src.LogDestinationType) == ec2.LogDestinationTypeS3 {
+		fl.LogDestination = src.LogDestination
+	} else {
+		fl.LogGroupName = src.LogGroupName
+	}
+}
+
+// resourceType is the Terraform type of the resource whose traffic is
+// captured, its id is the value of the vpc_id, subnet_id or eni_id attribute
+func (fl *FlowLog) resourceType() string {
+	id := aws.StringValue(fl.ResourceID)
+	switch {
+	case strings.HasPrefix(id, "vpc-"):
+		return "aws_vpc"
+	case strings.HasPrefix(id, "subnet-"):
+		return "aws_subnet"
+	case strings.HasPrefix(id, "eni-"):
+		return "aws_network_interface"
+	}
+
+	return ""
+}
+
+// ResourceAttribute is the attribute of the captured resource
+func (fl *FlowLog) ResourceAttribute() string {
+	switch fl.resourceType() {
+	case "aws_vpc":
+		return "vpc_id"
+	case "aws_subnet":
+		return "subnet_id"
+	case "aws_network_interface":
+		return "eni_id"
+	}
+
+	return ""
+}
+
+// iamRoleName is the name of the delivery role, the id of aws_iam_role
+func (fl *FlowLog) iamRoleName() string {
+	arn := aws.StringValue(fl.IAMRoleARN)
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+func (fl *FlowLog) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_flow_log",
+		ID:   aws.StringValue(fl.FlowLogID),
+		Name: aws.StringValue(fl.ResourceID) + "-" + strings.ToLower(aws.StringValue(fl.TrafficType)),
+	}
+}
+
+func (fls *FlowLogs) addToGraph(g *Graph) {
+	for _, v := range *fls {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, v.ResourceAttribute(), v.resourceType(), aws.StringValue(v.ResourceID))
+		if v.IAMRoleARN != nil {
+			g.addEdge(n, "iam_role", "aws_iam_role", v.iamRoleName())
+		}
+	}
+}
+
+func (c *AWSClient) GetFlowLogs() (*FlowLogs, error) {
+	return c.GetFlowLogsWithContext(aws.BackgroundContext())
+}
+
+// GetFlowLogsWithContext returns the flow logs of the VPCs, the
+// subnets & the network interfaces
+func (c *AWSClient) GetFlowLogsWithContext(ctx aws.Context) (*FlowLogs, error) {
+	res := FlowLogs{}
+	opt := &ec2.DescribeFlowLogsInput{}
+	for {
+		out, err := c.ec2conn.DescribeFlowLogsWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing flow logs: %s", err)
+		}
+
+		for _, v := range out.FlowLogs {
+			fl := &FlowLog{}
+			fl.set(v)
+			fl.ResourceName = c.namer.Name(fl.nameInfo())
+			res = append(res, fl)
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+func (fls *FlowLogs) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_flow_log" "{{ .ResourceName }}" {
+    {{ .ResourceAttribute }} = "{{ .ResourceID }}"
+    traffic_type = "{{ .TrafficType }}"
+    {{- if .LogDestinationType }}
+    log_destination_type = "{{ .LogDestinationType }}"
+    {{- end }}
+    {{- if .LogDestination }}
+    log_destination = "{{ .LogDestination }}"
+    {{- end }}
+    {{- if .LogGroupName }}
+    log_group_name = "{{ .LogGroupName }}"
+    {{- end }}
+    {{- if .IAMRoleARN }}
+    iam_role_arn = "{{ .IAMRoleARN }}"
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, fls)
+}
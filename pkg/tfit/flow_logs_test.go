@@ -0,0 +1,64 @@
+package tfit
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestFlowLogsWriteHCL(t *testing.T) {
+	src := []*ec2.FlowLog{
+		{
+			FlowLogId:                aws.String("fl-1"),
+			ResourceId:               aws.String("vpc-1"),
+			TrafficType:              aws.String("ALL"),
+			LogDestinationType:       aws.String(ec2.LogDestinationTypeCloudWatchLogs),
+			LogGroupName:             aws.String("vpc-flow-logs"),
+			DeliverLogsPermissionArn: aws.String("arn:aws:iam::123456789012:role/flow-logs"),
+		},
+		{
+			FlowLogId:          aws.String("fl-2"),
+			ResourceId:         aws.String("eni-1"),
+			TrafficType:        aws.String("REJECT"),
+			LogDestinationType: aws.String(ec2.LogDestinationTypeS3),
+			LogDestination:     aws.String("arn:aws:s3:::flow-logs"),
+			LogGroupName:       aws.String(""),
+		},
+	}
+
+	fls := FlowLogs{}
+	for _, v := range src {
+		fl := &FlowLog{}
+		fl.set(v)
+		fl.ResourceName = fl.nameInfo().Name
+		fls = append(fls, fl)
+	}
+
+	rs := mustRenderHCL(t, &fls)
+	if len(rs) != 2 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"vpc_id":               "vpc-1",
+		"traffic_type":         "ALL",
+		"log_destination_type": "cloud-watch-logs",
+		"log_group_name":       "vpc-flow-logs",
+		"log_destination":      "",
+		"iam_role_arn":         "arn:aws:iam::123456789012:role/flow-logs",
+	})
+	checkAttributes(t, rs[1], map[string]string{
+		"eni_id":          "eni-1",
+		"vpc_id":          "",
+		"log_destination": "arn:aws:s3:::flow-logs",
+		"log_group_name":  "",
+		"iam_role_arn":    "",
+	})
+
+	if got := fls[0].iamRoleName(); got != "flow-logs" {
+		t.Errorf("got role %s", got)
+	}
+	if got := (&FlowLog{ResourceID: aws.String("subnet-1")}).ResourceAttribute(); got != "subnet_id" {
+		t.Errorf("got attribute %s", got)
+	}
+}
@@ -0,0 +1,454 @@
+package tfit
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+//**************** Internet Gateway ****************
+
+type InternetGateway struct {
+	InternetGatewayID *string
+	VPCID             *string
+	Tags              *Tags
+
+	// Reference to the exported VPC, e.g. ${aws_vpc.main.id}
+	VPCRef string
+
+	ResourceName string
+}
+
+type InternetGateways []*InternetGateway
+
+func (igw *InternetGateway) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_internet_gateway",
+		ID:   aws.StringValue(igw.InternetGatewayID),
+		Tags: igw.Tags.values(),
+	}
+}
+
+func (igws *InternetGateways) addToGraph(g *Graph) {
+	for _, v := range *igws {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCID))
+	}
+}
+
+func (igws *InternetGateways) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *igws {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (igws *InternetGateways) addMissingTags(tags map[string]string) {
+	for _, v := range *igws {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (c *AWSClient) GetInternetGateways() (*InternetGateways, error) {
+	return c.GetInternetGatewaysWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetInternetGatewaysWithContext(ctx aws.Context) (*InternetGateways, error) {
+	out, err := c.ec2conn.DescribeInternetGatewaysWithContext(ctx, &ec2.DescribeInternetGatewaysInput{})
+	if err != nil {
+		return nil, fmt.Errorf("Error describing internet gateways: %s", err)
+	}
+
+	res := InternetGateways{}
+	for _, v := range out.InternetGateways {
+		igw := &InternetGateway{
+			InternetGatewayID: v.InternetGatewayId,
+			Tags:              &Tags{},
+		}
+		igw.Tags.setTags(v.Tags)
+		for _, a := range v.Attachments {
+			igw.VPCID = a.VpcId
+		}
+		igw.VPCRef = c.namer.reference("aws_vpc", igw.VPCID)
+
+		igw.ResourceName = c.namer.Name(igw.nameInfo())
+		res = append(res, igw)
+	}
+
+	return &res, nil
+}
+
+func (igws *InternetGateways) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_internet_gateway" "{{ .ResourceName }}" {
+    {{- if .VPCRef }}
+    vpc_id = "{{ .VPCRef }}"
+    {{- else if .VPCID }}
+    vpc_id = "{{ .VPCID }}"
+    {{- end }}
+    {{- if .Tags }}
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, igws)
+}
+
+//**************** NAT Gateway ****************
+
+type NATGateway struct {
+	NATGatewayID *string
+	AllocationID *string
+	SubnetID     *string
+	Tags         *Tags
+
+	// Reference to the exported subnet, e.g. ${aws_subnet.public.id}
+	SubnetRef string
+
+	ResourceName string
+}
+
+type NATGateways []*NATGateway
+
+func (nat *NATGateway) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_nat_gateway",
+		ID:   aws.StringValue(nat.NATGatewayID),
+		Tags: nat.Tags.values(),
+	}
+}
+
+func (nats *NATGateways) addToGraph(g *Graph) {
+	for _, v := range *nats {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "subnet", "aws_subnet", aws.StringValue(v.SubnetID))
+		g.addEdge(n, "allocation", "aws_eip", aws.StringValue(v.AllocationID))
+	}
+}
+
+func (nats *NATGateways) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *nats {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (nats *NATGateways) addMissingTags(tags map[string]string) {
+	for _, v := range *nats {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (c *AWSClient) GetNATGateways() (*NATGateways, error) {
+	return c.GetNATGatewaysWithContext(aws.BackgroundContext())
+}
+
+// GetNATGatewaysWithContext returns the NAT gateways which are
+// pending or available
+func (c *AWSClient) GetNATGatewaysWithContext(ctx aws.Context) (*NATGateways, error) {
+	res := NATGateways{}
+	opt := &ec2.DescribeNatGatewaysInput{
+		Filter: []*ec2.Filter{{
+			Name:   aws.String("state"),
+			Values: aws.StringSlice([]string{ec2.NatGatewayStatePending, ec2.NatGatewayStateAvailable}),
+		}},
+	}
+	for {
+		out, err := c.ec2conn.DescribeNatGatewaysWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing NAT gateways: %s", err)
+		}
+
+		for _, v := range out.NatGateways {
+			nat := &NATGateway{
+				NATGatewayID: v.NatGatewayId,
+				SubnetID:     v.SubnetId,
+				Tags:         &Tags{},
+			}
+			nat.Tags.setTags(v.Tags)
+			for _, a := range v.NatGatewayAddresses {
+				nat.AllocationID = a.AllocationId
+			}
+			nat.SubnetRef = c.namer.reference("aws_subnet", nat.SubnetID)
+
+			nat.ResourceName = c.namer.Name(nat.nameInfo())
+			res = append(res, nat)
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+func (nats *NATGateways) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_nat_gateway" "{{ .ResourceName }}" {
+    {{- if .AllocationID }}
+    allocation_id = "{{ .AllocationID }}"
+    {{- end }}
+    {{- if .SubnetRef }}
+    subnet_id = "{{ .SubnetRef }}"
+    {{- else }}
+    subnet_id = "{{ .SubnetID }}"
+    {{- end }}
+    {{- if .Tags }}
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, nats)
+}
+
+//**************** Egress Only Internet Gateway ****************
+
+type EgressOnlyInternetGateway struct {
+	EgressOnlyInternetGatewayID *string
+	VPCID                       *string
+
+	// Reference to the exported VPC, e.g. ${aws_vpc.main.id}
+	VPCRef string
+
+	ResourceName string
+}
+
+type EgressOnlyInternetGateways []*EgressOnlyInternetGateway
+
+func (eigw *EgressOnlyInternetGateway) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_egress_only_internet_gateway",
+		ID:   aws.StringValue(eigw.EgressOnlyInternetGatewayID),
+	}
+}
+
+func (eigws *EgressOnlyInternetGateways) addToGraph(g *Graph) {
+	for _, v := range *eigws {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCID))
+	}
+}
+
+func (c *AWSClient) GetEgressOnlyInternetGateways() (*EgressOnlyInternetGateways, error) {
+	return c.GetEgressOnlyInternetGatewaysWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetEgressOnlyInternetGatewaysWithContext(ctx aws.Context) (*EgressOnlyInternetGateways, error) {
+	res := EgressOnlyInternetGateways{}
+	opt := &ec2.DescribeEgressOnlyInternetGatewaysInput{}
+	for {
+		out, err := c.ec2conn.DescribeEgressOnlyInternetGatewaysWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing egress only internet gateways: %s", err)
+		}
+
+		for _, v := range out.EgressOnlyInternetGateways {
+			eigw := &EgressOnlyInternetGateway{
+				EgressOnlyInternetGatewayID: v.EgressOnlyInternetGatewayId,
+			}
+			for _, a := range v.Attachments {
+				eigw.VPCID = a.VpcId
+			}
+
+			// Terraform can't create a gateway without VPC
+			if eigw.VPCID == nil {
+				continue
+			}
+			eigw.VPCRef = c.namer.reference("aws_vpc", eigw.VPCID)
+
+			eigw.ResourceName = c.namer.Name(eigw.nameInfo())
+			res = append(res, eigw)
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+func (eigws *EgressOnlyInternetGateways) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_egress_only_internet_gateway" "{{ .ResourceName }}" {
+    {{- if .VPCRef }}
+    vpc_id = "{{ .VPCRef }}"
+    {{- else }}
+    vpc_id = "{{ .VPCID }}"
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, eigws)
+}
+
+//**************** Elastic IP ****************
+
+type EIP struct {
+	AllocationID       *string
+	PublicIP           *string
+	VPC                bool
+	InstanceID         *string
+	NetworkInterfaceID *string
+	PrivateIP          *string
+	Tags               *Tags
+
+	ResourceName string
+}
+
+type EIPs []*EIP
+
+// id is the allocation id in a VPC, the public IP in EC2-Classic
+func (eip *EIP) id() string {
+	if eip.VPC {
+		return aws.StringValue(eip.AllocationID)
+	}
+
+	return aws.StringValue(eip.PublicIP)
+}
+
+func (eip *EIP) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_eip",
+		ID:   eip.id(),
+		Name: aws.StringValue(eip.PublicIP),
+		Tags: eip.Tags.values(),
+	}
+}
+
+func (eips *EIPs) addToGraph(g *Graph) {
+	for _, v := range *eips {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "instance", "aws_instance", aws.StringValue(v.InstanceID))
+		g.addEdge(n, "network_interface", "aws_network_interface", aws.StringValue(v.NetworkInterfaceID))
+	}
+}
+
+func (eips *EIPs) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *eips {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (eips *EIPs) addMissingTags(tags map[string]string) {
+	for _, v := range *eips {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (c *AWSClient) GetEIPs() (*EIPs, error) {
+	return c.GetEIPsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetEIPsWithContext(ctx aws.Context) (*EIPs, error) {
+	out, err := c.ec2conn.DescribeAddressesWithContext(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("Error describing Elastic IPs: %s", err)
+	}
+
+	res := EIPs{}
+	for _, v := range out.Addresses {
+		eip := &EIP{
+			AllocationID: v.AllocationId,
+			PublicIP:     v.PublicIp,
+			VPC:          aws.StringValue(v.Domain) == ec2.DomainTypeVpc,
+			Tags:         &Tags{},
+		}
+		eip.Tags.setTags(v.Tags)
+
+		// Terraform associates with either the instance or the ENI
+		if v.InstanceId != nil {
+			eip.InstanceID = v.InstanceId
+		} else {
+			eip.NetworkInterfaceID = v.NetworkInterfaceId
+		}
+		if eip.VPC {
+			eip.PrivateIP = v.PrivateIpAddress
+		}
+
+		eip.ResourceName = c.namer.Name(eip.nameInfo())
+		res = append(res, eip)
+	}
+
+	return &res, nil
+}
+
+func (eips *EIPs) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_eip" "{{ .ResourceName }}" {
+    {{- if .VPC }}
+    vpc = true
+    {{- end }}
+    {{- if .InstanceID }}
+    instance = "{{ .InstanceID }}"
+    {{- end }}
+    {{- if .NetworkInterfaceID }}
+    network_interface = "{{ .NetworkInterfaceID }}"
+    {{- end }}
+    {{- if .PrivateIP }}
+    associate_with_private_ip = "{{ .PrivateIP }}"
+    {{- end }}
+    {{- if .Tags }}
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, eips)
+}
@@ -0,0 +1,129 @@
+package tfit
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestGatewaysWriteHCL(t *testing.T) {
+	cases := []struct {
+		name  string
+		col   Collection
+		attrs []map[string]string
+	}{
+		{
+			name: "internet gateways",
+			col: &InternetGateways{
+				{InternetGatewayID: aws.String("igw-1"), VPCID: aws.String("vpc-1"), Tags: &Tags{"Name": aws.String("main")}, ResourceName: "main"},
+				{InternetGatewayID: aws.String("igw-2"), ResourceName: "igw-2"},
+			},
+			attrs: []map[string]string{
+				{"vpc_id": "vpc-1", "tags.Name": "main"},
+				{"vpc_id": "", "tags.Name": ""},
+			},
+		},
+		{
+			name: "NAT gateways",
+			col: &NATGateways{
+				{NATGatewayID: aws.String("nat-1"), AllocationID: aws.String("eipalloc-1"), SubnetID: aws.String("subnet-1"), ResourceName: "nat-1"},
+				{NATGatewayID: aws.String("nat-2"), SubnetID: aws.String("subnet-2"), ResourceName: "nat-2"},
+			},
+			attrs: []map[string]string{
+				{"allocation_id": "eipalloc-1", "subnet_id": "subnet-1"},
+				{"allocation_id": "", "subnet_id": "subnet-2"},
+			},
+		},
+		{
+			name: "egress only internet gateways",
+			col: &EgressOnlyInternetGateways{
+				{EgressOnlyInternetGatewayID: aws.String("eigw-1"), VPCID: aws.String("vpc-1"), ResourceName: "eigw-1"},
+			},
+			attrs: []map[string]string{
+				{"vpc_id": "vpc-1"},
+			},
+		},
+		{
+			name: "EIPs",
+			col: &EIPs{
+				{AllocationID: aws.String("eipalloc-1"), PublicIP: aws.String("1.2.3.4"), VPC: true, InstanceID: aws.String("i-1"), PrivateIP: aws.String("10.0.0.10"), ResourceName: "web"},
+				{AllocationID: aws.String("eipalloc-2"), VPC: true, NetworkInterfaceID: aws.String("eni-1"), ResourceName: "eni"},
+				{PublicIP: aws.String("5.6.7.8"), ResourceName: "classic"},
+			},
+			attrs: []map[string]string{
+				{"vpc": "true", "instance": "i-1", "associate_with_private_ip": "10.0.0.10", "network_interface": ""},
+				{"vpc": "true", "network_interface": "eni-1", "instance": ""},
+				{"vpc": "", "instance": ""},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rs := mustRenderHCL(t, c.col)
+			if len(rs) != len(c.attrs) {
+				t.Fatalf("got %v", addresses(rs))
+			}
+			for i, want := range c.attrs {
+				checkAttributes(t, rs[i], want)
+			}
+		})
+	}
+}
+
+func TestGatewaysReferences(t *testing.T) {
+	namer, err := NewNamer(NamingNameTag, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	namer.Name(NameInfo{Type: "aws_vpc", ID: "vpc-1", Tags: map[string]string{"Name": "main"}})
+	namer.Name(NameInfo{Type: "aws_subnet", ID: "subnet-1", Tags: map[string]string{"Name": "public"}})
+
+	cols := []Collection{
+		&InternetGateways{
+			{InternetGatewayID: aws.String("igw-1"), VPCID: aws.String("vpc-1"), VPCRef: namer.reference("aws_vpc", aws.String("vpc-1")), ResourceName: "main"},
+			{InternetGatewayID: aws.String("igw-2"), VPCID: aws.String("vpc-2"), VPCRef: namer.reference("aws_vpc", aws.String("vpc-2")), ResourceName: "other"},
+		},
+		&NATGateways{
+			{NATGatewayID: aws.String("nat-1"), SubnetID: aws.String("subnet-1"), SubnetRef: namer.reference("aws_subnet", aws.String("subnet-1")), ResourceName: "nat-1"},
+		},
+		&EgressOnlyInternetGateways{
+			{EgressOnlyInternetGatewayID: aws.String("eigw-1"), VPCID: aws.String("vpc-1"), VPCRef: namer.reference("aws_vpc", aws.String("vpc-1")), ResourceName: "eigw-1"},
+		},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	for _, col := range cols {
+		mustRenderHCL(t, col)
+		if err := col.WriteHCL(buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, want := range []string{
+		`vpc_id = "${aws_vpc.main.id}"`,
+		`vpc_id = "vpc-2"`,
+		`subnet_id = "${aws_subnet.public.id}"`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("missing %s:\n%s", want, buf.String())
+		}
+	}
+	if got := strings.Count(buf.String(), "${aws_vpc.main.id}"); got != 2 {
+		t.Errorf("got %d references to the VPC", got)
+	}
+}
+
+func TestEIPID(t *testing.T) {
+	eips := []*EIP{
+		{AllocationID: aws.String("eipalloc-1"), PublicIP: aws.String("1.2.3.4"), VPC: true},
+		{PublicIP: aws.String("5.6.7.8")},
+	}
+
+	got := []string{eips[0].id(), eips[1].id()}
+	if want := []string{"eipalloc-1", "5.6.7.8"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
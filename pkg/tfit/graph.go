@@ -0,0 +1,227 @@
+package tfit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// GraphNode is a resource of the graph. External nodes are resources
+// referenced by the exported ones but not part of the export.
+type GraphNode struct {
+	Address  string `json:"address"`
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	External bool   `json:"external,omitempty"`
+}
+
+// GraphEdge goes from a resource to a resource it depends on,
+// e.g. from an instance to its subnet
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// Graph is the dependency graph of the exported resources
+type Graph struct {
+	Nodes []*GraphNode `json:"nodes"`
+	Edges []*GraphEdge `json:"edges"`
+
+	namer   *Namer
+	nodes   map[string]*GraphNode
+	edges   map[GraphEdge]bool
+	elbDNS  map[string]string // DNS name -> ELB name
+	aliases []graphAlias
+}
+
+type graphAlias struct {
+	from    string
+	dnsName string
+}
+
+// grapher is implemented by the collections which know their dependencies
+type grapher interface {
+	addToGraph(g *Graph)
+}
+
+func newGraph(namer *Namer) *Graph {
+	return &Graph{
+		Nodes:  []*GraphNode{},
+		Edges:  []*GraphEdge{},
+		namer:  namer,
+		nodes:  make(map[string]*GraphNode),
+		edges:  make(map[GraphEdge]bool),
+		elbDNS: make(map[string]string),
+	}
+}
+
+// GetGraph builds the graph of every resource (or of the given types)
+func (c *AWSClient) GetGraph(types ...string) (*Graph, error) {
+	return c.GetGraphWithContext(aws.BackgroundContext(), types...)
+}
+
+func (c *AWSClient) GetGraphWithContext(ctx aws.Context, types ...string) (*Graph, error) {
+	cols, err := c.GetAllWithContext(ctx, types...)
+	if err != nil {
+		return nil, err
+	}
+
+	g := newGraph(c.namer)
+	for _, col := range cols {
+		if v, ok := col.(grapher); ok {
+			v.addToGraph(g)
+		}
+	}
+	g.resolveAliases()
+
+	return g, nil
+}
+
+// addNode adds an exported resource and returns its address
+func (g *Graph) addNode(info NameInfo) string {
+	address := info.Type + "." + g.namer.Name(info)
+	if _, ok := g.nodes[address]; !ok {
+		n := &GraphNode{Address: address, Type: info.Type, ID: info.ID}
+		g.nodes[address] = n
+		g.Nodes = append(g.Nodes, n)
+	}
+
+	return address
+}
+
+// addEdge adds an edge from 'from' to the resource of the given type & id
+func (g *Graph) addEdge(from, kind, resourceType, id string) {
+	if len(id) == 0 || len(resourceType) == 0 {
+		return
+	}
+
+	name, ok := g.namer.Lookup(resourceType, id)
+	if !ok {
+		// The resource may be exported as another type, e.g. aws_default_*
+		for t, alias := range stateTypeAliases {
+			if alias != resourceType {
+				continue
+			}
+			if n, found := g.namer.Lookup(t, id); found {
+				name, ok, resourceType = n, true, t
+				break
+			}
+		}
+	}
+
+	var address string
+	if ok {
+		address = resourceType + "." + name
+		if _, ok := g.nodes[address]; !ok {
+			g.addNode(NameInfo{Type: resourceType, ID: id})
+		}
+	} else {
+		address = resourceType + "." + externalName(id)
+		if _, ok := g.nodes[address]; !ok {
+			n := &GraphNode{Address: address, Type: resourceType, ID: id, External: true}
+			g.nodes[address] = n
+			g.Nodes = append(g.Nodes, n)
+		}
+	}
+
+	e := GraphEdge{From: from, To: address, Kind: kind}
+	if g.edges[e] || from == address {
+		return
+	}
+	g.edges[e] = true
+	g.Edges = append(g.Edges, &e)
+}
+
+func (g *Graph) addEdges(from, kind, resourceType string, ids []*string) {
+	for _, id := range ids {
+		g.addEdge(from, kind, resourceType, aws.StringValue(id))
+	}
+}
+
+// externalName turns the id of a resource tfit didn't export
+// (possibly an ARN) into a resource name
+func externalName(id string) string {
+	if strings.HasPrefix(id, "arn:") {
+		parts := strings.SplitN(id, ":", 6)
+		id = parts[len(parts)-1]
+	}
+
+	return makeTerraformResourceName(aws.String(id))
+}
+
+func normalizeDNSName(name string) string {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	return strings.TrimPrefix(name, "dualstack.")
+}
+
+// resolveAliases links the Route53 alias records to the ELBs they target
+func (g *Graph) resolveAliases() {
+	for _, a := range g.aliases {
+		if name, ok := g.elbDNS[normalizeDNSName(a.dnsName)]; ok {
+			g.addEdge(a.from, "alias", "aws_elb", name)
+		}
+	}
+	g.aliases = nil
+}
+
+// Resource type of the target of a route, by id prefix
+var routeTargetTypes = []struct {
+	prefix       string
+	resourceType string
+}{
+	{"igw-", "aws_internet_gateway"},
+	{"vgw-", "aws_vpn_gateway"},
+	{"vpce-", "aws_vpc_endpoint"},
+	{"nat-", "aws_nat_gateway"},
+	{"eigw-", "aws_egress_only_internet_gateway"},
+	{"pcx-", "aws_vpc_peering_connection"},
+	{"tgw-", "aws_ec2_transit_gateway"},
+	{"eni-", "aws_network_interface"},
+	{"i-", "aws_instance"},
+}
+
+func routeTargetType(id string) string {
+	for _, v := range routeTargetTypes {
+		if strings.HasPrefix(id, v.prefix) {
+			return v.resourceType
+		}
+	}
+
+	return ""
+}
+
+// WriteJSON writes the nodes & edges as JSON
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes the graph in the Graphviz format
+func (g *Graph) WriteDOT(w io.Writer) error {
+	lines := []string{"digraph tfit {", "  rankdir=LR;", "  node [shape=box];"}
+
+	nodes := make([]*GraphNode, len(g.Nodes))
+	copy(nodes, g.Nodes)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Address < nodes[j].Address })
+	for _, n := range nodes {
+		attrs := fmt.Sprintf("label=%q", n.Address+"\n"+n.ID)
+		if n.External {
+			attrs += ", style=dashed"
+		}
+		lines = append(lines, fmt.Sprintf("  %q [%s];", n.Address, attrs))
+	}
+
+	for _, e := range g.Edges {
+		lines = append(lines, fmt.Sprintf("  %q -> %q [label=%q];", e.From, e.To, e.Kind))
+	}
+	lines = append(lines, "}")
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
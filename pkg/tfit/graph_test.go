@@ -0,0 +1,161 @@
+package tfit
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestGraph(t *testing.T) {
+	namer, err := NewNamer(NamingNameTag, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := newGraph(namer)
+	cols := []grapher{
+		&Subnets{{SubnetId: aws.String("subnet-1"), VPCId: aws.String("vpc-1")}},
+		&Instances{{
+			InstanceID:     aws.String("i-1"),
+			SubnetID:       aws.String("subnet-1"),
+			VpcID:          aws.String("vpc-1"),
+			SecurityGroups: aws.StringSlice([]string{"sg-1", "sg-1"}),
+		}},
+		&ELBs{{
+			Name:      aws.String("web"),
+			DNSName:   aws.String("web-123.eu-west-1.elb.amazonaws.com"),
+			Instances: aws.StringSlice([]string{"i-1"}),
+		}},
+		&RecordSets{{
+			Name:   aws.String("www.example.com."),
+			ZoneId: aws.String("Z1"),
+			Type:   aws.String("A"),
+			Alias:  &RecordAlias{Name: aws.String("dualstack.WEB-123.eu-west-1.elb.amazonaws.com.")},
+		}},
+	}
+	for _, col := range cols {
+		col.addToGraph(g)
+	}
+	g.resolveAliases()
+
+	nodes := []string{}
+	for _, n := range g.Nodes {
+		s := n.Address
+		if n.External {
+			s += " (external)"
+		}
+		nodes = append(nodes, s)
+	}
+	sort.Strings(nodes)
+
+	wantNodes := []string{
+		"aws_elb.web",
+		"aws_instance.i-1",
+		"aws_route53_record.www-example-com-A",
+		"aws_route53_zone.Z1 (external)",
+		"aws_security_group.sg-1 (external)",
+		"aws_subnet.subnet-1",
+		"aws_vpc.vpc-1 (external)",
+	}
+	if !reflect.DeepEqual(nodes, wantNodes) {
+		t.Errorf("nodes: got %v, want %v", nodes, wantNodes)
+	}
+
+	edges := []string{}
+	for _, e := range g.Edges {
+		edges = append(edges, e.From+" -"+e.Kind+"-> "+e.To)
+	}
+	sort.Strings(edges)
+
+	wantEdges := []string{
+		"aws_elb.web -instance-> aws_instance.i-1",
+		"aws_instance.i-1 -security_group-> aws_security_group.sg-1",
+		"aws_instance.i-1 -subnet-> aws_subnet.subnet-1",
+		"aws_instance.i-1 -vpc-> aws_vpc.vpc-1",
+		"aws_route53_record.www-example-com-A -alias-> aws_elb.web",
+		"aws_route53_record.www-example-com-A -zone-> aws_route53_zone.Z1",
+		"aws_subnet.subnet-1 -vpc-> aws_vpc.vpc-1",
+	}
+	if !reflect.DeepEqual(edges, wantEdges) {
+		t.Errorf("edges: got %v, want %v", edges, wantEdges)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := g.WriteDOT(buf); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	for _, s := range []string{
+		"digraph tfit {",
+		`"aws_vpc.vpc-1" [label="aws_vpc.vpc-1\nvpc-1", style=dashed];`,
+		`"aws_elb.web" -> "aws_instance.i-1" [label="instance"];`,
+	} {
+		if !strings.Contains(dot, s) {
+			t.Errorf("DOT output is missing %s:\n%s", s, dot)
+		}
+	}
+}
+
+func TestRouteTargetType(t *testing.T) {
+	cases := []struct {
+		id   string
+		want string
+	}{
+		{"igw-1", "aws_internet_gateway"},
+		{"eigw-1", "aws_egress_only_internet_gateway"},
+		{"i-1", "aws_instance"},
+		{"local", ""},
+	}
+
+	for _, c := range cases {
+		if got := routeTargetType(c.id); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.id, got, c.want)
+		}
+	}
+}
+
+func TestExternalName(t *testing.T) {
+	cases := []struct {
+		id   string
+		want string
+	}{
+		{"vpc-1", "vpc-1"},
+		{"arn:aws:iam::123456789012:role/app", "role-app"},
+		{"arn:aws:s3:::logs", "logs"},
+	}
+
+	for _, c := range cases {
+		if got := externalName(c.id); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.id, got, c.want)
+		}
+	}
+}
+
+func TestGraphDefaultResources(t *testing.T) {
+	namer, err := NewNamer(NamingID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := newGraph(namer)
+	(&SecurityGroups{{GroupId: aws.String("sg-0"), Name: aws.String("default"), VPCId: aws.String("vpc-1"), Default: true}}).addToGraph(g)
+	(&Instances{{InstanceID: aws.String("i-1"), SecurityGroups: aws.StringSlice([]string{"sg-0"})}}).addToGraph(g)
+
+	edges := []string{}
+	for _, e := range g.Edges {
+		edges = append(edges, e.From+" -"+e.Kind+"-> "+e.To)
+	}
+	sort.Strings(edges)
+
+	want := []string{
+		"aws_default_security_group.sg-0 -vpc-> aws_vpc.vpc-1",
+		"aws_instance.i-1 -security_group-> aws_default_security_group.sg-0",
+	}
+	if !reflect.DeepEqual(edges, want) {
+		t.Errorf("got %v, want %v", edges, want)
+	}
+}
@@ -2,6 +2,7 @@ package tfit
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,10 +10,12 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
+	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/sts"
@@ -25,11 +28,6 @@ type ResourceTag struct {
 	Value *string
 }
 
-type chanItem struct {
-	obj interface{}
-	err error
-}
-
 type Tags map[string]*string
 
 func (t *Tags) setTags(src []*ec2.Tag) {
@@ -39,15 +37,20 @@ func (t *Tags) setTags(src []*ec2.Tag) {
 }
 
 func (c *Config) GetAccountId() (*string, error) {
+	return c.GetAccountIdWithContext(aws.BackgroundContext())
+}
+
+func (c *Config) GetAccountIdWithContext(ctx aws.Context) (*string, error) {
 	creds := GetCredentials(c)
-	sess, err := session.NewSession(&aws.Config{Credentials: creds})
+	sess, err := session.NewSession(request.WithRetryer(&aws.Config{Credentials: creds}, newRetryer(c)))
 	if err != nil {
 		return nil, fmt.Errorf("Error creating AWS session: %s", err)
 	}
 
 	stsconn := sts.New(sess, aws.NewConfig().WithRegion(c.Region))
+	setRateLimit(stsconn.Client, c.RateLimits)
 
-	output, err := stsconn.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	output, err := stsconn.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
 	if err != nil {
 		return nil, fmt.Errorf("Error calling GetCallerIdentity: %s", err)
 	}
@@ -95,10 +98,23 @@ func GetCredentials(c *Config) *credentials.Credentials {
 	return credentials.NewChainCredentials(providers)
 }
 
+// makeTerraformResourceName turns any string into a valid Terraform
+// resource name: runs of characters other than ASCII letters, digits
+// and '_' become a single '-' and the name never starts with a digit
 func makeTerraformResourceName(src *string) string {
-	output := aws.StringValue(src)
-	for _, v := range "._:/ " {
-		output = strings.Replace(output, string(v), "-", -1)
+	buf := bytes.NewBuffer(nil)
+	for _, r := range aws.StringValue(src) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			buf.WriteRune(r)
+		case buf.Len() > 0 && !strings.HasSuffix(buf.String(), "-"):
+			buf.WriteByte('-')
+		}
+	}
+
+	output := strings.TrimSuffix(buf.String(), "-")
+	if len(output) == 0 || (output[0] >= '0' && output[0] <= '9') {
+		output = "_" + output
 	}
 
 	return output
@@ -175,6 +191,68 @@ func unEscapeHTML(src *string) (string, error) {
 	return url.QueryUnescape(aws.StringValue(src))
 }
 
+// chunkStrings splits ids in chunks of at most 'size' ids
+func chunkStrings(ids []*string, size int) [][]*string {
+	var res [][]*string
+	for len(ids) > size {
+		res = append(res, ids[:size])
+		ids = ids[size:]
+	}
+	if len(ids) > 0 {
+		res = append(res, ids)
+	}
+
+	return res
+}
+
+// hclQuote quotes a string with the escapes every HCL version reads:
+// \\, \", \n, \t and \uXXXX for the other control characters
+func hclQuote(s string) string {
+	buf := bytes.NewBufferString(`"`)
+	for _, r := range s {
+		switch {
+		case r == '\\':
+			buf.WriteString(`\\`)
+		case r == '"':
+			buf.WriteString(`\"`)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(buf, `\u%04x`, r)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteString(`"`)
+
+	return buf.String()
+}
+
+// userDataHCL renders the base64 user data of an instance: a heredoc
+// (or a string when there's no final newline, which a heredoc would add)
+// with the interpolations escaped, or user_data_base64 for binary data
+func userDataHCL(src *string) string {
+	b, err := base64.StdEncoding.DecodeString(aws.StringValue(src))
+	if err != nil || !utf8.Valid(b) {
+		return fmt.Sprintf("user_data_base64 = %q", aws.StringValue(src))
+	}
+
+	data := strings.Replace(string(b), "${", "$${", -1)
+	if !strings.HasSuffix(data, "\n") {
+		return "user_data = " + hclQuote(data)
+	}
+
+	// The delimiter can't be a line of the user data
+	delimiter := "USERDATA"
+	for strings.Contains("\n"+data, "\n"+delimiter+"\n") {
+		delimiter += "_"
+	}
+
+	return fmt.Sprintf("user_data = <<%s\n%s%s", delimiter, data, delimiter)
+}
+
 func doHCLRendering(w io.Writer, t *template.Template, target interface{}) error {
 	buf := bytes.NewBuffer(nil)
 	err := t.Execute(buf, target)
@@ -182,7 +260,12 @@ func doHCLRendering(w io.Writer, t *template.Template, target interface{}) error
 		return err
 	}
 
-	return HCLFmt(buf, w)
+	hclFile, err := parser.Parse(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return printer.Fprint(w, hclFile.Node)
 }
 
 func renderHCL(w io.Writer, Tmpl string, funcMap template.FuncMap, target interface{}) error {
@@ -0,0 +1,151 @@
+package tfit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// newTestClient returns a client whose EC2, STS & Auto Scaling calls are served by 'h'
+func newTestClient(t *testing.T, h http.HandlerFunc) *AWSClient {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		Endpoint:    aws.String(srv.URL),
+		Region:      aws.String("eu-west-1"),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namer, err := NewNamer(NamingID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &AWSClient{
+		ec2conn:  ec2.New(sess),
+		stsconn:  sts.New(sess),
+		asconn:   autoscaling.New(sess),
+		region:   "eu-west-1",
+		pool:     newWorkerPool(1),
+		namer:    namer,
+		progress: newProgress(),
+	}
+}
+
+// mustRenderHCL renders a collection and parses the resources back
+func mustRenderHCL(t *testing.T, col Collection) Resources {
+	t.Helper()
+	buf := bytes.NewBuffer(nil)
+	if err := col.WriteHCL(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	src := buf.String()
+	if strings.Contains(src, "<nil>") {
+		t.Errorf("rendered a nil value:\n%s", src)
+	}
+	if err := HCLFmt(strings.NewReader(src), ioutil.Discard); err != nil {
+		t.Fatalf("%s\n%s", err, src)
+	}
+
+	return mustParseResources(t, src)
+}
+
+// checkAttributes compares the attributes of a resource with 'want',
+// where an empty value means the attribute must not be set
+func checkAttributes(t *testing.T, r *Resource, want map[string]string) {
+	t.Helper()
+	for k, v := range want {
+		got, ok := r.Attributes[k]
+		switch {
+		case len(v) == 0 && ok:
+			t.Errorf("%s: got %s = %v, want it unset", r.Address(), k, got)
+		case len(v) > 0 && fmt.Sprint(got) != v:
+			t.Errorf("%s: got %s = %v, want %s", r.Address(), k, got, v)
+		}
+	}
+}
+
+func TestUserDataHCL(t *testing.T) {
+	b64 := func(s string) *string {
+		return aws.String(base64.StdEncoding.EncodeToString([]byte(s)))
+	}
+
+	cases := []struct {
+		name string
+		src  *string
+		want string
+	}{
+		{"heredoc", b64("#!/bin/sh\necho ${HOME}\n"), "user_data = <<USERDATA\n#!/bin/sh\necho $${HOME}\nUSERDATA"},
+		{"delimiter in data", b64("USERDATA\n"), "user_data = <<USERDATA_\nUSERDATA\nUSERDATA_"},
+		{"no final newline", b64(`echo "hi"`), `user_data = "echo \"hi\""`},
+		{"escapes", b64("a\\b\tc\r\nd\x1b[0mé"), `user_data = "a\\b\tc\u000d\nd\u001b[0mé"`},
+		{"binary", b64("\x1f\x8b\x08\x00\xff"), `user_data_base64 = "H4sIAP8="`},
+		{"not base64", aws.String("#!"), `user_data_base64 = "#!"`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := userDataHCL(c.src)
+			if got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+			if err := HCLFmt(strings.NewReader(got+"\n"), ioutil.Discard); err != nil {
+				t.Errorf("%s: %q", err, got)
+			}
+		})
+	}
+
+	// The quoted user data reads back as it is
+	src := "a\\b\tc\r\nd\x1b[0mé"
+	rs := mustParseResources(t, `resource "aws_instance" "web" { `+userDataHCL(b64(src))+` }`)
+	if got := rs[0].Attributes["user_data"]; got != src {
+		t.Errorf("got %q, want %q", got, src)
+	}
+}
+
+func TestChunkStrings(t *testing.T) {
+	ids := aws.StringSlice([]string{"a", "b", "c", "d", "e"})
+
+	cases := []struct {
+		size int
+		want [][]string
+	}{
+		{2, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}},
+		{5, [][]string{{"a", "b", "c", "d", "e"}}},
+		{10, [][]string{{"a", "b", "c", "d", "e"}}},
+	}
+
+	for _, c := range cases {
+		got := [][]string{}
+		for _, chunk := range chunkStrings(ids, c.size) {
+			got = append(got, aws.StringValueSlice(chunk))
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%d: got %v, want %v", c.size, got, c.want)
+		}
+	}
+
+	if got := chunkStrings(nil, 2); len(got) != 0 {
+		t.Errorf("got %v for no ids", got)
+	}
+}
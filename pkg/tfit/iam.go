@@ -1,7 +1,6 @@
 package tfit
 
 import (
-	"fmt"
 	"io"
 	"text/template"
 
@@ -18,12 +17,33 @@ type Policy struct {
 	PolicyName       *string
 	Document         *string
 	DefaultVersionId *string
+	AttachmentCount  *int64
+
+	ResourceName string
 }
 
 type Policies []*Policy
 
+func (p *Policy) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_iam_policy",
+		ID:   aws.StringValue(p.Arn),
+		Name: aws.StringValue(p.PolicyName),
+	}
+}
+
+func (p *Policies) addToGraph(g *Graph) {
+	for _, v := range *p {
+		g.addNode(v.nameInfo())
+	}
+}
+
 func (c *AWSClient) GetPolicy(p *Policy) error {
-	out, err := c.iamconn.GetPolicy(&iam.GetPolicyInput{PolicyArn: p.Arn})
+	return c.GetPolicyWithContext(aws.BackgroundContext(), p)
+}
+
+func (c *AWSClient) GetPolicyWithContext(ctx aws.Context, p *Policy) error {
+	out, err := c.iamconn.GetPolicyWithContext(ctx, &iam.GetPolicyInput{PolicyArn: p.Arn})
 	if err != nil {
 		return err
 	}
@@ -31,12 +51,17 @@ func (c *AWSClient) GetPolicy(p *Policy) error {
 	p.DefaultVersionId = out.Policy.DefaultVersionId
 	p.Path = out.Policy.Path
 	p.PolicyName = out.Policy.PolicyName
+	p.AttachmentCount = out.Policy.AttachmentCount
 
 	return nil
 }
 
 func (c *AWSClient) GetPolicyDocument(p *Policy) error {
-	doc, err := c.iamconn.GetPolicyVersion(&iam.GetPolicyVersionInput{PolicyArn: p.Arn, VersionId: p.DefaultVersionId})
+	return c.GetPolicyDocumentWithContext(aws.BackgroundContext(), p)
+}
+
+func (c *AWSClient) GetPolicyDocumentWithContext(ctx aws.Context, p *Policy) error {
+	doc, err := c.iamconn.GetPolicyVersionWithContext(ctx, &iam.GetPolicyVersionInput{PolicyArn: p.Arn, VersionId: p.DefaultVersionId})
 	if err != nil {
 		return err
 	}
@@ -51,6 +76,12 @@ func (c *AWSClient) GetPolicyDocument(p *Policy) error {
 }
 
 func (c *AWSClient) GetPolicies() (*Policies, error) {
+	return c.GetPoliciesWithContext(aws.BackgroundContext())
+}
+
+// GetPoliciesWithContext is the same as GetPolicies with the addition of
+// the ability to pass a context for cancellation
+func (c *AWSClient) GetPoliciesWithContext(ctx aws.Context) (*Policies, error) {
 	var res Policies
 
 	opt := &iam.ListPoliciesInput{
@@ -59,42 +90,35 @@ func (c *AWSClient) GetPolicies() (*Policies, error) {
 
 	for {
 		// Get all Local managed policies
-		out, err := c.iamconn.ListPolicies(opt)
+		out, err := c.iamconn.ListPoliciesWithContext(ctx, opt)
 		if err != nil {
 			return nil, err
 		}
 
-		ch := make(chan *chanItem, len(out.Policies))
-
-		for _, v := range out.Policies {
-
-			go func(Arn *string) {
-				p := &Policy{
-					Arn: Arn,
-				}
-				err := c.GetPolicy(p)
-				if err != nil {
-					ch <- &chanItem{err: err}
-				}
-
-				err = c.GetPolicyDocument(p)
-				if err != nil {
-					ch <- &chanItem{err: err}
-				}
-
-				ch <- &chanItem{obj: p}
+		page := make([]*Policy, len(out.Policies))
+		err = c.pool.run(ctx, len(out.Policies), func(ctx aws.Context, i int) error {
+			p := &Policy{
+				Arn: out.Policies[i].Arn,
+			}
 
-			}(v.Arn)
-		}
+			if err := c.GetPolicyWithContext(ctx, p); err != nil {
+				return err
+			}
 
-		for range out.Policies {
-			receiver := <-ch
-			if receiver.err != nil {
-				return nil, err
+			if err := c.GetPolicyDocumentWithContext(ctx, p); err != nil {
+				return err
 			}
 
-			res = append(res, receiver.obj.(*Policy))
+			page[i] = p
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range page {
+			v.ResourceName = c.namer.Name(v.nameInfo())
 		}
+		res = append(res, page...)
 
 		// Check if output was truncated
 		if aws.BoolValue(out.IsTruncated) {
@@ -113,7 +137,7 @@ func (p *Policies) WriteHCL(w io.Writer) error {
 	tmpl := `
 	{{ if . }}
     {{ range . }}
-    resource "aws_iam_policy" "{{ .PolicyName }}" {
+    resource "aws_iam_policy" "{{ .ResourceName }}" {
       name = "{{ .PolicyName }}"
       {{- if .Path }}
       path = "{{.Path }}"
@@ -140,15 +164,35 @@ type Role struct {
 	Path                     *string
 	MaxSessionDuration       *int64
 	PermissionBoundaryArn    *string
+
+	ResourceName string
 }
 
 type Roles []*Role
 
+func (r *Role) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_iam_role",
+		ID:   aws.StringValue(r.Name),
+		Name: aws.StringValue(r.Name),
+	}
+}
+
+func (r *Roles) addToGraph(g *Graph) {
+	for _, v := range *r {
+		g.addNode(v.nameInfo())
+	}
+}
+
 func (c *AWSClient) ListRoles() (*Roles, error) {
+	return c.ListRolesWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) ListRolesWithContext(ctx aws.Context) (*Roles, error) {
 	opt := iam.ListRolesInput{}
 	var output Roles
 	for {
-		data, err := c.iamconn.ListRoles(&opt)
+		data, err := c.iamconn.ListRolesWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
@@ -168,12 +212,13 @@ func (c *AWSClient) ListRoles() (*Roles, error) {
 
 			unEscapeAssumeRole, err := unEscapeHTML(tmp.AssumeRolePolicyDocument)
 			if err != nil {
-				fmt.Println(err)
+				c.logf(LevelWarn, "skipping role with an unreadable trust policy", "role", aws.StringValue(v.RoleName), "error", err)
 				continue
 			} else {
 				tmp.AssumeRolePolicyDocument = &unEscapeAssumeRole
 			}
 
+			tmp.ResourceName = c.namer.Name(tmp.nameInfo())
 			output = append(output, &tmp)
 		}
 
@@ -189,14 +234,13 @@ func (c *AWSClient) ListRoles() (*Roles, error) {
 
 func (r *Roles) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
-		"makeTerraformResourceName": makeTerraformResourceName,
-		"prettyJSON":                prettyJSON,
+		"prettyJSON": prettyJSON,
 	}
 
 	tmpl := `
 	{{ if . }}
     {{ range . }}
-    resource "aws_iam_policy" "{{ .Name | makeTerraformResourceName }}" {
+    resource "aws_iam_role" "{{ .ResourceName }}" {
       name = "{{ .Name }}"
       assume_role_policy = <<EOF
       {{ .AssumeRolePolicyDocument | prettyJSON }}
@@ -230,6 +274,8 @@ type User struct {
 	UserId                 *string
 	UserName               *string
 	PermissionsBoundaryArn *string
+
+	ResourceName string
 }
 
 func (u *User) setUser(src *iam.User) {
@@ -247,18 +293,56 @@ func (u *User) setUser(src *iam.User) {
 
 type Users []*User
 
+func (u *User) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_iam_user",
+		ID:   aws.StringValue(u.UserName),
+		Name: aws.StringValue(u.UserName),
+		Tags: u.Tags.values(),
+	}
+}
+
+func (r *Users) addToGraph(g *Graph) {
+	for _, v := range *r {
+		g.addNode(v.nameInfo())
+	}
+}
+
+func (r *Users) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *r {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (r *Users) addMissingTags(tags map[string]string) {
+	for _, v := range *r {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
 func (c *AWSClient) ListUsers() (*Users, error) {
+	return c.ListUsersWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) ListUsersWithContext(ctx aws.Context) (*Users, error) {
 	opt := iam.ListUsersInput{}
 
 	var output Users
 	for {
-		data, err := c.iamconn.ListUsers(&opt)
+		data, err := c.iamconn.ListUsersWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
 		for _, v := range data.Users {
 			var u User
 			u.setUser(v)
+			u.ResourceName = c.namer.Name(u.nameInfo())
 			output = append(output, &u)
 		}
 
@@ -273,14 +357,12 @@ func (c *AWSClient) ListUsers() (*Users, error) {
 }
 
 func (r *Users) WriteHCL(w io.Writer) error {
-	funcMap := template.FuncMap{
-		"makeTerraformResourceName": makeTerraformResourceName,
-	}
+	funcMap := template.FuncMap{}
 
 	tmpl := `
 	{{ if . }}
     {{ range . }}
-    resource "aws_iam_user" "{{ .UserName | makeTerraformResourceName }}" {
+    resource "aws_iam_user" "{{ .ResourceName }}" {
       name = "{{ .UserName }}"
       {{- if .Path }}
       path = "{{ .Path }}"
@@ -309,15 +391,35 @@ type IAMGroup struct {
 	Name *string
 	Id   *string
 	Path *string
+
+	ResourceName string
 }
 
 type IAMGroups []*IAMGroup
 
+func (g *IAMGroup) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_iam_group",
+		ID:   aws.StringValue(g.Name),
+		Name: aws.StringValue(g.Name),
+	}
+}
+
+func (g *IAMGroups) addToGraph(graph *Graph) {
+	for _, v := range *g {
+		graph.addNode(v.nameInfo())
+	}
+}
+
 func (c *AWSClient) ListIAMGroups() (*IAMGroups, error) {
+	return c.ListIAMGroupsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) ListIAMGroupsWithContext(ctx aws.Context) (*IAMGroups, error) {
 	opt := iam.ListGroupsInput{}
 	var output IAMGroups
 	for {
-		data, err := c.iamconn.ListGroups(&opt)
+		data, err := c.iamconn.ListGroupsWithContext(ctx, &opt)
 		if err != nil {
 			return nil, err
 		}
@@ -328,6 +430,7 @@ func (c *AWSClient) ListIAMGroups() (*IAMGroups, error) {
 				Id:   g.GroupId,
 				Path: g.Path,
 			}
+			tmp.ResourceName = c.namer.Name(tmp.nameInfo())
 			output = append(output, &tmp)
 		}
 
@@ -342,14 +445,12 @@ func (c *AWSClient) ListIAMGroups() (*IAMGroups, error) {
 }
 
 func (g *IAMGroups) WriteHCL(w io.Writer) error {
-	funcMap := template.FuncMap{
-		"makeTerraformResourceName": makeTerraformResourceName,
-	}
+	funcMap := template.FuncMap{}
 
 	tmpl := `
 	{{ if . }}
     {{ range . }}
-    resource "aws_iam_group" "{{ .Name | makeTerraformResourceName }}" {
+    resource "aws_iam_group" "{{ .ResourceName }}" {
       name = "{{ .Name }}"
       {{- if .Path }}
       path = "{{ .Path }}"
@@ -360,3 +461,134 @@ func (g *IAMGroups) WriteHCL(w io.Writer) error {
 	`
 	return renderHCL(w, tmpl, funcMap, g)
 }
+
+//**************** IAM Inline Policy ****************
+
+// InlinePolicy is a policy embedded in a role, a user or a group
+type InlinePolicy struct {
+	Owner      NameInfo
+	PolicyName *string
+	Document   *string
+}
+
+type InlinePolicies []*InlinePolicy
+
+// inlinePolicyLister lists and reads the inline policies of one owner
+type inlinePolicyLister struct {
+	owner NameInfo
+	list  func(ctx aws.Context, marker *string) ([]*string, *string, error)
+	get   func(ctx aws.Context, name *string) (*string, error)
+}
+
+func (c *AWSClient) GetInlinePolicies(roles *Roles, users *Users, groups *IAMGroups) (*InlinePolicies, error) {
+	return c.GetInlinePoliciesWithContext(aws.BackgroundContext(), roles, users, groups)
+}
+
+// GetInlinePoliciesWithContext returns the inline policies of the roles, users and groups
+func (c *AWSClient) GetInlinePoliciesWithContext(ctx aws.Context, roles *Roles, users *Users, groups *IAMGroups) (*InlinePolicies, error) {
+	var listers []inlinePolicyLister
+	for _, v := range *roles {
+		name := v.Name
+		listers = append(listers, inlinePolicyLister{
+			owner: v.nameInfo(),
+			list: func(ctx aws.Context, marker *string) ([]*string, *string, error) {
+				out, err := c.iamconn.ListRolePoliciesWithContext(ctx, &iam.ListRolePoliciesInput{RoleName: name, Marker: marker})
+				if err != nil {
+					return nil, nil, err
+				}
+				return out.PolicyNames, out.Marker, nil
+			},
+			get: func(ctx aws.Context, policy *string) (*string, error) {
+				out, err := c.iamconn.GetRolePolicyWithContext(ctx, &iam.GetRolePolicyInput{RoleName: name, PolicyName: policy})
+				if err != nil {
+					return nil, err
+				}
+				return out.PolicyDocument, nil
+			},
+		})
+	}
+	for _, v := range *users {
+		name := v.UserName
+		listers = append(listers, inlinePolicyLister{
+			owner: v.nameInfo(),
+			list: func(ctx aws.Context, marker *string) ([]*string, *string, error) {
+				out, err := c.iamconn.ListUserPoliciesWithContext(ctx, &iam.ListUserPoliciesInput{UserName: name, Marker: marker})
+				if err != nil {
+					return nil, nil, err
+				}
+				return out.PolicyNames, out.Marker, nil
+			},
+			get: func(ctx aws.Context, policy *string) (*string, error) {
+				out, err := c.iamconn.GetUserPolicyWithContext(ctx, &iam.GetUserPolicyInput{UserName: name, PolicyName: policy})
+				if err != nil {
+					return nil, err
+				}
+				return out.PolicyDocument, nil
+			},
+		})
+	}
+	for _, v := range *groups {
+		name := v.Name
+		listers = append(listers, inlinePolicyLister{
+			owner: v.nameInfo(),
+			list: func(ctx aws.Context, marker *string) ([]*string, *string, error) {
+				out, err := c.iamconn.ListGroupPoliciesWithContext(ctx, &iam.ListGroupPoliciesInput{GroupName: name, Marker: marker})
+				if err != nil {
+					return nil, nil, err
+				}
+				return out.PolicyNames, out.Marker, nil
+			},
+			get: func(ctx aws.Context, policy *string) (*string, error) {
+				out, err := c.iamconn.GetGroupPolicyWithContext(ctx, &iam.GetGroupPolicyInput{GroupName: name, PolicyName: policy})
+				if err != nil {
+					return nil, err
+				}
+				return out.PolicyDocument, nil
+			},
+		})
+	}
+
+	owned := make([]InlinePolicies, len(listers))
+	err := c.pool.run(ctx, len(listers), func(ctx aws.Context, i int) error {
+		l := listers[i]
+
+		var marker *string
+		for {
+			names, next, err := l.list(ctx, marker)
+			if err != nil {
+				return err
+			}
+
+			for _, name := range names {
+				doc, err := l.get(ctx, name)
+				if err != nil {
+					return err
+				}
+
+				d, err := unEscapeHTML(doc)
+				if err != nil {
+					c.logf(LevelWarn, "skipping an unreadable inline policy", "owner", l.owner.ID, "policy", aws.StringValue(name), "error", err)
+					continue
+				}
+				owned[i] = append(owned[i], &InlinePolicy{Owner: l.owner, PolicyName: name, Document: &d})
+			}
+
+			if next == nil {
+				break
+			}
+			marker = next
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res InlinePolicies
+	for _, v := range owned {
+		res = append(res, v...)
+	}
+
+	return &res, nil
+}
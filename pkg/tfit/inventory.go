@@ -0,0 +1,237 @@
+package tfit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// inventoryAttributes are the attributes worth a column
+// of the inventory, per resource type
+var inventoryAttributes = map[string][]string{
+	"aws_vpc":                                {"cidr_block", "instance_tenancy"},
+	"aws_vpc_ipv4_cidr_block_association":    {"vpc_id", "cidr_block"},
+	"aws_vpc_dhcp_options":                   {"domain_name", "domain_name_servers"},
+	"aws_vpc_dhcp_options_association":       {"vpc_id", "dhcp_options_id"},
+	"aws_flow_log":                           {"vpc_id", "subnet_id", "eni_id", "traffic_type", "log_destination_type"},
+	"aws_subnet":                             {"vpc_id", "cidr_block", "availability_zone"},
+	"aws_route_table":                        {"vpc_id"},
+	"aws_default_route_table":                {"default_route_table_id"},
+	"aws_route_table_association":            {"subnet_id", "route_table_id"},
+	"aws_main_route_table_association":       {"vpc_id", "route_table_id"},
+	"aws_route":                              {"route_table_id", "destination_cidr_block", "destination_ipv6_cidr_block"},
+	"aws_network_acl":                        {"vpc_id", "subnet_ids"},
+	"aws_default_network_acl":                {"default_network_acl_id", "subnet_ids"},
+	"aws_internet_gateway":                   {"vpc_id"},
+	"aws_nat_gateway":                        {"subnet_id", "allocation_id"},
+	"aws_egress_only_internet_gateway":       {"vpc_id"},
+	"aws_vpc_peering_connection":             {"vpc_id", "peer_vpc_id", "peer_owner_id", "peer_region"},
+	"aws_vpc_peering_connection_accepter":    {"vpc_peering_connection_id"},
+	"aws_vpc_endpoint":                       {"vpc_id", "service_name", "vpc_endpoint_type"},
+	"aws_vpc_endpoint_service":               {"acceptance_required", "network_load_balancer_arns"},
+	"aws_ec2_transit_gateway":                {"amazon_side_asn", "description"},
+	"aws_ec2_transit_gateway_vpc_attachment": {"transit_gateway_id", "vpc_id", "subnet_ids"},
+	"aws_ec2_transit_gateway_route_table":    {"transit_gateway_id"},
+	"aws_ec2_transit_gateway_route":          {"transit_gateway_route_table_id", "destination_cidr_block", "transit_gateway_attachment_id"},
+	"aws_vpn_gateway":                        {"availability_zone", "amazon_side_asn"},
+	"aws_vpn_gateway_attachment":             {"vpn_gateway_id", "vpc_id"},
+	"aws_vpn_gateway_route_propagation":      {"vpn_gateway_id", "route_table_id"},
+	"aws_customer_gateway":                   {"ip_address", "bgp_asn", "type"},
+	"aws_vpn_connection":                     {"customer_gateway_id", "vpn_gateway_id", "transit_gateway_id", "static_routes_only"},
+	"aws_vpn_connection_route":               {"vpn_connection_id", "destination_cidr_block"},
+	"aws_eip":                                {"instance", "network_interface", "associate_with_private_ip"},
+	"aws_security_group":                     {"vpc_id", "description"},
+	"aws_default_security_group":             {"vpc_id"},
+	"aws_security_group_rule":                {"type", "protocol", "from_port", "to_port", "cidr_blocks"},
+	"aws_instance":                           {"instance_type", "ami", "availability_zone", "subnet_id", "private_ip"},
+	"aws_ebs_volume":                         {"availability_zone", "size", "type", "encrypted"},
+	"aws_volume_attachment":                  {"device_name", "volume_id", "instance_id"},
+	"aws_dlm_lifecycle_policy":               {"description", "execution_role_arn", "state"},
+	"aws_elb":                                {"internal", "subnets", "availability_zones"},
+	"aws_launch_configuration":               {"image_id", "instance_type"},
+	"aws_launch_template":                    {"image_id", "instance_type", "latest_version"},
+	"aws_autoscaling_group":                  {"launch_configuration", "min_size", "max_size", "desired_capacity"},
+	"aws_iam_policy":                         {"path"},
+	"aws_iam_role":                           {"path"},
+	"aws_iam_user":                           {"path"},
+	"aws_iam_group":                          {"path"},
+	"aws_route53_zone":                       {"comment"},
+	"aws_route53_record":                     {"zone_id", "type", "ttl", "records"},
+	"aws_s3_bucket":                          {"versioning.enabled"},
+}
+
+// globalResourceTypes don't belong to a region
+var globalResourceTypes = map[string]bool{
+	"aws_iam_policy":     true,
+	"aws_iam_role":       true,
+	"aws_iam_user":       true,
+	"aws_iam_group":      true,
+	"aws_route53_zone":   true,
+	"aws_route53_record": true,
+}
+
+// InventoryItem is a row of the inventory
+type InventoryItem struct {
+	Type       string            `json:"type"`
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Region     string            `json:"region"`
+	Tags       map[string]string `json:"tags"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+type Inventory []*InventoryItem
+
+// GetInventory lists every resource (or the resources of the given types)
+func (c *AWSClient) GetInventory(types ...string) (Inventory, error) {
+	return c.GetInventoryWithContext(aws.BackgroundContext(), types...)
+}
+
+func (c *AWSClient) GetInventoryWithContext(ctx aws.Context, types ...string) (Inventory, error) {
+	resources, err := c.GetResourcesWithContext(ctx, types...)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := Inventory{}
+	for _, r := range resources {
+		item := &InventoryItem{
+			Type:       r.Type,
+			ID:         r.ID,
+			Region:     c.region,
+			Tags:       resourceTags(r),
+			Attributes: make(map[string]string),
+		}
+		if globalResourceTypes[r.Type] {
+			item.Region = "global"
+		}
+		if attrs, ok := identityAttributes[r.Type]; ok {
+			if v, ok := r.Attributes[attrs[0]]; ok {
+				item.Name = driftCanonical(r.Type, attrs[0], v)
+			}
+		}
+		for _, a := range inventoryAttributes[r.Type] {
+			if v, ok := r.Attributes[a]; ok {
+				item.Attributes[a] = inventoryValue(v)
+			}
+		}
+
+		inv = append(inv, item)
+	}
+
+	sort.SliceStable(inv, func(i, j int) bool {
+		if inv[i].Type != inv[j].Type {
+			return inv[i].Type < inv[j].Type
+		}
+		return inv[i].ID < inv[j].ID
+	})
+
+	return inv, nil
+}
+
+// resourceTags reads the "tags" block, or the "tags" list
+// of key/value maps of the autoscaling groups
+func resourceTags(r *Resource) map[string]string {
+	res := make(map[string]string)
+	for k, v := range r.Attributes {
+		if strings.HasPrefix(k, "tags.") {
+			res[strings.TrimPrefix(k, "tags.")] = fmt.Sprint(v)
+		}
+	}
+
+	if list, ok := r.Attributes["tags"].([]interface{}); ok {
+		for _, e := range list {
+			if m, ok := e.(map[string]interface{}); ok {
+				res[fmt.Sprint(m["key"])] = fmt.Sprint(m["value"])
+			}
+		}
+	}
+
+	return res
+}
+
+func inventoryValue(v interface{}) string {
+	switch v := v.(type) {
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, e := range v {
+			values[i] = inventoryValue(e)
+		}
+		return strings.Join(values, ",")
+	case map[string]interface{}:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+
+	return fmt.Sprint(v)
+}
+
+// Counts returns the number of resources per type
+func (inv Inventory) Counts() map[string]int {
+	res := make(map[string]int)
+	for _, v := range inv {
+		res[v.Type]++
+	}
+
+	return res
+}
+
+// WriteText writes the number of resources per type as a table
+func (inv Inventory) WriteText(w io.Writer) error {
+	counts := inv.Counts()
+	types := make([]string, 0, len(counts))
+	for k := range counts {
+		types = append(types, k)
+	}
+	sort.Strings(types)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tCOUNT")
+	for _, t := range types {
+		fmt.Fprintf(tw, "%s\t%d\n", t, counts[t])
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\n", len(inv))
+
+	return tw.Flush()
+}
+
+// WriteCSV writes a row per resource. Tags and attributes are
+// written as sorted "key=value" pairs separated by ";".
+func (inv Inventory) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"type", "id", "name", "region", "tags", "attributes"}); err != nil {
+		return err
+	}
+
+	for _, v := range inv {
+		if err := cw.Write([]string{v.Type, v.ID, v.Name, v.Region, joinPairs(v.Tags), joinPairs(v.Attributes)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func joinPairs(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ";")
+}
+
+// WriteJSON writes a JSON array with an object per resource
+func (inv Inventory) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(inv)
+}
@@ -0,0 +1,114 @@
+package tfit
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestResourceTags(t *testing.T) {
+	cases := []struct {
+		name  string
+		attrs map[string]interface{}
+		want  map[string]string
+	}{
+		{"none", map[string]interface{}{"cidr_block": "10.0.0.0/16"}, map[string]string{}},
+		{"block", map[string]interface{}{"tags.Name": "web", "tags.env": "prod"}, map[string]string{"Name": "web", "env": "prod"}},
+		{"autoscaling group", map[string]interface{}{"tags": []interface{}{
+			map[string]interface{}{"key": "env", "value": "prod", "propagate_at_launch": true},
+		}}, map[string]string{"env": "prod"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := resourceTags(&Resource{Attributes: c.attrs}); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestInventoryValue(t *testing.T) {
+	cases := []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{"string", "t2.micro", "t2.micro"},
+		{"bool", true, "true"},
+		{"list", []interface{}{"subnet-1", "subnet-2"}, "subnet-1,subnet-2"},
+		{"map", map[string]interface{}{"enabled": true}, `{"enabled":true}`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := inventoryValue(c.v); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func testInventory() Inventory {
+	return Inventory{
+		{Type: "aws_instance", ID: "i-1", Name: "web", Region: "eu-west-1",
+			Tags: map[string]string{"env": "prod", "Name": "web"}, Attributes: map[string]string{"instance_type": "t2.micro"}},
+		{Type: "aws_instance", ID: "i-2", Region: "eu-west-1", Tags: map[string]string{}, Attributes: map[string]string{}},
+		{Type: "aws_iam_user", ID: "bob", Name: "bob", Region: "global", Tags: map[string]string{}, Attributes: map[string]string{"path": "/"}},
+	}
+}
+
+func TestInventoryWrite(t *testing.T) {
+	cases := []struct {
+		name  string
+		write func(inv Inventory, buf *bytes.Buffer) error
+		want  string
+	}{
+		{
+			name:  "text",
+			write: func(inv Inventory, buf *bytes.Buffer) error { return inv.WriteText(buf) },
+			want: `TYPE          COUNT
+aws_iam_user  1
+aws_instance  2
+TOTAL         3
+`,
+		},
+		{
+			name:  "csv",
+			write: func(inv Inventory, buf *bytes.Buffer) error { return inv.WriteCSV(buf) },
+			want: `type,id,name,region,tags,attributes
+aws_instance,i-1,web,eu-west-1,Name=web;env=prod,instance_type=t2.micro
+aws_instance,i-2,,eu-west-1,,
+aws_iam_user,bob,bob,global,,path=/
+`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			if err := c.write(testInventory(), buf); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != c.want {
+				t.Errorf("got\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		if err := testInventory().WriteJSON(buf); err != nil {
+			t.Fatal(err)
+		}
+
+		var got Inventory
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, testInventory()) {
+			t.Errorf("got %s", buf)
+		}
+	})
+}
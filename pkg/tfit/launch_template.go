@@ -0,0 +1,382 @@
+package tfit
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// The versions of a launch template which can be exported. The vendored
+// SDK doesn't return the metadata options of the templates.
+const (
+	LaunchTemplateVersionDefault = "$Default"
+	LaunchTemplateVersionLatest  = "$Latest"
+)
+
+//**************** Launch Template ****************
+
+type LaunchTemplate struct {
+	LaunchTemplateID   *string
+	LaunchTemplateName *string
+	Tags               *Tags
+
+	// The exported version
+	VersionNumber      *int64
+	VersionDescription *string
+	*ec2.ResponseLaunchTemplateData
+
+	ResourceName string
+}
+
+type LaunchTemplates []*LaunchTemplate
+
+func (lt *LaunchTemplate) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_launch_template",
+		ID:   aws.StringValue(lt.LaunchTemplateID),
+		Name: aws.StringValue(lt.LaunchTemplateName),
+		Tags: lt.Tags.values(),
+	}
+}
+
+func (lts *LaunchTemplates) addToGraph(g *Graph) {
+	for _, v := range *lts {
+		n := g.addNode(v.nameInfo())
+		g.addEdges(n, "security_group", "aws_security_group", v.SecurityGroupIds)
+		for _, ni := range v.NetworkInterfaces {
+			g.addEdge(n, "subnet", "aws_subnet", aws.StringValue(ni.SubnetId))
+			g.addEdges(n, "security_group", "aws_security_group", ni.Groups)
+		}
+	}
+}
+
+func (lts *LaunchTemplates) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *lts {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (lts *LaunchTemplates) addMissingTags(tags map[string]string) {
+	for _, v := range *lts {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+// describeLaunchTemplatesWithContext returns the named templates without their data
+func (c *AWSClient) describeLaunchTemplatesWithContext(ctx aws.Context) (LaunchTemplates, error) {
+	res := LaunchTemplates{}
+	opt := &ec2.DescribeLaunchTemplatesInput{}
+	for {
+		out, err := c.ec2conn.DescribeLaunchTemplatesWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing launch templates: %s", err)
+		}
+
+		for _, v := range out.LaunchTemplates {
+			lt := &LaunchTemplate{
+				LaunchTemplateID:           v.LaunchTemplateId,
+				LaunchTemplateName:         v.LaunchTemplateName,
+				Tags:                       &Tags{},
+				ResponseLaunchTemplateData: &ec2.ResponseLaunchTemplateData{},
+			}
+			lt.Tags.setTags(v.Tags)
+			lt.ResourceName = c.namer.Name(lt.nameInfo())
+			res = append(res, lt)
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return res, nil
+}
+
+func (c *AWSClient) GetLaunchTemplates(version string) (*LaunchTemplates, error) {
+	return c.GetLaunchTemplatesWithContext(aws.BackgroundContext(), version)
+}
+
+// GetLaunchTemplatesWithContext returns the launch templates with the data
+// of one of their versions, LaunchTemplateVersionDefault or LaunchTemplateVersionLatest
+func (c *AWSClient) GetLaunchTemplatesWithContext(ctx aws.Context, version string) (*LaunchTemplates, error) {
+	res, err := c.describeLaunchTemplatesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.pool.run(ctx, len(res), func(ctx aws.Context, i int) error {
+		out, err := c.ec2conn.DescribeLaunchTemplateVersionsWithContext(ctx, &ec2.DescribeLaunchTemplateVersionsInput{
+			LaunchTemplateId: res[i].LaunchTemplateID,
+			Versions:         aws.StringSlice([]string{version}),
+		})
+		if err != nil {
+			return fmt.Errorf("Error describing the versions of launch template %s: %s", aws.StringValue(res[i].LaunchTemplateID), err)
+		}
+
+		for _, v := range out.LaunchTemplateVersions {
+			res[i].VersionNumber = v.VersionNumber
+			res[i].VersionDescription = v.VersionDescription
+			if v.LaunchTemplateData != nil {
+				res[i].ResponseLaunchTemplateData = v.LaunchTemplateData
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// secondaryIPv4Addresses are the private addresses of a network
+// interface, but the primary one
+func secondaryIPv4Addresses(src []*ec2.PrivateIpAddressSpecification) []*string {
+	var res []*string
+	for _, v := range src {
+		if !aws.BoolValue(v.Primary) {
+			res = append(res, v.PrivateIpAddress)
+		}
+	}
+
+	return res
+}
+
+func ipv6Addresses(src []*ec2.InstanceIpv6Address) []*string {
+	var res []*string
+	for _, v := range src {
+		res = append(res, v.Ipv6Address)
+	}
+
+	return res
+}
+
+func (lts *LaunchTemplates) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformList":      makeTerraformList,
+		"secondaryIPv4Addresses": secondaryIPv4Addresses,
+		"ipv6Addresses":          ipv6Addresses,
+	}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_launch_template" "{{ .ResourceName }}" {
+    name = "{{ .LaunchTemplateName }}"
+    {{- if .VersionDescription }}
+    description = "{{ .VersionDescription }}"
+    {{- end }}
+    {{- if .ImageId }}
+    image_id = "{{ .ImageId }}"
+    {{- end }}
+    {{- if .InstanceType }}
+    instance_type = "{{ .InstanceType }}"
+    {{- end }}
+    {{- if .KeyName }}
+    key_name = "{{ .KeyName }}"
+    {{- end }}
+    {{- if .KernelId }}
+    kernel_id = "{{ .KernelId }}"
+    {{- end }}
+    {{- if .RamDiskId }}
+    ram_disk_id = "{{ .RamDiskId }}"
+    {{- end }}
+    {{- if .EbsOptimized }}
+    ebs_optimized = "{{ .EbsOptimized }}"
+    {{- end }}
+    {{- if .DisableApiTermination }}
+    disable_api_termination = {{ .DisableApiTermination }}
+    {{- end }}
+    {{- if .InstanceInitiatedShutdownBehavior }}
+    instance_initiated_shutdown_behavior = "{{ .InstanceInitiatedShutdownBehavior }}"
+    {{- end }}
+    {{- if .SecurityGroupIds }}
+    vpc_security_group_ids = [{{ .SecurityGroupIds | makeTerraformList }}]
+    {{- end }}
+    {{- if .SecurityGroups }}
+    security_group_names = [{{ .SecurityGroups | makeTerraformList }}]
+    {{- end }}
+    {{- if .UserData }}
+    user_data = "{{ .UserData }}"
+    {{- end }}
+    {{- with .IamInstanceProfile }}
+
+    iam_instance_profile {
+      {{- if .Name }}
+      name = "{{ .Name }}"
+      {{- else }}
+      arn = "{{ .Arn }}"
+      {{- end }}
+    }
+    {{- end }}
+    {{- with .Monitoring }}
+    {{- if .Enabled }}
+
+    monitoring {
+      enabled = {{ .Enabled }}
+    }
+    {{- end }}
+    {{- end }}
+    {{- with .CreditSpecification }}
+
+    credit_specification {
+      cpu_credits = "{{ .CpuCredits }}"
+    }
+    {{- end }}
+    {{- with .Placement }}
+
+    placement {
+      {{- if .AvailabilityZone }}
+      availability_zone = "{{ .AvailabilityZone }}"
+      {{- end }}
+      {{- if .GroupName }}
+      group_name = "{{ .GroupName }}"
+      {{- end }}
+      {{- if .Tenancy }}
+      tenancy = "{{ .Tenancy }}"
+      {{- end }}
+      {{- if .HostId }}
+      host_id = "{{ .HostId }}"
+      {{- end }}
+    }
+    {{- end }}
+    {{- with .InstanceMarketOptions }}
+
+    instance_market_options {
+      market_type = "{{ .MarketType }}"
+      {{- with .SpotOptions }}
+
+      spot_options {
+        {{- if .BlockDurationMinutes }}
+        block_duration_minutes = {{ .BlockDurationMinutes }}
+        {{- end }}
+        {{- if .InstanceInterruptionBehavior }}
+        instance_interruption_behavior = "{{ .InstanceInterruptionBehavior }}"
+        {{- end }}
+        {{- if .MaxPrice }}
+        max_price = "{{ .MaxPrice }}"
+        {{- end }}
+        {{- if .SpotInstanceType }}
+        spot_instance_type = "{{ .SpotInstanceType }}"
+        {{- end }}
+        {{- if .ValidUntil }}
+        valid_until = "{{ .ValidUntil.Format "2006-01-02T15:04:05Z07:00" }}"
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+    {{- range .BlockDeviceMappings }}
+
+    block_device_mappings {
+      device_name = "{{ .DeviceName }}"
+      {{- if .NoDevice }}
+      no_device = "{{ .NoDevice }}"
+      {{- end }}
+      {{- if .VirtualName }}
+      virtual_name = "{{ .VirtualName }}"
+      {{- end }}
+      {{- with .Ebs }}
+
+      ebs {
+        {{- if .SnapshotId }}
+        snapshot_id = "{{ .SnapshotId }}"
+        {{- end }}
+        {{- if .VolumeType }}
+        volume_type = "{{ .VolumeType }}"
+        {{- end }}
+        {{- if .VolumeSize }}
+        volume_size = {{ .VolumeSize }}
+        {{- end }}
+        {{- if .Iops }}
+        iops = {{ .Iops }}
+        {{- end }}
+        {{- if .Encrypted }}
+        encrypted = {{ .Encrypted }}
+        {{- end }}
+        {{- if .KmsKeyId }}
+        kms_key_id = "{{ .KmsKeyId }}"
+        {{- end }}
+        {{- if .DeleteOnTermination }}
+        delete_on_termination = {{ .DeleteOnTermination }}
+        {{- end }}
+      }
+      {{- end }}
+    }
+    {{- end }}
+    {{- range .NetworkInterfaces }}
+
+    network_interfaces {
+      device_index = {{ .DeviceIndex }}
+      {{- if .NetworkInterfaceId }}
+      network_interface_id = "{{ .NetworkInterfaceId }}"
+      {{- end }}
+      {{- if .SubnetId }}
+      subnet_id = "{{ .SubnetId }}"
+      {{- end }}
+      {{- if .Description }}
+      description = "{{ .Description }}"
+      {{- end }}
+      {{- if .AssociatePublicIpAddress }}
+      associate_public_ip_address = {{ .AssociatePublicIpAddress }}
+      {{- end }}
+      {{- if .DeleteOnTermination }}
+      delete_on_termination = {{ .DeleteOnTermination }}
+      {{- end }}
+      {{- if .Groups }}
+      security_groups = [{{ .Groups | makeTerraformList }}]
+      {{- end }}
+      {{- if .PrivateIpAddress }}
+      private_ip_address = "{{ .PrivateIpAddress }}"
+      {{- end }}
+      {{- with secondaryIPv4Addresses .PrivateIpAddresses }}
+      ipv4_addresses = [{{ . | makeTerraformList }}]
+      {{- end }}
+      {{- if .SecondaryPrivateIpAddressCount }}
+      ipv4_address_count = {{ .SecondaryPrivateIpAddressCount }}
+      {{- end }}
+      {{- with ipv6Addresses .Ipv6Addresses }}
+      ipv6_addresses = [{{ . | makeTerraformList }}]
+      {{- end }}
+      {{- if .Ipv6AddressCount }}
+      ipv6_address_count = {{ .Ipv6AddressCount }}
+      {{- end }}
+    }
+    {{- end }}
+    {{- range .TagSpecifications }}
+
+    tag_specifications {
+      resource_type = "{{ .ResourceType }}"
+
+      tags {
+        {{- range .Tags }}
+        "{{ .Key }}" = "{{ .Value }}"
+        {{- end }}
+      }
+    }
+    {{- end }}
+    {{- if .Tags }}
+
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, lts)
+}
@@ -0,0 +1,154 @@
+package tfit
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestLaunchTemplatesWriteHCL(t *testing.T) {
+	lts := &LaunchTemplates{
+		{
+			LaunchTemplateID:   aws.String("lt-1"),
+			LaunchTemplateName: aws.String("web"),
+			VersionNumber:      aws.Int64(3),
+			VersionDescription: aws.String("nginx"),
+			Tags:               &Tags{"Name": aws.String("web")},
+			ResponseLaunchTemplateData: &ec2.ResponseLaunchTemplateData{
+				ImageId:          aws.String("ami-1"),
+				InstanceType:     aws.String("t3.micro"),
+				SecurityGroupIds: aws.StringSlice([]string{"sg-1"}),
+				Monitoring:       &ec2.LaunchTemplatesMonitoring{Enabled: aws.Bool(true)},
+				IamInstanceProfile: &ec2.LaunchTemplateIamInstanceProfileSpecification{
+					Arn: aws.String("arn:aws:iam::123456789012:instance-profile/web"),
+				},
+				BlockDeviceMappings: []*ec2.LaunchTemplateBlockDeviceMapping{{
+					DeviceName: aws.String("/dev/xvda"),
+					Ebs: &ec2.LaunchTemplateEbsBlockDevice{
+						VolumeType: aws.String("gp2"),
+						VolumeSize: aws.Int64(20),
+						Encrypted:  aws.Bool(true),
+					},
+				}},
+				NetworkInterfaces: []*ec2.LaunchTemplateInstanceNetworkInterfaceSpecification{{
+					DeviceIndex: aws.Int64(0),
+					SubnetId:    aws.String("subnet-1"),
+					PrivateIpAddresses: []*ec2.PrivateIpAddressSpecification{
+						{PrivateIpAddress: aws.String("10.0.0.10"), Primary: aws.Bool(true)},
+						{PrivateIpAddress: aws.String("10.0.0.11"), Primary: aws.Bool(false)},
+					},
+				}},
+				TagSpecifications: []*ec2.LaunchTemplateTagSpecification{{
+					ResourceType: aws.String("instance"),
+					Tags:         []*ec2.Tag{{Key: aws.String("Role"), Value: aws.String("web")}},
+				}},
+			},
+			ResourceName: "web",
+		},
+		{
+			LaunchTemplateID:   aws.String("lt-2"),
+			LaunchTemplateName: aws.String("empty"),
+			Tags:               &Tags{},
+			ResponseLaunchTemplateData: &ec2.ResponseLaunchTemplateData{
+				Monitoring: &ec2.LaunchTemplatesMonitoring{},
+			},
+			ResourceName: "empty",
+		},
+	}
+
+	rs := mustRenderHCL(t, lts)
+	if len(rs) != 2 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"name":                                "web",
+		"description":                         "nginx",
+		"image_id":                            "ami-1",
+		"instance_type":                       "t3.micro",
+		"vpc_security_group_ids":              "[sg-1]",
+		"monitoring.enabled":                  "true",
+		"iam_instance_profile.arn":            "arn:aws:iam::123456789012:instance-profile/web",
+		"block_device_mappings.device_name":   "/dev/xvda",
+		"block_device_mappings.ebs.encrypted": "true",
+		"network_interfaces.subnet_id":        "subnet-1",
+		"network_interfaces.ipv4_addresses":   "[10.0.0.11]",
+		"tag_specifications.tags.Role":        "web",
+		"tags.Name":                           "web",
+	})
+	checkAttributes(t, rs[1], map[string]string{"name": "empty", "image_id": "", "monitoring.enabled": ""})
+}
+
+func TestAutoScalingGroupsLaunchTemplate(t *testing.T) {
+	groups := &AutoScalingGroups{
+		{
+			Name:                  aws.String("web"),
+			MinSize:               aws.Int64(1),
+			MaxSize:               aws.Int64(2),
+			LaunchTemplateID:      aws.String("lt-1"),
+			LaunchTemplateName:    aws.String("web"),
+			LaunchTemplateVersion: aws.String("$Latest"),
+			LaunchTemplateRef:     "${aws_launch_template.web.id}",
+			ResourceName:          "web",
+		},
+		{
+			Name:               aws.String("batch"),
+			MinSize:            aws.Int64(0),
+			MaxSize:            aws.Int64(4),
+			LaunchTemplateID:   aws.String("lt-2"),
+			LaunchTemplateName: aws.String("batch"),
+			ResourceName:       "batch",
+		},
+		{
+			Name:             aws.String("worker"),
+			MinSize:          aws.Int64(0),
+			MaxSize:          aws.Int64(1),
+			LaunchTemplateID: aws.String("lt-3"),
+			ResourceName:     "worker",
+		},
+	}
+
+	rs := mustRenderHCL(t, groups)
+	if len(rs) != 3 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{"launch_template.version": "$Latest", "launch_template.name": ""})
+	checkAttributes(t, rs[1], map[string]string{"launch_template.name": "batch", "launch_template.version": ""})
+	checkAttributes(t, rs[2], map[string]string{"launch_template.id": "lt-3", "launch_template.name": ""})
+}
+
+func TestGetAutoScalingGroupsLaunchTemplateRef(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("Action") != "DescribeAutoScalingGroups" {
+			http.Error(w, r.Form.Get("Action"), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `<DescribeAutoScalingGroupsResponse><DescribeAutoScalingGroupsResult><AutoScalingGroups>
+<member><AutoScalingGroupName>web</AutoScalingGroupName><MinSize>1</MinSize><MaxSize>2</MaxSize>
+<LaunchTemplate><LaunchTemplateId>lt-1</LaunchTemplateId><LaunchTemplateName>web</LaunchTemplateName></LaunchTemplate></member>
+<member><AutoScalingGroupName>batch</AutoScalingGroupName><MinSize>0</MinSize><MaxSize>4</MaxSize>
+<LaunchTemplate><LaunchTemplateId>lt-2</LaunchTemplateId><LaunchTemplateName>batch</LaunchTemplateName></LaunchTemplate></member>
+</AutoScalingGroups></DescribeAutoScalingGroupsResult></DescribeAutoScalingGroupsResponse>`)
+	})
+
+	// Only lt-1 is exported
+	lt := &LaunchTemplate{LaunchTemplateID: aws.String("lt-1"), LaunchTemplateName: aws.String("web")}
+	name := c.namer.Name(lt.nameInfo())
+
+	groups, err := c.GetAutoScalingGroupsWithContext(aws.BackgroundContext())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, v := range *groups {
+		got = append(got, v.LaunchTemplateRef)
+	}
+	if want := []string{"${aws_launch_template." + name + ".id}", ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,267 @@
+package tfit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/parser"
+)
+
+// Level is the severity of a log entry
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return strconv.Itoa(int(l))
+	}
+
+	return levelNames[l]
+}
+
+// ParseLevel parses one of "debug", "info", "warn" or "error"
+func ParseLevel(s string) (Level, error) {
+	for i, v := range levelNames {
+		if strings.EqualFold(s, v) {
+			return Level(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("Unknown log level: %s", s)
+}
+
+// Logger receives the log entries of tfit. 'keyvals' are
+// alternating keys and values, e.g. "service", "ec2", "pages", 3
+type Logger interface {
+	Log(level Level, msg string, keyvals ...interface{})
+}
+
+// Log formats understood by NewLogger
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+type logger struct {
+	mu     sync.Mutex
+	w      io.Writer
+	level  Level
+	format string
+}
+
+// NewLogger returns a Logger writing the entries at or above 'level'
+// to w, one per line, either as key=value pairs or as JSON objects
+func NewLogger(w io.Writer, level Level, format string) (Logger, error) {
+	switch format {
+	case "":
+		format = LogFormatText
+	case LogFormatText, LogFormatJSON:
+	default:
+		return nil, fmt.Errorf("Unknown log format: %s", format)
+	}
+
+	return &logger{w: w, level: level, format: format}, nil
+}
+
+func (l *logger) Log(level Level, msg string, keyvals ...interface{}) {
+	if level < l.level {
+		return
+	}
+
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, nil)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	ts := time.Now().UTC().Format(time.RFC3339)
+	if l.format == LogFormatJSON {
+		entry := map[string]interface{}{
+			"time":  ts,
+			"level": level.String(),
+			"msg":   msg,
+		}
+		for i := 0; i < len(keyvals); i += 2 {
+			v := keyvals[i+1]
+			switch t := v.(type) {
+			case error:
+				v = t.Error()
+			case fmt.Stringer:
+				v = t.String()
+			}
+			entry[fmt.Sprint(keyvals[i])] = v
+		}
+
+		b, err := json.Marshal(entry)
+		if err != nil {
+			b, _ = json.Marshal(map[string]string{"time": ts, "level": level.String(), "msg": msg, "error": err.Error()})
+		}
+		buf.Write(b)
+	} else {
+		fmt.Fprintf(buf, "time=%s level=%s msg=%s", ts, level, logfmtValue(msg))
+		for i := 0; i < len(keyvals); i += 2 {
+			fmt.Fprintf(buf, " %s=%s", keyvals[i], logfmtValue(keyvals[i+1]))
+		}
+	}
+	buf.WriteByte('\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(buf.Bytes())
+}
+
+func logfmtValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if len(s) == 0 || strings.ContainsAny(s, " =\"\t\n") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
+
+// logger returns the Logger of the clients (Default to warnings
+// and errors written to StdErr as key=value pairs)
+func (c *Config) logger() Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+
+	l, _ := NewLogger(os.Stderr, LevelWarn, LogFormatText)
+	return l
+}
+
+func (c *AWSClient) logf(level Level, msg string, keyvals ...interface{}) {
+	if c.logger != nil {
+		c.logger.Log(level, msg, keyvals...)
+	}
+}
+
+// ProgressStats is a snapshot of the work done so far
+type ProgressStats struct {
+	// API pages fetched per AWS service
+	Pages map[string]int
+
+	// Resources rendered per Terraform resource type
+	Rendered map[string]int
+}
+
+// progress counts the pages fetched & resources rendered by a client
+type progress struct {
+	mu       sync.Mutex
+	pages    map[string]int
+	rendered map[string]int
+}
+
+func newProgress() *progress {
+	return &progress{
+		pages:    make(map[string]int),
+		rendered: make(map[string]int),
+	}
+}
+
+// Progress returns the pages fetched & resources rendered so far by the client
+func (c *AWSClient) Progress() ProgressStats {
+	c.progress.mu.Lock()
+	defer c.progress.mu.Unlock()
+
+	res := ProgressStats{
+		Pages:    make(map[string]int, len(c.progress.pages)),
+		Rendered: make(map[string]int, len(c.progress.rendered)),
+	}
+	for k, v := range c.progress.pages {
+		res.Pages[k] = v
+	}
+	for k, v := range c.progress.rendered {
+		res.Rendered[k] = v
+	}
+
+	return res
+}
+
+// trackProgress counts (and logs) every page successfully
+// fetched by the service client
+func (c *AWSClient) trackProgress(svc *client.Client) {
+	svc.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "tfit.ProgressHandler",
+		Fn: func(r *request.Request) {
+			if r.Error != nil {
+				c.logf(LevelDebug, "request failed", "service", r.ClientInfo.ServiceName, "operation", r.Operation.Name, "error", r.Error)
+				return
+			}
+
+			c.progress.mu.Lock()
+			c.progress.pages[r.ClientInfo.ServiceName]++
+			pages := c.progress.pages[r.ClientInfo.ServiceName]
+			c.progress.mu.Unlock()
+
+			c.logf(LevelInfo, "page fetched", "service", r.ClientInfo.ServiceName, "operation", r.Operation.Name, "pages", pages)
+		},
+	})
+}
+
+// WriteHCL renders a collection like its WriteHCL method
+// and counts (and logs) the rendered resources
+func (c *AWSClient) WriteHCL(w io.Writer, col Collection) error {
+	buf := bytes.NewBuffer(nil)
+	if err := col.WriteHCL(buf); err != nil {
+		return err
+	}
+
+	f, err := parser.Parse(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	c.trackRendered(f.Node)
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// trackRendered counts (and logs) the resource blocks of a rendered HCL file
+func (c *AWSClient) trackRendered(node ast.Node) {
+	list, ok := node.(*ast.ObjectList)
+	if !ok {
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, item := range list.Items {
+		if len(item.Keys) < 2 || item.Keys[0].Token.Value() != "resource" {
+			continue
+		}
+		counts[fmt.Sprint(item.Keys[1].Token.Value())]++
+	}
+
+	types := make([]string, 0, len(counts))
+	for k := range counts {
+		types = append(types, k)
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		c.progress.mu.Lock()
+		c.progress.rendered[t] += counts[t]
+		total := c.progress.rendered[t]
+		c.progress.mu.Unlock()
+
+		c.logf(LevelInfo, "resources rendered", "type", t, "count", counts[t], "total", total)
+	}
+}
@@ -0,0 +1,180 @@
+package tfit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestParseLevel(t *testing.T) {
+	cases := []struct {
+		src  string
+		want Level
+		err  bool
+	}{
+		{"debug", LevelDebug, false},
+		{"INFO", LevelInfo, false},
+		{"Warn", LevelWarn, false},
+		{"error", LevelError, false},
+		{"trace", 0, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.src, func(t *testing.T) {
+			got, err := ParseLevel(c.src)
+			if (err != nil) != c.err || got != c.want {
+				t.Errorf("got (%v, %v), want %v", got, err, c.want)
+			}
+		})
+	}
+}
+
+func TestNewLoggerFormat(t *testing.T) {
+	for _, f := range []string{"", LogFormatText, LogFormatJSON} {
+		if _, err := NewLogger(nil, LevelInfo, f); err != nil {
+			t.Errorf("%q: %s", f, err)
+		}
+	}
+
+	if _, err := NewLogger(nil, LevelInfo, "xml"); err == nil {
+		t.Error("got no error for xml")
+	}
+}
+
+func TestLoggerText(t *testing.T) {
+	cases := []struct {
+		name    string
+		level   Level
+		msg     string
+		keyvals []interface{}
+		want    string
+	}{
+		{"filtered", LevelDebug, "page fetched", nil, ""},
+		{"plain", LevelInfo, "done", nil, "level=info msg=done\n"},
+		{"keyvals", LevelWarn, "page fetched", []interface{}{"service", "ec2", "pages", 3},
+			"level=warn msg=\"page fetched\" service=ec2 pages=3\n"},
+		{"quoted values", LevelError, "failed", []interface{}{"error", errors.New(`bad "id"`), "empty", ""},
+			"level=error msg=failed error=\"bad \\\"id\\\"\" empty=\"\"\n"},
+		{"odd keyvals", LevelInfo, "done", []interface{}{"count"}, "level=info msg=done count=<nil>\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			l, err := NewLogger(buf, LevelInfo, LogFormatText)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			l.Log(c.level, c.msg, c.keyvals...)
+			got := buf.String()
+			if len(c.want) == 0 {
+				if len(got) > 0 {
+					t.Errorf("got %q, want nothing", got)
+				}
+				return
+			}
+			if !strings.HasPrefix(got, "time=") || !strings.HasSuffix(got, " "+c.want) {
+				t.Errorf("got %q, want time=... %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestLoggerJSON(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l, err := NewLogger(buf, LevelDebug, LogFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Log(LevelDebug, "request failed", "service", "s3", "error", errors.New("denied"), "min", LevelWarn, "pages", 2)
+	l.Log(LevelInfo, "done")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := entry["time"]; !ok {
+		t.Error("missing time")
+	}
+	delete(entry, "time")
+
+	want := map[string]interface{}{"level": "debug", "min": "warn", "msg": "request failed", "service": "s3", "error": "denied", "pages": float64(2)}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s: got %v, want %v", k, entry[k], v)
+		}
+	}
+}
+
+// recordLogger keeps the messages of the entries
+type recordLogger struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (l *recordLogger) Log(level Level, msg string, keyvals ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.msgs = append(l.msgs, msg)
+}
+
+func TestClientProgress(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<DescribeVpcsResponse></DescribeVpcsResponse>`)
+	}
+
+	logs := &recordLogger{}
+	c1 := newTestClient(t, handler)
+	c1.logger = logs
+	c1.trackProgress(c1.ec2conn.Client)
+	c2 := newTestClient(t, handler)
+	c2.trackProgress(c2.ec2conn.Client)
+
+	for _, c := range []*AWSClient{c1, c1, c2} {
+		if _, err := c.ec2conn.DescribeVpcs(&ec2.DescribeVpcsInput{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	igws := &InternetGateways{{InternetGatewayID: aws.String("igw-1"), ResourceName: "main"}}
+	if err := c1.WriteHCL(buf, igws); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `resource "aws_internet_gateway" "main"`) {
+		t.Errorf("got %s", buf.String())
+	}
+
+	want := ProgressStats{Pages: map[string]int{"ec2": 2}, Rendered: map[string]int{"aws_internet_gateway": 1}}
+	if got := c1.Progress(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	want = ProgressStats{Pages: map[string]int{"ec2": 1}, Rendered: map[string]int{}}
+	if got := c2.Progress(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if want := []string{"page fetched", "page fetched", "resources rendered"}; !reflect.DeepEqual(logs.msgs, want) {
+		t.Errorf("got logs %v, want %v", logs.msgs, want)
+	}
+
+	if r := newRetryer(&Config{Logger: logs}); r.logger != logs {
+		t.Error("the retryer doesn't use the logger of the config")
+	}
+}
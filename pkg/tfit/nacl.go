@@ -0,0 +1,203 @@
+package tfit
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+//**************** Network ACL ****************
+
+// The rules numbered from 32767 are the implicit "deny all" ones
+const maxNetworkACLRuleNumber = 32766
+
+type NetworkACL struct {
+	NetworkACLID *string
+	VPCID        *string
+	SubnetIDs    []*string
+	Rules        []*NetworkACLRule
+	Tags         *Tags
+
+	// The default network ACL of the VPC is exported as aws_default_network_acl
+	Default bool
+
+	ResourceName string
+}
+
+type NetworkACLRule struct {
+	Egress        bool
+	RuleNumber    *int64
+	Action        *string
+	Protocol      *string
+	CIDRBlock     *string
+	IPv6CIDRBlock *string
+	FromPort      int64
+	ToPort        int64
+	ICMPType      *int64
+	ICMPCode      *int64
+}
+
+type NetworkACLs []*NetworkACL
+
+func (acl *NetworkACL) set(src *ec2.NetworkAcl) {
+	acl.NetworkACLID = src.NetworkAclId
+	acl.VPCID = src.VpcId
+	acl.Default = aws.BoolValue(src.IsDefault)
+	acl.Tags = &Tags{}
+	acl.Tags.setTags(src.Tags)
+
+	for _, v := range src.Associations {
+		acl.SubnetIDs = append(acl.SubnetIDs, v.SubnetId)
+	}
+
+	for _, v := range src.Entries {
+		if aws.Int64Value(v.RuleNumber) > maxNetworkACLRuleNumber {
+			continue
+		}
+
+		r := &NetworkACLRule{
+			Egress:        aws.BoolValue(v.Egress),
+			RuleNumber:    v.RuleNumber,
+			Action:        v.RuleAction,
+			Protocol:      v.Protocol,
+			CIDRBlock:     v.CidrBlock,
+			IPv6CIDRBlock: v.Ipv6CidrBlock,
+		}
+		if v.PortRange != nil {
+			r.FromPort = aws.Int64Value(v.PortRange.From)
+			r.ToPort = aws.Int64Value(v.PortRange.To)
+		}
+		if v.IcmpTypeCode != nil {
+			r.ICMPType = v.IcmpTypeCode.Type
+			r.ICMPCode = v.IcmpTypeCode.Code
+		}
+		acl.Rules = append(acl.Rules, r)
+	}
+
+	// Ingress rules first, by rule number
+	sort.SliceStable(acl.Rules, func(i, j int) bool {
+		if acl.Rules[i].Egress != acl.Rules[j].Egress {
+			return !acl.Rules[i].Egress
+		}
+		return aws.Int64Value(acl.Rules[i].RuleNumber) < aws.Int64Value(acl.Rules[j].RuleNumber)
+	})
+}
+
+func (acl *NetworkACL) nameInfo() NameInfo {
+	t := "aws_network_acl"
+	if acl.Default {
+		t = "aws_default_network_acl"
+	}
+
+	return NameInfo{
+		Type: t,
+		ID:   aws.StringValue(acl.NetworkACLID),
+		Tags: acl.Tags.values(),
+	}
+}
+
+func (acls *NetworkACLs) addToGraph(g *Graph) {
+	for _, v := range *acls {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCID))
+		g.addEdges(n, "subnet", "aws_subnet", v.SubnetIDs)
+	}
+}
+
+func (acls *NetworkACLs) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *acls {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (acls *NetworkACLs) addMissingTags(tags map[string]string) {
+	for _, v := range *acls {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (c *AWSClient) GetNetworkACLs() (*NetworkACLs, error) {
+	return c.GetNetworkACLsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetNetworkACLsWithContext(ctx aws.Context) (*NetworkACLs, error) {
+	out, err := c.ec2conn.DescribeNetworkAclsWithContext(ctx, &ec2.DescribeNetworkAclsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("Error describing network ACLs: %s", err)
+	}
+
+	res := NetworkACLs{}
+	for _, v := range out.NetworkAcls {
+		acl := &NetworkACL{}
+		acl.set(v)
+		acl.ResourceName = c.namer.Name(acl.nameInfo())
+		res = append(res, acl)
+	}
+
+	return &res, nil
+}
+
+func (acls *NetworkACLs) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformList": makeTerraformList,
+	}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+    {{- if .Default }}
+	resource "aws_default_network_acl" "{{ .ResourceName }}" {
+    default_network_acl_id = "{{ .NetworkACLID }}"
+    {{- else }}
+	resource "aws_network_acl" "{{ .ResourceName }}" {
+    vpc_id = "{{ .VPCID }}"
+    {{- end }}
+    {{- if .SubnetIDs }}
+    subnet_ids = [{{ .SubnetIDs | makeTerraformList }}]
+    {{- end }}
+
+    {{- range .Rules }}
+    {{ if .Egress }}egress{{ else }}ingress{{ end }} {
+      rule_no = {{ .RuleNumber }}
+      action = "{{ .Action }}"
+      protocol = "{{ .Protocol }}"
+      from_port = {{ .FromPort }}
+      to_port = {{ .ToPort }}
+      {{- if .CIDRBlock }}
+      cidr_block = "{{ .CIDRBlock }}"
+      {{- end }}
+      {{- if .IPv6CIDRBlock }}
+      ipv6_cidr_block = "{{ .IPv6CIDRBlock }}"
+      {{- end }}
+      {{- if .ICMPType }}
+      icmp_type = {{ .ICMPType }}
+      {{- end }}
+      {{- if .ICMPCode }}
+      icmp_code = {{ .ICMPCode }}
+      {{- end }}
+    }
+    {{- end }}
+
+    {{- if .Tags }}
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, acls)
+}
@@ -0,0 +1,64 @@
+package tfit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestNetworkACLsWriteHCL(t *testing.T) {
+	src := []*ec2.NetworkAcl{
+		{
+			NetworkAclId: aws.String("acl-1"),
+			VpcId:        aws.String("vpc-1"),
+			IsDefault:    aws.Bool(true),
+			Associations: []*ec2.NetworkAclAssociation{{SubnetId: aws.String("subnet-1")}},
+			Entries: []*ec2.NetworkAclEntry{
+				{Egress: aws.Bool(true), RuleNumber: aws.Int64(100), RuleAction: aws.String("allow"), Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0")},
+				{Egress: aws.Bool(false), RuleNumber: aws.Int64(32767), RuleAction: aws.String("deny"), Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0")},
+				{Egress: aws.Bool(false), RuleNumber: aws.Int64(100), RuleAction: aws.String("allow"), Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0")},
+			},
+		},
+		{
+			NetworkAclId: aws.String("acl-2"),
+			VpcId:        aws.String("vpc-1"),
+			Tags:         []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("private")}},
+			Entries: []*ec2.NetworkAclEntry{
+				{Egress: aws.Bool(false), RuleNumber: aws.Int64(200), RuleAction: aws.String("allow"), Protocol: aws.String("1"),
+					Ipv6CidrBlock: aws.String("::/0"), IcmpTypeCode: &ec2.IcmpTypeCode{Type: aws.Int64(0), Code: aws.Int64(-1)}},
+				{Egress: aws.Bool(false), RuleNumber: aws.Int64(100), RuleAction: aws.String("allow"), Protocol: aws.String("6"),
+					CidrBlock: aws.String("10.0.0.0/8"), PortRange: &ec2.PortRange{From: aws.Int64(443), To: aws.Int64(443)}},
+			},
+		},
+	}
+
+	acls := NetworkACLs{}
+	for _, v := range src {
+		acl := &NetworkACL{}
+		acl.set(v)
+		acl.ResourceName = aws.StringValue(v.NetworkAclId)
+		acls = append(acls, acl)
+	}
+
+	rs := mustRenderHCL(t, &acls)
+	if got, want := addresses(rs), []string{"aws_default_network_acl.acl-1", "aws_network_acl.acl-2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	checkAttributes(t, rs[0], map[string]string{
+		"default_network_acl_id": "acl-1",
+		"vpc_id":                 "",
+		"subnet_ids":             "[subnet-1]",
+		"ingress.rule_no":        "100",
+		"egress.rule_no":         "100",
+		"egress.protocol":        "-1",
+	})
+	checkAttributes(t, rs[1], map[string]string{
+		"vpc_id":    "vpc-1",
+		"tags.Name": "private",
+		"ingress": "[map[action:allow cidr_block:10.0.0.0/8 from_port:443 protocol:6 rule_no:100 to_port:443] " +
+			"map[action:allow from_port:0 icmp_code:-1 icmp_type:0 ipv6_cidr_block:::/0 protocol:1 rule_no:200 to_port:0]]",
+	})
+}
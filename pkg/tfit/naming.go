@@ -0,0 +1,212 @@
+package tfit
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// Naming strategies understood by NewNamer
+const (
+	// NamingID names resources after their AWS id
+	NamingID = "id"
+
+	// NamingNameTag prefers the Name tag, then the natural name
+	// of the resource (bucket name, role name, ...), then the id
+	NamingNameTag = "name-tag"
+
+	// NamingTemplate renders a text/template against NameInfo
+	NamingTemplate = "template"
+)
+
+// NameInfo describes the resource being named
+type NameInfo struct {
+	// Terraform resource type, e.g. aws_instance
+	Type string
+
+	// The id Terraform uses for the resource (the import id)
+	ID string
+
+	// Natural name of the resource, e.g. bucket or role name
+	Name string
+
+	Tags map[string]string
+}
+
+// Namer gives every exported resource a valid Terraform resource name.
+// A resource always gets the same name within an export and names are
+// unique per resource type: clashes get a "-2", "-3", ... suffix in the
+// order the resources are named.
+type Namer struct {
+	strategy string
+	tmpl     *template.Template
+
+	mu    sync.Mutex
+	names map[string]map[string]string // type -> id -> name
+	ids   map[string]map[string]string // type -> name -> id
+}
+
+// NewNamer returns a Namer using one of the Naming* strategies.
+// 'tmpl' is only used by NamingTemplate, e.g. `{{ .Tags.env }}-{{ .Name }}`
+func NewNamer(strategy, tmpl string) (*Namer, error) {
+	n := &Namer{
+		strategy: strategy,
+		names:    make(map[string]map[string]string),
+		ids:      make(map[string]map[string]string),
+	}
+
+	switch strategy {
+	case "":
+		n.strategy = NamingNameTag
+	case NamingID, NamingNameTag:
+	case NamingTemplate:
+		if len(tmpl) == 0 {
+			return nil, fmt.Errorf("Naming template is required by the %q naming strategy", NamingTemplate)
+		}
+
+		t, err := template.New("naming").Option("missingkey=zero").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing naming template: %s", err)
+		}
+		n.tmpl = t
+	default:
+		return nil, fmt.Errorf("Unknown naming strategy: %s", strategy)
+	}
+
+	return n, nil
+}
+
+// Name returns the Terraform resource name of the resource
+func (n *Namer) Name(info NameInfo) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if name, ok := n.names[info.Type][info.ID]; ok {
+		return name
+	}
+
+	if n.names[info.Type] == nil {
+		n.names[info.Type] = make(map[string]string)
+		n.ids[info.Type] = make(map[string]string)
+	}
+
+	base := makeTerraformResourceName(aws.String(n.baseName(info)))
+	name := base
+	for i := 2; ; i++ {
+		if _, taken := n.ids[info.Type][name]; !taken {
+			break
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	n.names[info.Type][info.ID] = name
+	n.ids[info.Type][name] = info.ID
+
+	return name
+}
+
+// Lookup returns the name given to the resource with the given id
+func (n *Namer) Lookup(resourceType, id string) (string, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	name, ok := n.names[resourceType][id]
+	return name, ok
+}
+
+// reference returns the interpolation of the id of an exported
+// resource, e.g. ${aws_vpc.main.id}, or "" when it isn't exported
+func (n *Namer) reference(resourceType string, id *string) string {
+	name, ok := n.Lookup(resourceType, aws.StringValue(id))
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("${%s.%s.id}", resourceType, name)
+}
+
+// ID returns the id of the resource named 'name'
+func (n *Namer) ID(resourceType, name string) (string, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	id, ok := n.ids[resourceType][name]
+	return id, ok
+}
+
+func (n *Namer) baseName(info NameInfo) string {
+	switch n.strategy {
+	case NamingID:
+		if len(info.ID) > 0 {
+			return info.ID
+		}
+	case NamingTemplate:
+		buf := bytes.NewBuffer(nil)
+		if err := n.tmpl.Execute(buf, info); err == nil {
+			if name := strings.TrimSpace(buf.String()); len(name) > 0 {
+				return name
+			}
+		}
+	}
+
+	if v := info.Tags["Name"]; len(v) > 0 {
+		return v
+	}
+
+	if len(info.Name) > 0 {
+		return info.Name
+	}
+
+	return info.ID
+}
+
+// Helpers converting the different shapes of tags to map[string]string
+
+func (t *Tags) values() map[string]string {
+	res := make(map[string]string)
+	if t == nil {
+		return res
+	}
+
+	for k, v := range *t {
+		res[k] = aws.StringValue(v)
+	}
+
+	return res
+}
+
+func tagValues(src map[string]*string) map[string]string {
+	t := Tags(src)
+	return t.values()
+}
+
+func ptrTagValues(src map[*string]*string) map[string]string {
+	res := make(map[string]string)
+	for k, v := range src {
+		res[aws.StringValue(k)] = aws.StringValue(v)
+	}
+
+	return res
+}
+
+func resourceTagValues(src []*ResourceTag) map[string]string {
+	res := make(map[string]string)
+	for _, v := range src {
+		res[aws.StringValue(v.Key)] = aws.StringValue(v.Value)
+	}
+
+	return res
+}
+
+func tagDescriptionValues(src []*TagDescription) map[string]string {
+	res := make(map[string]string)
+	for _, v := range src {
+		res[aws.StringValue(v.Key)] = aws.StringValue(v.Value)
+	}
+
+	return res
+}
@@ -0,0 +1,133 @@
+package tfit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestMakeTerraformResourceName(t *testing.T) {
+	cases := []struct {
+		src  string
+		want string
+	}{
+		{"web", "web"},
+		{"web_server", "web_server"},
+		{"Web Server", "Web-Server"},
+		{"www.example.com.", "www-example-com"},
+		{"a  /  b", "a-b"},
+		{"--a--", "a"},
+		{"10.0.0.0/16", "_10-0-0-0-16"},
+		{"vpc-0a1b", "vpc-0a1b"},
+		{"été", "t"},
+		{"", "_"},
+		{"***", "_"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.src, func(t *testing.T) {
+			if got := makeTerraformResourceName(aws.String(c.src)); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestNewNamer(t *testing.T) {
+	cases := []struct {
+		strategy string
+		tmpl     string
+		err      string
+	}{
+		{"", "", ""},
+		{NamingID, "", ""},
+		{NamingNameTag, "", ""},
+		{NamingTemplate, "{{ .Name }}", ""},
+		{NamingTemplate, "", "Naming template is required"},
+		{NamingTemplate, "{{ .Name ", "Error parsing naming template"},
+		{"random", "", "Unknown naming strategy"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.strategy+"/"+c.tmpl, func(t *testing.T) {
+			_, err := NewNamer(c.strategy, c.tmpl)
+			if len(c.err) == 0 && err != nil {
+				t.Fatal(err)
+			}
+			if len(c.err) > 0 && (err == nil || !strings.Contains(err.Error(), c.err)) {
+				t.Errorf("got error %v, want %q", err, c.err)
+			}
+		})
+	}
+}
+
+func TestNamerName(t *testing.T) {
+	tagged := NameInfo{Type: "aws_instance", ID: "i-1", Name: "natural", Tags: map[string]string{"Name": "web", "env": "prod"}}
+	untagged := NameInfo{Type: "aws_instance", ID: "i-2", Name: "natural"}
+	bare := NameInfo{Type: "aws_instance", ID: "i-3"}
+
+	cases := []struct {
+		strategy string
+		tmpl     string
+		info     NameInfo
+		want     string
+	}{
+		{NamingNameTag, "", tagged, "web"},
+		{NamingNameTag, "", untagged, "natural"},
+		{NamingNameTag, "", bare, "i-3"},
+		{NamingID, "", tagged, "i-1"},
+		{NamingID, "", NameInfo{Type: "aws_instance", Name: "no-id"}, "no-id"},
+		{NamingTemplate, "{{ .Tags.env }}-{{ .Name }}", tagged, "prod-natural"},
+		{NamingTemplate, "{{ .Tags.env }}", untagged, "natural"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.strategy+"/"+c.want, func(t *testing.T) {
+			n, err := NewNamer(c.strategy, c.tmpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := n.Name(c.info); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestNamerClashes(t *testing.T) {
+	n, err := NewNamer(NamingNameTag, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	web := func(id string) NameInfo {
+		return NameInfo{Type: "aws_instance", ID: id, Tags: map[string]string{"Name": "web"}}
+	}
+
+	for _, c := range []struct {
+		info NameInfo
+		want string
+	}{
+		{web("i-1"), "web"},
+		{web("i-2"), "web-2"},
+		{web("i-1"), "web"},
+		{web("i-3"), "web-3"},
+		{NameInfo{Type: "aws_ebs_volume", ID: "vol-1", Tags: map[string]string{"Name": "web"}}, "web"},
+	} {
+		if got := n.Name(c.info); got != c.want {
+			t.Errorf("%s %s: got %q, want %q", c.info.Type, c.info.ID, got, c.want)
+		}
+	}
+
+	if name, ok := n.Lookup("aws_instance", "i-2"); !ok || name != "web-2" {
+		t.Errorf("Lookup: got (%q, %v), want web-2", name, ok)
+	}
+	if id, ok := n.ID("aws_instance", "web-3"); !ok || id != "i-3" {
+		t.Errorf("ID: got (%q, %v), want i-3", id, ok)
+	}
+	if _, ok := n.ID("aws_ebs_volume", "web-2"); ok {
+		t.Error("ID: got a volume named web-2")
+	}
+}
@@ -0,0 +1,212 @@
+package tfit
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+//**************** VPC Peering Connection ****************
+
+type VPCPeeringConnection struct {
+	VPCPeeringConnectionID *string
+	VPCID                  *string
+	PeerVPCID              *string
+
+	// Set when the peer is in another account or region
+	PeerOwnerID *string
+	PeerRegion  *string
+
+	// Another account requested the connection, it's exported
+	// as aws_vpc_peering_connection_accepter
+	Accepter bool
+
+	AccepterOptions  *VPCPeeringOptions
+	RequesterOptions *VPCPeeringOptions
+	Tags             *Tags
+
+	ResourceName string
+}
+
+type VPCPeeringOptions struct {
+	AllowRemoteVPCDNSResolution *bool
+	AllowClassicLinkToRemoteVPC *bool
+	AllowVPCToRemoteClassicLink *bool
+}
+
+type VPCPeeringConnections []*VPCPeeringConnection
+
+func newVPCPeeringOptions(src *ec2.VpcPeeringConnectionOptionsDescription) *VPCPeeringOptions {
+	if src == nil {
+		return nil
+	}
+
+	return &VPCPeeringOptions{
+		AllowRemoteVPCDNSResolution: src.AllowDnsResolutionFromRemoteVpc,
+		AllowClassicLinkToRemoteVPC: src.AllowEgressFromLocalClassicLinkToRemoteVpc,
+		AllowVPCToRemoteClassicLink: src.AllowEgressFromLocalVpcToRemoteClassicLink,
+	}
+}
+
+func (pcx *VPCPeeringConnection) set(src *ec2.VpcPeeringConnection, accountId, region string) {
+	requester, accepter := src.RequesterVpcInfo, src.AccepterVpcInfo
+
+	pcx.VPCPeeringConnectionID = src.VpcPeeringConnectionId
+	pcx.Accepter = aws.StringValue(requester.OwnerId) != accountId && aws.StringValue(accepter.OwnerId) == accountId
+	pcx.AccepterOptions = newVPCPeeringOptions(accepter.PeeringOptions)
+	pcx.RequesterOptions = newVPCPeeringOptions(requester.PeeringOptions)
+	pcx.Tags = &Tags{}
+	pcx.Tags.setTags(src.Tags)
+
+	local, peer := requester, accepter
+	if pcx.Accepter {
+		local, peer = accepter, requester
+	}
+	pcx.VPCID = local.VpcId
+	pcx.PeerVPCID = peer.VpcId
+	if aws.StringValue(peer.OwnerId) != accountId {
+		pcx.PeerOwnerID = peer.OwnerId
+	}
+	if peer.Region != nil && aws.StringValue(peer.Region) != region {
+		pcx.PeerRegion = peer.Region
+	}
+}
+
+func (pcx *VPCPeeringConnection) nameInfo() NameInfo {
+	t := "aws_vpc_peering_connection"
+	if pcx.Accepter {
+		t = "aws_vpc_peering_connection_accepter"
+	}
+
+	return NameInfo{
+		Type: t,
+		ID:   aws.StringValue(pcx.VPCPeeringConnectionID),
+		Tags: pcx.Tags.values(),
+	}
+}
+
+func (pcxs *VPCPeeringConnections) addToGraph(g *Graph) {
+	for _, v := range *pcxs {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCID))
+		g.addEdge(n, "peer_vpc", "aws_vpc", aws.StringValue(v.PeerVPCID))
+	}
+}
+
+func (pcxs *VPCPeeringConnections) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *pcxs {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (pcxs *VPCPeeringConnections) addMissingTags(tags map[string]string) {
+	for _, v := range *pcxs {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (c *AWSClient) GetVPCPeeringConnections(AccountId *string) (*VPCPeeringConnections, error) {
+	return c.GetVPCPeeringConnectionsWithContext(aws.BackgroundContext(), AccountId)
+}
+
+// GetVPCPeeringConnectionsWithContext returns the connections which are
+// pending acceptance, being provisioned or active
+func (c *AWSClient) GetVPCPeeringConnectionsWithContext(ctx aws.Context, AccountId *string) (*VPCPeeringConnections, error) {
+	out, err := c.ec2conn.DescribeVpcPeeringConnectionsWithContext(ctx, &ec2.DescribeVpcPeeringConnectionsInput{
+		Filters: []*ec2.Filter{{
+			Name: aws.String("status-code"),
+			Values: aws.StringSlice([]string{
+				ec2.VpcPeeringConnectionStateReasonCodePendingAcceptance,
+				ec2.VpcPeeringConnectionStateReasonCodeProvisioning,
+				ec2.VpcPeeringConnectionStateReasonCodeActive,
+			}),
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error describing VPC peering connections: %s", err)
+	}
+
+	res := VPCPeeringConnections{}
+	for _, v := range out.VpcPeeringConnections {
+		pcx := &VPCPeeringConnection{}
+		pcx.set(v, aws.StringValue(AccountId), c.region)
+		pcx.ResourceName = c.namer.Name(pcx.nameInfo())
+		res = append(res, pcx)
+	}
+
+	return &res, nil
+}
+
+func (pcxs *VPCPeeringConnections) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ define "options" }}
+      {{- if .AllowRemoteVPCDNSResolution }}
+      allow_remote_vpc_dns_resolution = {{ .AllowRemoteVPCDNSResolution }}
+      {{- end }}
+      {{- if .AllowClassicLinkToRemoteVPC }}
+      allow_classic_link_to_remote_vpc = {{ .AllowClassicLinkToRemoteVPC }}
+      {{- end }}
+      {{- if .AllowVPCToRemoteClassicLink }}
+      allow_vpc_to_remote_classic_link = {{ .AllowVPCToRemoteClassicLink }}
+      {{- end }}
+	{{- end }}
+	{{ if . }}
+		{{- range . }}
+    {{- if .Accepter }}
+	resource "aws_vpc_peering_connection_accepter" "{{ .ResourceName }}" {
+    vpc_peering_connection_id = "{{ .VPCPeeringConnectionID }}"
+    auto_accept = true
+    {{- if .AccepterOptions }}
+
+    accepter {
+      {{- template "options" .AccepterOptions }}
+    }
+    {{- end }}
+    {{- else }}
+	resource "aws_vpc_peering_connection" "{{ .ResourceName }}" {
+    vpc_id = "{{ .VPCID }}"
+    peer_vpc_id = "{{ .PeerVPCID }}"
+    {{- if .PeerOwnerID }}
+    peer_owner_id = "{{ .PeerOwnerID }}"
+    {{- end }}
+    {{- if .PeerRegion }}
+    peer_region = "{{ .PeerRegion }}"
+    {{- end }}
+    {{- if and .AccepterOptions (not .PeerOwnerID) (not .PeerRegion) }}
+
+    accepter {
+      {{- template "options" .AccepterOptions }}
+    }
+    {{- end }}
+    {{- if .RequesterOptions }}
+
+    requester {
+      {{- template "options" .RequesterOptions }}
+    }
+    {{- end }}
+    {{- end }}
+    {{- if .Tags }}
+
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, pcxs)
+}
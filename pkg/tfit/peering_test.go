@@ -0,0 +1,80 @@
+package tfit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestVPCPeeringConnectionsWriteHCL(t *testing.T) {
+	info := func(owner, vpc, region string, dns bool) *ec2.VpcPeeringConnectionVpcInfo {
+		return &ec2.VpcPeeringConnectionVpcInfo{
+			OwnerId: aws.String(owner),
+			VpcId:   aws.String(vpc),
+			Region:  aws.String(region),
+			PeeringOptions: &ec2.VpcPeeringConnectionOptionsDescription{
+				AllowDnsResolutionFromRemoteVpc: aws.Bool(dns),
+			},
+		}
+	}
+
+	src := []*ec2.VpcPeeringConnection{
+		{
+			VpcPeeringConnectionId: aws.String("pcx-1"),
+			RequesterVpcInfo:       info("111111111111", "vpc-1", "eu-west-1", true),
+			AccepterVpcInfo:        info("111111111111", "vpc-2", "eu-west-1", false),
+			Tags:                   []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("same-account")}},
+		},
+		{
+			VpcPeeringConnectionId: aws.String("pcx-2"),
+			RequesterVpcInfo:       info("111111111111", "vpc-1", "eu-west-1", false),
+			AccepterVpcInfo:        info("222222222222", "vpc-9", "us-east-1", true),
+		},
+		{
+			VpcPeeringConnectionId: aws.String("pcx-3"),
+			RequesterVpcInfo:       info("222222222222", "vpc-9", "eu-west-1", false),
+			AccepterVpcInfo:        info("111111111111", "vpc-2", "eu-west-1", true),
+		},
+	}
+
+	pcxs := VPCPeeringConnections{}
+	for _, v := range src {
+		pcx := &VPCPeeringConnection{}
+		pcx.set(v, "111111111111", "eu-west-1")
+		pcx.ResourceName = aws.StringValue(v.VpcPeeringConnectionId)
+		pcxs = append(pcxs, pcx)
+	}
+
+	rs := mustRenderHCL(t, &pcxs)
+	if got, want := addresses(rs), []string{
+		"aws_vpc_peering_connection.pcx-1",
+		"aws_vpc_peering_connection.pcx-2",
+		"aws_vpc_peering_connection_accepter.pcx-3",
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	checkAttributes(t, rs[0], map[string]string{
+		"vpc_id":        "vpc-1",
+		"peer_vpc_id":   "vpc-2",
+		"peer_owner_id": "",
+		"peer_region":   "",
+		"requester.allow_remote_vpc_dns_resolution": "true",
+		"accepter.allow_remote_vpc_dns_resolution":  "false",
+		"tags.Name": "same-account",
+	})
+	checkAttributes(t, rs[1], map[string]string{
+		"peer_vpc_id":   "vpc-9",
+		"peer_owner_id": "222222222222",
+		"peer_region":   "us-east-1",
+		"accepter.allow_remote_vpc_dns_resolution": "",
+	})
+	checkAttributes(t, rs[2], map[string]string{
+		"vpc_peering_connection_id": "pcx-3",
+		"auto_accept":               "true",
+		"vpc_id":                    "",
+		"accepter.allow_remote_vpc_dns_resolution": "true",
+	})
+}
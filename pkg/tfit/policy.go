@@ -0,0 +1,297 @@
+package tfit
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// PolicyDocument is a parsed IAM policy (identity, resource or trust policy)
+type PolicyDocument struct {
+	Version    string
+	Statements []*PolicyStatement
+}
+
+// PolicyStatement is a statement of a PolicyDocument. Principals are keyed
+// by kind (AWS, Service, Federated, ...), "*" being the anonymous principal.
+type PolicyStatement struct {
+	Sid          string
+	Effect       string
+	Principal    map[string][]string
+	NotPrincipal map[string][]string
+	Action       []string
+	NotAction    []string
+	Resource     []string
+	NotResource  []string
+	Condition    map[string]map[string]interface{}
+
+	// The statement as written, to render the policy back
+	raw json.RawMessage
+}
+
+// policyStrings is a policy element which is either a string or a list of strings
+type policyStrings []string
+
+func (p *policyStrings) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*p = []string{s}
+		return nil
+	}
+
+	var l []string
+	if err := json.Unmarshal(b, &l); err != nil {
+		return err
+	}
+	*p = l
+
+	return nil
+}
+
+// parsePrincipal reads "*" or {"AWS": "..." | [...], "Service": ...}
+func parsePrincipal(b json.RawMessage) (map[string][]string, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		return map[string][]string{s: {s}}, nil
+	}
+
+	var m map[string]policyStrings
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string][]string, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+
+	return res, nil
+}
+
+// ParsePolicyDocument parses the JSON of a policy
+func ParsePolicyDocument(src string) (*PolicyDocument, error) {
+	var doc struct {
+		Version   string
+		Statement json.RawMessage
+	}
+	if err := json.Unmarshal([]byte(src), &doc); err != nil {
+		return nil, fmt.Errorf("Error parsing policy document: %s", err)
+	}
+
+	// A single statement doesn't need to be in a list
+	var raws []json.RawMessage
+	if trimmed := strings.TrimSpace(string(doc.Statement)); strings.HasPrefix(trimmed, "{") {
+		raws = []json.RawMessage{doc.Statement}
+	} else if len(trimmed) > 0 {
+		if err := json.Unmarshal(doc.Statement, &raws); err != nil {
+			return nil, fmt.Errorf("Error parsing policy statements: %s", err)
+		}
+	}
+
+	res := &PolicyDocument{Version: doc.Version}
+	for _, raw := range raws {
+		var s struct {
+			Sid          string
+			Effect       string
+			Principal    json.RawMessage
+			NotPrincipal json.RawMessage
+			Action       policyStrings
+			NotAction    policyStrings
+			Resource     policyStrings
+			NotResource  policyStrings
+			Condition    map[string]map[string]interface{}
+		}
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, fmt.Errorf("Error parsing policy statement: %s", err)
+		}
+
+		st := &PolicyStatement{
+			Sid:         s.Sid,
+			Effect:      s.Effect,
+			Action:      s.Action,
+			NotAction:   s.NotAction,
+			Resource:    s.Resource,
+			NotResource: s.NotResource,
+			Condition:   s.Condition,
+			raw:         raw,
+		}
+
+		var err error
+		if st.Principal, err = parsePrincipal(s.Principal); err != nil {
+			return nil, fmt.Errorf("Error parsing policy principal: %s", err)
+		}
+		if st.NotPrincipal, err = parsePrincipal(s.NotPrincipal); err != nil {
+			return nil, fmt.Errorf("Error parsing policy principal: %s", err)
+		}
+
+		res.Statements = append(res.Statements, st)
+	}
+
+	return res, nil
+}
+
+// Allows tells whether the statement grants permissions
+func (s *PolicyStatement) Allows() bool {
+	return strings.EqualFold(s.Effect, "Allow")
+}
+
+// AnyPrincipal tells whether the statement applies to anyone
+// ("*" or {"AWS": "*"})
+func (s *PolicyStatement) AnyPrincipal() bool {
+	if _, ok := s.Principal["*"]; ok {
+		return true
+	}
+
+	for _, v := range s.Principal["AWS"] {
+		if v == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasCondition tells whether the statement is restricted by a condition
+func (s *PolicyStatement) HasCondition() bool {
+	return len(s.Condition) > 0
+}
+
+// Without returns the JSON of the policy without the given statements
+// and false when no statement is left
+func (d *PolicyDocument) Without(remove ...*PolicyStatement) (string, bool) {
+	skip := make(map[*PolicyStatement]bool, len(remove))
+	for _, s := range remove {
+		skip[s] = true
+	}
+
+	var kept []json.RawMessage
+	for _, s := range d.Statements {
+		if !skip[s] {
+			kept = append(kept, s.raw)
+		}
+	}
+	if len(kept) == 0 {
+		return "", false
+	}
+
+	b, err := json.MarshalIndent(struct {
+		Version   string            `json:",omitempty"`
+		Statement []json.RawMessage `json:"Statement"`
+	}{d.Version, kept}, "", "  ")
+	if err != nil {
+		return "", false
+	}
+
+	return string(b), true
+}
+
+// matchAction tells whether the action pattern (e.g. "iam:*", "s3:Get*")
+// matches the action, both being case insensitive
+func matchAction(pattern, action string) bool {
+	ok, err := path.Match(strings.ToLower(pattern), strings.ToLower(action))
+	return err == nil && ok
+}
+
+// AllowsAction tells whether the statement allows the action,
+// NotAction allowing every action it doesn't match
+func (s *PolicyStatement) AllowsAction(action string) bool {
+	if !s.Allows() {
+		return false
+	}
+
+	for _, v := range s.Action {
+		if matchAction(v, action) {
+			return true
+		}
+	}
+
+	if len(s.NotAction) == 0 {
+		return false
+	}
+	for _, v := range s.NotAction {
+		if matchAction(v, action) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AnyResource tells whether the statement applies to every resource
+func (s *PolicyStatement) AnyResource() bool {
+	for _, v := range s.Resource {
+		if v == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Admin tells whether the statement grants every action on every resource.
+// Allowing every action but a few (NotAction) is as good as admin.
+func (s *PolicyStatement) Admin() bool {
+	if !s.Allows() || !s.AnyResource() {
+		return false
+	}
+
+	if len(s.NotAction) > 0 {
+		return true
+	}
+	for _, v := range s.Action {
+		if v == "*" || v == "*:*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConditionValues returns the values of a condition key, whatever the operator
+func (s *PolicyStatement) ConditionValues(key string) []string {
+	var res []string
+	for _, keys := range s.Condition {
+		for k, v := range keys {
+			if !strings.EqualFold(k, key) {
+				continue
+			}
+
+			switch v := v.(type) {
+			case string:
+				res = append(res, v)
+			case []interface{}:
+				for _, e := range v {
+					res = append(res, fmt.Sprint(e))
+				}
+			default:
+				res = append(res, fmt.Sprint(v))
+			}
+		}
+	}
+
+	return res
+}
+
+// PrincipalAccounts returns the accounts of the AWS principals of the
+// statement, which are either account ids or ARNs
+func (s *PolicyStatement) PrincipalAccounts() []string {
+	var res []string
+	for _, v := range s.Principal["AWS"] {
+		if strings.HasPrefix(v, "arn:") {
+			if parts := strings.Split(v, ":"); len(parts) > 4 {
+				res = append(res, parts[4])
+			}
+			continue
+		}
+		if v != "*" {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
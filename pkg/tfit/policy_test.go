@@ -0,0 +1,153 @@
+package tfit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePolicyDocument(t *testing.T) {
+	cases := []struct {
+		name       string
+		src        string
+		statements int
+		first      PolicyStatement
+		err        string
+	}{
+		{
+			name: "single statement",
+			src: `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:GetObject",
+				"Resource": ["arn:aws:s3:::a/*", "arn:aws:s3:::b/*"]}}`,
+			statements: 1,
+			first: PolicyStatement{Effect: "Allow", Action: []string{"s3:GetObject"},
+				Resource: []string{"arn:aws:s3:::a/*", "arn:aws:s3:::b/*"}},
+		},
+		{
+			name: "statement list",
+			src: `{"Statement": [{"Sid": "One", "Effect": "Deny", "NotAction": ["iam:*"], "NotResource": "*"},
+				{"Effect": "Allow", "Action": "*", "Resource": "*"}]}`,
+			statements: 2,
+			first:      PolicyStatement{Sid: "One", Effect: "Deny", NotAction: []string{"iam:*"}, NotResource: []string{"*"}},
+		},
+		{
+			name: "principals",
+			src: `{"Statement": {"Effect": "Allow", "Action": "sts:AssumeRole",
+				"Principal": {"AWS": ["arn:aws:iam::123456789012:root", "210987654321"], "Service": "ec2.amazonaws.com"}}}`,
+			statements: 1,
+			first: PolicyStatement{Effect: "Allow", Action: []string{"sts:AssumeRole"}, Principal: map[string][]string{
+				"AWS":     {"arn:aws:iam::123456789012:root", "210987654321"},
+				"Service": {"ec2.amazonaws.com"},
+			}},
+		},
+		{
+			name:       "anonymous principal",
+			src:        `{"Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Principal": "*"}}`,
+			statements: 1,
+			first:      PolicyStatement{Effect: "Allow", Action: []string{"s3:GetObject"}, Principal: map[string][]string{"*": {"*"}}},
+		},
+		{
+			name:       "no statement",
+			src:        `{"Version": "2012-10-17"}`,
+			statements: 0,
+		},
+		{
+			name: "invalid JSON",
+			src:  `{"Statement": `,
+			err:  "Error parsing policy document",
+		},
+		{
+			name: "invalid action",
+			src:  `{"Statement": {"Effect": "Allow", "Action": 1}}`,
+			err:  "Error parsing policy statement",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			doc, err := ParsePolicyDocument(c.src)
+			if len(c.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Errorf("got error %v, want %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(doc.Statements) != c.statements {
+				t.Fatalf("got %d statements, want %d", len(doc.Statements), c.statements)
+			}
+			if c.statements == 0 {
+				return
+			}
+
+			got := *doc.Statements[0]
+			got.raw = nil
+			if !reflect.DeepEqual(got, c.first) {
+				t.Errorf("got %+v, want %+v", got, c.first)
+			}
+		})
+	}
+}
+
+func TestPolicyStatementAdmin(t *testing.T) {
+	cases := []struct {
+		name      string
+		statement string
+		admin     bool
+		passRole  bool
+	}{
+		{"star", `{"Effect": "Allow", "Action": "*", "Resource": "*"}`, true, true},
+		{"star colon star", `{"Effect": "Allow", "Action": ["s3:*", "*:*"], "Resource": ["*"]}`, true, true},
+		{"deny", `{"Effect": "Deny", "Action": "*", "Resource": "*"}`, false, false},
+		{"some resources", `{"Effect": "Allow", "Action": "*", "Resource": "arn:aws:s3:::a"}`, false, true},
+		{"service wildcard", `{"Effect": "Allow", "Action": "iam:*", "Resource": "*"}`, false, true},
+		{"not action", `{"Effect": "Allow", "NotAction": "iam:*", "Resource": "*"}`, true, false},
+		{"not action passrole", `{"Effect": "Allow", "NotAction": "s3:*", "Resource": "*"}`, true, true},
+		{"not action some resources", `{"Effect": "Allow", "NotAction": "s3:*", "Resource": "arn:aws:s3:::a"}`, false, true},
+		{"lower case effect", `{"Effect": "allow", "Action": "IAM:PASSROLE", "Resource": "*"}`, false, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			doc, err := ParsePolicyDocument(`{"Statement": ` + c.statement + `}`)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := doc.Statements[0]
+			if got := s.Admin(); got != c.admin {
+				t.Errorf("Admin: got %v, want %v", got, c.admin)
+			}
+			if got := s.AllowsAction("iam:PassRole"); got != c.passRole {
+				t.Errorf("AllowsAction(iam:PassRole): got %v, want %v", got, c.passRole)
+			}
+		})
+	}
+}
+
+func TestPolicyDocumentWithout(t *testing.T) {
+	doc, err := ParsePolicyDocument(`{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": "*", "Resource": "*"},
+		{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, ok := doc.Without(doc.Statements[0])
+	if !ok {
+		t.Fatal("no statement left")
+	}
+	left, err := ParsePolicyDocument(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left.Version != "2012-10-17" || len(left.Statements) != 1 || left.Statements[0].Action[0] != "s3:GetObject" {
+		t.Errorf("got %s", src)
+	}
+
+	if _, ok := doc.Without(doc.Statements...); ok {
+		t.Error("got statements left after removing all of them")
+	}
+}
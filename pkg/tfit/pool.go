@@ -0,0 +1,188 @@
+package tfit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// DefaultConcurrency is the number of AWS calls a getter runs at
+// the same time when Config.Concurrency is not set
+const DefaultConcurrency = 10
+
+// DefaultRateLimits is the maximum number of requests per second sent
+// to each AWS service (keyed by SDK service name) unless overridden
+// by Config.RateLimits
+var DefaultRateLimits = map[string]float64{
+	"autoscaling":          10,
+	"DLM":                  5,
+	"ec2":                  20,
+	"elasticloadbalancing": 10,
+	"iam":                  10,
+	"route53":              5,
+	"s3":                   50,
+	"sts":                  10,
+}
+
+// workerPool runs the per-resource calls of the getters with a bounded
+// number of goroutines, shared by all the getters of the client
+type workerPool struct {
+	size  int
+	slots chan struct{}
+}
+
+// workerKey marks the context handed to the tasks of a pool
+type workerKey struct{}
+
+func newWorkerPool(size int) *workerPool {
+	if size <= 0 {
+		size = DefaultConcurrency
+	}
+
+	return &workerPool{size: size, slots: make(chan struct{}, size)}
+}
+
+// run calls task for every index in [0, n). The first failing task
+// cancels the context handed to the others and its error is returned.
+// run always waits for the goroutines it started, so nothing is leaked
+// when it returns early.
+//
+// A task holds one of the pool slots while it runs. When run is called
+// by a task, the nested tasks run one after the other in the slot of
+// the caller, so there are never more than 'size' tasks in flight.
+func (p *workerPool) run(ctx aws.Context, n int, task func(ctx aws.Context, i int) error) error {
+	if ctx.Value(workerKey{}) == p {
+		for i := 0; i < n; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := task(ctx, i); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	taskCtx := context.WithValue(ctx, workerKey{}, p)
+
+	workers := p.size
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	jobs := make(chan int)
+	for k := 0; k < workers; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				select {
+				case p.slots <- struct{}{}:
+				case <-ctx.Done():
+					continue
+				}
+
+				err := task(taskCtx, i)
+				<-p.slots
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	return ctx.Err()
+}
+
+// rateLimiter spaces requests evenly so that
+// no more than 'rps' requests are sent per second
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(rps float64) *rateLimiter {
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / rps)}
+}
+
+// wait blocks until the next request slot or until ctx is done
+func (l *rateLimiter) wait(ctx aws.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// setRateLimit installs a rate limiter on every attempt (retries included)
+// of the requests sent by the service client
+func setRateLimit(svc *client.Client, limits map[string]float64) {
+	rps, ok := limits[svc.ServiceName]
+	if !ok {
+		rps = DefaultRateLimits[svc.ServiceName]
+	}
+
+	if rps <= 0 {
+		return
+	}
+
+	l := newRateLimiter(rps)
+	svc.Handlers.Sign.PushFrontNamed(request.NamedHandler{
+		Name: "tfit.RateLimitHandler",
+		Fn: func(r *request.Request) {
+			if err := l.wait(r.Context()); err != nil {
+				r.Error = awserr.New(request.CanceledErrorCode, "request context canceled", err)
+			}
+		},
+	})
+}
@@ -0,0 +1,74 @@
+package tfit
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestWorkerPoolNestedRun(t *testing.T) {
+	p := newWorkerPool(3)
+
+	var (
+		mu       sync.Mutex
+		inFlight int
+		max      int
+	)
+	call := func() {
+		mu.Lock()
+		inFlight++
+		if inFlight > max {
+			max = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}
+
+	var wg sync.WaitGroup
+	for k := 0; k < 2; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := p.run(aws.BackgroundContext(), 5, func(ctx aws.Context, i int) error {
+				call()
+				return p.run(ctx, 5, func(ctx aws.Context, j int) error {
+					call()
+					return nil
+				})
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if max > 3 {
+		t.Errorf("got %d calls in flight, want at most 3", max)
+	}
+}
+
+func TestWorkerPoolError(t *testing.T) {
+	p := newWorkerPool(2)
+	want := errors.New("failed")
+
+	err := p.run(aws.BackgroundContext(), 10, func(ctx aws.Context, i int) error {
+		return p.run(ctx, 3, func(ctx aws.Context, j int) error {
+			if i == 4 && j == 1 {
+				return want
+			}
+			return nil
+		})
+	})
+	if err != want {
+		t.Errorf("got %v, want %v", err, want)
+	}
+}
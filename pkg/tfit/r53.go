@@ -19,10 +19,41 @@ type Route53Zone struct {
 	DelegationSetId *string
 	NameServers     []*string
 	Tags            map[*string]*string
+	ResourceName    string
 }
 
 type Zones []*Route53Zone
 
+func (z *Route53Zone) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_route53_zone",
+		ID:   aws.StringValue(z.ZoneId),
+		Name: strings.TrimSuffix(aws.StringValue(z.Name), "."),
+		Tags: ptrTagValues(z.Tags),
+	}
+}
+
+func (zs *Zones) addToGraph(g *Graph) {
+	for _, v := range *zs {
+		g.addNode(v.nameInfo())
+	}
+}
+
+func (zs *Zones) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *zs {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (zs *Zones) addMissingTags(tags map[string]string) {
+	for _, v := range *zs {
+		v.Tags = addMissingPtrTags(v.Tags, tags)
+	}
+}
+
 func (z *Route53Zone) set(data *route53.HostedZone) {
 	z.Name = data.Name
 	z.ZoneId = getZoneId(data.Id)
@@ -32,64 +63,65 @@ func (z *Route53Zone) set(data *route53.HostedZone) {
 	}
 }
 
+// GetHostZones returns the public hosted zones. maxRoutines is kept for
+// compatibility: the concurrency is Config.Concurrency, shared by all the getters
 func (c *AWSClient) GetHostZones(maxRoutines int) (*Zones, error) {
+	return c.GetHostZonesWithContext(aws.BackgroundContext())
+}
+
+// GetHostZonesWithContext is the same as GetHostZones with the addition of
+// the ability to pass a context for cancellation
+func (c *AWSClient) GetHostZonesWithContext(ctx aws.Context) (*Zones, error) {
 	r53 := c.r53conn
 	var res Zones
 	opt := &route53.ListHostedZonesInput{}
 	for {
-		zones, err := r53.ListHostedZones(opt)
+		zones, err := r53.ListHostedZonesWithContext(ctx, opt)
 		if err != nil {
 			return nil, err
 		}
 
-		if zones != nil {
-			ch := make(chan *chanItem, len(zones.HostedZones))
-			lock := make(chan struct{}, maxRoutines)
+		// Ignore Private hosted zone
+		var public []*route53.HostedZone
+		for _, v := range zones.HostedZones {
+			if v.Config != nil && aws.BoolValue(v.Config.PrivateZone) {
+				continue
+			}
+			public = append(public, v)
+		}
 
-			for _, v := range zones.HostedZones {
-				// Ignore Private hosted zone
-				if *v.Config.PrivateZone {
-					ch <- &chanItem{obj: nil, err: fmt.Errorf("Private Zone")}
-				}
+		page := make(Zones, len(public))
+		err = c.pool.run(ctx, len(public), func(ctx aws.Context, i int) error {
+			z := &Route53Zone{}
+			z.set(public[i])
 
-				// Get lock
-				lock <- struct{}{}
-				go func(v *route53.HostedZone) {
-					z := &Route53Zone{}
-					z.set(v)
-
-					// Get tags
-					req := &route53.ListTagsForResourceInput{
-						ResourceId:   z.ZoneId,
-						ResourceType: aws.String("hostedzone"),
-					}
-
-					resp, err := r53.ListTagsForResource(req)
-					if err != nil {
-						ch <- &chanItem{obj: nil, err: err}
-					}
-					z.Tags = make(map[*string]*string)
-					if resp.ResourceTagSet != nil && resp.ResourceTagSet.Tags != nil {
-						for i := range resp.ResourceTagSet.Tags {
-							z.Tags[resp.ResourceTagSet.Tags[i].Key] = resp.ResourceTagSet.Tags[i].Value
-						}
-					}
-
-					ch <- &chanItem{obj: z, err: nil}
-					<-lock
-				}(v)
+			// Get tags
+			req := &route53.ListTagsForResourceInput{
+				ResourceId:   z.ZoneId,
+				ResourceType: aws.String("hostedzone"),
 			}
 
-			for range zones.HostedZones {
-				receiver := <-ch
-				if receiver.err != nil {
-					//return nil, receiver.err
-					// [TODO] Handing error here
-					continue
+			resp, err := r53.ListTagsForResourceWithContext(ctx, req)
+			if err != nil {
+				return err
+			}
+			z.Tags = make(map[*string]*string)
+			if resp.ResourceTagSet != nil && resp.ResourceTagSet.Tags != nil {
+				for i := range resp.ResourceTagSet.Tags {
+					z.Tags[resp.ResourceTagSet.Tags[i].Key] = resp.ResourceTagSet.Tags[i].Value
 				}
-				res = append(res, receiver.obj.(*Route53Zone))
 			}
+
+			page[i] = z
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
+		for _, v := range page {
+			v.ResourceName = c.namer.Name(v.nameInfo())
+		}
+		res = append(res, page...)
 
 		if zones.IsTruncated != nil && aws.BoolValue(zones.IsTruncated) {
 			opt.Marker = zones.NextMarker
@@ -106,8 +138,7 @@ func (zs *Zones) WriteHCL(w io.Writer) error {
 	tmpl := `
 		{{ if . }}
       {{ range . }}
-      {{- $resource_name := TrimSuffix .Name "." }}
-				resource "aws_route53_zone" "{{ replace $resource_name "." "-" -1}}" {
+				resource "aws_route53_zone" "{{ .ResourceName }}" {
 					name = "{{ .Name }}"
           {{- if .Comment }}
           comment = "{{ .Comment }}"
@@ -123,25 +154,18 @@ func (zs *Zones) WriteHCL(w io.Writer) error {
 			{{end}}
 		{{end}}
 	`
-	funcMap := template.FuncMap{
-		"replace":    strings.Replace,
-		"TrimSuffix": strings.TrimSuffix,
-	}
+	funcMap := template.FuncMap{}
 
 	return renderHCL(w, tmpl, funcMap, *zs)
 }
 
 func (z *Zones) WriteTerraformImportCmd(w io.Writer) error {
-	funcMap := template.FuncMap{
-		"replace":    strings.Replace,
-		"TrimSuffix": strings.TrimSuffix,
-	}
+	funcMap := template.FuncMap{}
 
 	tmpl := `
   {{if .}}
     {{- range .}}
-    {{- $resource_name := TrimSuffix .Name "." }}
-terraform import aws_route53_zone.{{ replace $resource_name "." "-" -1}} {{.ZoneId}}
+terraform import aws_route53_zone.{{ .ResourceName }} {{.ZoneId}}
     {{- end}}
   {{- end}}
   `
@@ -156,16 +180,55 @@ type RecordAlias struct {
 }
 
 type RecordSet struct {
-	Name    *string
-	ZoneId  *string
-	Type    *string
-	TTL     *int64
-	Records []*string
-	Alias   *RecordAlias
+	Name          *string
+	ZoneId        *string
+	Type          *string
+	SetIdentifier *string
+	TTL           *int64
+	Records       []*string
+	Alias         *RecordAlias
+	ResourceName  string
 }
 
 type RecordSets []RecordSet
 
+// ImportID is the id 'terraform import' expects for the record set
+func (r RecordSet) ImportID() string {
+	id := fmt.Sprintf("%s_%s_%s", aws.StringValue(r.ZoneId), strings.TrimSuffix(aws.StringValue(r.Name), "."), aws.StringValue(r.Type))
+	if r.SetIdentifier != nil {
+		id = fmt.Sprintf("%s_%s", id, aws.StringValue(r.SetIdentifier))
+	}
+
+	return id
+}
+
+func (r RecordSet) nameInfo() NameInfo {
+	name := fmt.Sprintf("%s-%s", strings.TrimSuffix(aws.StringValue(r.Name), "."), aws.StringValue(r.Type))
+	if r.SetIdentifier != nil {
+		name = fmt.Sprintf("%s-%s", name, aws.StringValue(r.SetIdentifier))
+	}
+
+	// Route53 returns '*' of wildcard records as "\052"
+	name = strings.Replace(name, "\\052", "wildcard", 1)
+	name = strings.Replace(name, "*", "wildcard", 1)
+
+	return NameInfo{
+		Type: "aws_route53_record",
+		ID:   r.ImportID(),
+		Name: name,
+	}
+}
+
+func (rs *RecordSets) addToGraph(g *Graph) {
+	for _, v := range *rs {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "zone", "aws_route53_zone", aws.StringValue(v.ZoneId))
+		if v.Alias != nil {
+			g.aliases = append(g.aliases, graphAlias{from: n, dnsName: aws.StringValue(v.Alias.Name)})
+		}
+	}
+}
+
 func (r *RecordSet) setAlias(src *route53.AliasTarget) {
 	r.Alias = &RecordAlias{}
 	r.Alias.ZoneId = src.HostedZoneId
@@ -180,12 +243,34 @@ func (r *RecordSet) setValue(src []*route53.ResourceRecord) {
 }
 
 func (c *AWSClient) GetResourceRecordSets(ZoneId *string) (*RecordSets, error) {
+	return c.GetResourceRecordSetsWithContext(aws.BackgroundContext(), ZoneId)
+}
+
+func (c *AWSClient) GetResourceRecordSetsWithContext(ctx aws.Context, ZoneId *string) (*RecordSets, error) {
+	results, err := c.getResourceRecordSets(ctx, ZoneId)
+	if err != nil {
+		return nil, err
+	}
+
+	results.setNames(c.namer)
+	return results, nil
+}
+
+// setNames names the record sets once all of them are known
+// so that clashing names get their suffix in a stable order
+func (rs *RecordSets) setNames(n *Namer) {
+	for i := range *rs {
+		(*rs)[i].ResourceName = n.Name((*rs)[i].nameInfo())
+	}
+}
+
+func (c *AWSClient) getResourceRecordSets(ctx aws.Context, ZoneId *string) (*RecordSets, error) {
 	r53 := c.r53conn
 	results := RecordSets{}
 
 	opt := &route53.ListResourceRecordSetsInput{HostedZoneId: ZoneId}
 	for {
-		records, err := r53.ListResourceRecordSets(opt)
+		records, err := r53.ListResourceRecordSetsWithContext(ctx, opt)
 		if err != nil {
 			return nil, err
 		}
@@ -204,6 +289,7 @@ func (c *AWSClient) GetResourceRecordSets(ZoneId *string) (*RecordSets, error) {
 			r.Name = v.Name
 			r.TTL = v.TTL
 			r.Type = v.Type
+			r.SetIdentifier = v.SetIdentifier
 
 			results = append(results, r)
 		}
@@ -219,24 +305,39 @@ func (c *AWSClient) GetResourceRecordSets(ZoneId *string) (*RecordSets, error) {
 }
 
 func (c *AWSClient) GetAllResourceRecordSets() (*RecordSets, error) {
-	// Get all hosted zones
-	zones, err := c.GetHostZones(5)
-	results := RecordSets{}
+	return c.GetAllResourceRecordSetsWithContext(aws.BackgroundContext())
+}
 
+// GetAllResourceRecordSetsWithContext is the same as GetAllResourceRecordSets
+// with the addition of the ability to pass a context for cancellation
+func (c *AWSClient) GetAllResourceRecordSetsWithContext(ctx aws.Context) (*RecordSets, error) {
+	// Get all hosted zones
+	zones, err := c.GetHostZonesWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, v := range []*Route53Zone(*zones) {
-		//		prettyJson, err := url.QueryUnescape(aws.StringValue([]*Policy(*polices)[0].Document))
-		zId := v.ZoneId
-		r, err := c.GetResourceRecordSets(zId)
+	perZone := make([]*RecordSets, len(*zones))
+	err = c.pool.run(ctx, len(*zones), func(ctx aws.Context, i int) error {
+		r, err := c.getResourceRecordSets(ctx, (*zones)[i].ZoneId)
 		if err != nil {
-			return nil, err
+			return err
 		}
+
+		perZone[i] = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := RecordSets{}
+	for _, r := range perZone {
 		results = append(results, []RecordSet(*r)...)
 	}
 
+	results.setNames(c.namer)
+
 	return &results, nil
 
 }
@@ -253,8 +354,7 @@ func (rs *RecordSets) WriteTerraformImportCmd(w io.Writer) error {
 	tmpl := `
   {{if .}}
     {{range .}}
-    {{- $resource_name := TrimSuffix .Name "." }}
-terraform import aws_route53_record.{{ replace $resource_name "." "_" -1 }}-{{.Type}} {{.ZoneId}}_{{TrimSuffix .Name "."}}_{{.Type}}
+terraform import aws_route53_record.{{ .ResourceName }} {{ .ImportID }}
     {{- end}}
   {{- end}}
   `
@@ -275,8 +375,7 @@ func (rs *RecordSets) WriteHCL(w io.Writer) error {
 	tmpl := `
 	{{if . }}
     {{ range . }}
-    {{- $resource_name := TrimSuffix .Name "." }}
-			resource "aws_route53_record" "{{ replace $resource_name "." "_" -1 }}-{{.Type}}" {
+			resource "aws_route53_record" "{{ .ResourceName }}" {
 				zone_id = "{{ .ZoneId }}"
 				name = "{{.Name}}"
         type = "{{.Type}}"
@@ -0,0 +1,352 @@
+package tfit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/parser"
+	"github.com/hashicorp/hcl/hcl/printer"
+	"github.com/hashicorp/hcl/hcl/token"
+)
+
+// Resource is a `resource` block of a Terraform configuration,
+// either rendered by tfit or written by hand
+type Resource struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+
+	// AWS id of the resource (the import id), only known
+	// for the resources returned by the getters
+	ID string `json:"id,omitempty"`
+
+	// Where the block was found, for configuration files
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+
+	// The literal attributes of the block. Nested blocks are flattened
+	// with dots (e.g. "versioning.enabled"), repeated blocks are lists of
+	// maps and attributes using interpolations are left out.
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+
+	// The parsed block
+	Node *ast.ObjectItem `json:"-"`
+}
+
+type Resources []*Resource
+
+// Address returns the Terraform address of the resource, e.g. aws_vpc.main
+func (r *Resource) Address() string {
+	return r.Type + "." + r.Name
+}
+
+// Location returns file:line of the resource block
+func (r *Resource) Location() string {
+	if len(r.File) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%d", r.File, r.Line)
+}
+
+// Types returns the sorted resource types of the resources
+func (rs Resources) Types() []string {
+	seen := make(map[string]bool)
+	var res []string
+	for _, r := range rs {
+		if !seen[r.Type] {
+			seen[r.Type] = true
+			res = append(res, r.Type)
+		}
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// OfType returns the resources of the given type
+func (rs Resources) OfType(resourceType string) Resources {
+	var res Resources
+	for _, r := range rs {
+		if r.Type == resourceType {
+			res = append(res, r)
+		}
+	}
+
+	return res
+}
+
+// WriteHCL prints the parsed resource blocks back
+func (rs Resources) WriteHCL(w io.Writer) error {
+	for i, r := range rs {
+		if r.Node == nil {
+			continue
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n\n"); err != nil {
+				return err
+			}
+		}
+		if err := printer.Fprint(w, r.Node); err != nil {
+			return err
+		}
+	}
+
+	if len(rs) > 0 {
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+
+	return nil
+}
+
+// Terraform meta-arguments which are not attributes of the resource
+var metaArguments = map[string]struct{}{
+	"count":       {},
+	"for_each":    {},
+	"provider":    {},
+	"depends_on":  {},
+	"lifecycle":   {},
+	"provisioner": {},
+	"connection":  {},
+}
+
+// ParseResources returns the resource blocks of a HCL document
+func ParseResources(src []byte, filename string) (Resources, error) {
+	f, err := parser.Parse(src)
+	if err != nil {
+		if len(filename) > 0 {
+			return nil, fmt.Errorf("Error parsing %s: %s", filename, err)
+		}
+		return nil, fmt.Errorf("Error parsing HCL: %s", err)
+	}
+
+	list, ok := f.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, nil
+	}
+
+	var res Resources
+	for _, item := range list.Items {
+		if len(item.Keys) != 3 || keyName(item.Keys[0]) != "resource" {
+			continue
+		}
+
+		body, ok := item.Val.(*ast.ObjectType)
+		if !ok {
+			continue
+		}
+
+		r := &Resource{
+			Type:       keyName(item.Keys[1]),
+			Name:       keyName(item.Keys[2]),
+			File:       filename,
+			Attributes: make(map[string]interface{}),
+			Node:       item,
+		}
+		if len(filename) > 0 {
+			r.Line = item.Pos().Line
+		}
+
+		flattenObject(r.Attributes, "", body.List, true)
+		res = append(res, r)
+	}
+
+	return res, nil
+}
+
+// ParseConfigDir returns the resources declared in the .tf files
+// of a directory (like Terraform, sub-directories are not read)
+func ParseConfigDir(dir string) (Resources, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var res Resources
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading %s: %s", file, err)
+		}
+
+		rs, err := ParseResources(src, file)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, rs...)
+	}
+
+	return res, nil
+}
+
+func keyName(k *ast.ObjectKey) string {
+	return fmt.Sprint(k.Token.Value())
+}
+
+// flattenObject adds the literal attributes of an object to 'dst'
+func flattenObject(dst map[string]interface{}, prefix string, list *ast.ObjectList, top bool) {
+	blocks := make(map[string][]*ast.ObjectItem)
+	for _, item := range list.Items {
+		if _, ok := item.Val.(*ast.ObjectType); ok {
+			blocks[itemKey(item)] = append(blocks[itemKey(item)], item)
+		}
+	}
+
+	for _, item := range list.Items {
+		key := itemKey(item)
+		if _, skip := metaArguments[key]; skip && top {
+			continue
+		}
+
+		obj, isBlock := item.Val.(*ast.ObjectType)
+		switch {
+		case isBlock && len(blocks[key]) > 1:
+			// Repeated blocks (ingress, cors_rule, ...) are kept
+			// as a list, when all of them are literal
+			if _, done := dst[prefix+key]; done || blocks[key][0] != item {
+				continue
+			}
+			if v, ok := literalValue(&ast.ListType{List: blockValues(blocks[key])}); ok {
+				dst[prefix+key] = v
+			}
+		case isBlock:
+			flattenObject(dst, prefix+key+".", obj.List, false)
+		default:
+			if v, ok := literalValue(item.Val); ok {
+				dst[prefix+key] = v
+			}
+		}
+	}
+}
+
+func blockValues(items []*ast.ObjectItem) []ast.Node {
+	res := make([]ast.Node, len(items))
+	for i, item := range items {
+		res[i] = item.Val
+	}
+
+	return res
+}
+
+// itemKey joins the keys of an item, e.g. `tags { }` or `a "b" { }`
+func itemKey(item *ast.ObjectItem) string {
+	keys := make([]string, len(item.Keys))
+	for i, k := range item.Keys {
+		keys[i] = keyName(k)
+	}
+
+	return strings.Join(keys, ".")
+}
+
+// literalObject returns an object as a map, if all its values are literals
+func literalObject(list *ast.ObjectList) (map[string]interface{}, bool) {
+	res := make(map[string]interface{})
+	for _, item := range list.Items {
+		v, ok := literalValue(item.Val)
+		if !ok {
+			return nil, false
+		}
+
+		key := itemKey(item)
+		if prev, dup := res[key]; dup {
+			// Repeated nested blocks
+			if l, isList := prev.([]interface{}); isList {
+				res[key] = append(l, v)
+			} else {
+				res[key] = []interface{}{prev, v}
+			}
+			continue
+		}
+		res[key] = v
+	}
+
+	return res, true
+}
+
+// literalValue returns the Go value of a node which
+// doesn't use any interpolation
+func literalValue(n ast.Node) (interface{}, bool) {
+	switch v := n.(type) {
+	case *ast.LiteralType:
+		switch v.Token.Type {
+		case token.STRING, token.HEREDOC:
+			s := v.Token.Value().(string)
+			if strings.Contains(s, "${") {
+				return nil, false
+			}
+			return s, true
+		case token.NUMBER, token.FLOAT, token.BOOL:
+			return v.Token.Value(), true
+		}
+	case *ast.ListType:
+		res := make([]interface{}, 0, len(v.List))
+		for _, e := range v.List {
+			ev, ok := literalValue(e)
+			if !ok {
+				return nil, false
+			}
+			res = append(res, ev)
+		}
+		return res, true
+	case *ast.ObjectType:
+		return literalObject(v.List)
+	}
+
+	return nil, false
+}
+
+// canonicalValue returns a string comparing equal for equivalent values:
+// scalars are compared as strings (like Terraform does), lists as sets
+// and JSON documents (policies) regardless of their formatting
+func canonicalValue(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		s := strings.TrimSpace(t)
+		if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+			var doc interface{}
+			if err := json.Unmarshal([]byte(s), &doc); err == nil {
+				b, _ := json.Marshal(doc)
+				return string(b)
+			}
+		}
+		return s
+	case []interface{}:
+		items := make([]string, len(t))
+		for i, e := range t {
+			items[i] = canonicalValue(e)
+		}
+		sort.Strings(items)
+		b, _ := json.Marshal(items)
+		return string(b)
+	case map[string]interface{}:
+		m := make(map[string]string, len(t))
+		for k, e := range t {
+			m[k] = canonicalValue(e)
+		}
+		b, _ := json.Marshal(m)
+		return string(b)
+	case nil:
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+// renderResources renders a collection and parses the HCL back
+func renderResources(col Collection) (Resources, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := col.WriteHCL(buf); err != nil {
+		return nil, err
+	}
+
+	return ParseResources(buf.Bytes(), "")
+}
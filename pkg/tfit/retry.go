@@ -0,0 +1,182 @@
+package tfit
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// Retry policy used when the matching Config fields are not set
+const (
+	DefaultMaxRetries    = 10
+	DefaultMinRetryDelay = 500 * time.Millisecond
+	DefaultMaxRetryDelay = 30 * time.Second
+)
+
+// Throttling codes on top of the ones the SDK already knows about
+var extraThrottleCodes = map[string]struct{}{
+	"SlowDown":                  {}, // S3
+	"EC2ThrottledException":     {},
+	"RequestThrottledException": {},
+	"BandwidthLimitExceeded":    {},
+	"Throttled":                 {},
+}
+
+// RetryStats is the number of retries an AWS service needed during a run
+type RetryStats struct {
+	Retries   int
+	Throttles int
+}
+
+// retryer retries throttled and transient failures using
+// jittered exponential backoff, honoring Retry-After,
+// and counts the retries of every service
+type retryer struct {
+	client.DefaultRetryer
+	minDelay time.Duration
+	maxDelay time.Duration
+
+	logger Logger
+
+	mu    sync.Mutex
+	rand  *rand.Rand
+	stats map[string]*RetryStats
+}
+
+func newRetryer(c *Config) *retryer {
+	r := &retryer{
+		DefaultRetryer: client.DefaultRetryer{NumMaxRetries: c.MaxRetries},
+		minDelay:       c.MinRetryDelay,
+		maxDelay:       c.MaxRetryDelay,
+		logger:         c.logger(),
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		stats:          make(map[string]*RetryStats),
+	}
+
+	if r.NumMaxRetries <= 0 {
+		r.NumMaxRetries = DefaultMaxRetries
+	}
+	if r.minDelay <= 0 {
+		r.minDelay = DefaultMinRetryDelay
+	}
+	if r.maxDelay <= 0 {
+		r.maxDelay = DefaultMaxRetryDelay
+	}
+	if r.maxDelay < r.minDelay {
+		r.maxDelay = r.minDelay
+	}
+
+	return r
+}
+
+func (r *retryer) ShouldRetry(req *request.Request) bool {
+	return r.DefaultRetryer.ShouldRetry(req) || isThrottle(req)
+}
+
+// RetryRules is only called for requests which are going to be retried,
+// which makes it the place to collect the retry statistics
+func (r *retryer) RetryRules(req *request.Request) time.Duration {
+	throttled := isThrottle(req)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.stats[req.ClientInfo.ServiceName]
+	if !ok {
+		s = &RetryStats{}
+		r.stats[req.ClientInfo.ServiceName] = s
+	}
+	s.Retries++
+	if throttled {
+		s.Throttles++
+	}
+
+	delay := r.delay(req)
+	r.logger.Log(LevelDebug, "retrying request", "service", req.ClientInfo.ServiceName, "operation", req.Operation.Name,
+		"retry", req.RetryCount+1, "throttled", throttled, "delay", delay)
+
+	return delay
+}
+
+func (r *retryer) delay(req *request.Request) time.Duration {
+	// A server asking for more than max doesn't get to stall the run
+	if delay, ok := retryAfter(req); ok {
+		if delay > r.maxDelay {
+			return r.maxDelay
+		}
+		return delay
+	}
+
+	// Exponential backoff with "equal jitter": wait between
+	// half and the whole of min * 2^retries, capped at max
+	ceiling := r.maxDelay
+	if req.RetryCount < 30 {
+		if d := r.minDelay << uint(req.RetryCount); d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+
+	half := ceiling / 2
+	return half + time.Duration(r.rand.Int63n(int64(half)+1))
+}
+
+func (r *retryer) snapshot() map[string]RetryStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make(map[string]RetryStats, len(r.stats))
+	for k, v := range r.stats {
+		res[k] = *v
+	}
+
+	return res
+}
+
+func isThrottle(req *request.Request) bool {
+	if req.IsErrorThrottle() {
+		return true
+	}
+
+	if req.HTTPResponse != nil && req.HTTPResponse.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if awsErr, ok := req.Error.(awserr.Error); ok {
+		_, found := extraThrottleCodes[awsErr.Code()]
+		return found
+	}
+
+	return false
+}
+
+// retryAfter reads the Retry-After header (RFC 7231), either
+// a number of seconds or an HTTP date
+func retryAfter(req *request.Request) (time.Duration, bool) {
+	if req.HTTPResponse == nil {
+		return 0, false
+	}
+
+	v := req.HTTPResponse.Header.Get("Retry-After")
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
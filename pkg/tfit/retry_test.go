@@ -0,0 +1,110 @@
+package tfit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+func requestWithRetryAfter(v string) *request.Request {
+	req := &request.Request{HTTPResponse: &http.Response{Header: http.Header{}}}
+	if len(v) > 0 {
+		req.HTTPResponse.Header.Set("Retry-After", v)
+	}
+
+	return req
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"missing", "", 0, false},
+		{"seconds", "3", 3 * time.Second, true},
+		{"zero", "0", 0, true},
+		{"negative", "-1", 0, false},
+		{"past date", "Mon, 02 Jan 2006 15:04:05 GMT", 0, true},
+		{"invalid", "soon", 0, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := retryAfter(requestWithRetryAfter(c.header))
+			if got != c.want || ok != c.ok {
+				t.Errorf("got (%v, %v), want (%v, %v)", got, ok, c.want, c.ok)
+			}
+		})
+	}
+
+	t.Run("future date", func(t *testing.T) {
+		date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+		got, ok := retryAfter(requestWithRetryAfter(date))
+		if !ok || got <= 58*time.Second || got > time.Minute {
+			t.Errorf("got (%v, %v), want about 1m", got, ok)
+		}
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		if _, ok := retryAfter(&request.Request{}); ok {
+			t.Error("got a delay without response")
+		}
+	})
+}
+
+func TestRetryerDelay(t *testing.T) {
+	r := newRetryer(&Config{MinRetryDelay: time.Second, MaxRetryDelay: 10 * time.Second})
+
+	cases := []struct {
+		name       string
+		retryCount int
+		header     string
+		min, max   time.Duration
+	}{
+		{"first retry", 0, "", 500 * time.Millisecond, time.Second},
+		{"third retry", 2, "", 2 * time.Second, 4 * time.Second},
+		{"capped", 10, "", 5 * time.Second, 10 * time.Second},
+		{"no overflow", 100, "", 5 * time.Second, 10 * time.Second},
+		{"retry-after", 0, "7", 7 * time.Second, 7 * time.Second},
+		{"retry-after over max", 0, "120", 10 * time.Second, 10 * time.Second},
+		{"retry-after date over max", 0, time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), 10 * time.Second, 10 * time.Second},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := requestWithRetryAfter(c.header)
+			req.RetryCount = c.retryCount
+			for k := 0; k < 20; k++ {
+				if got := r.delay(req); got < c.min || got > c.max {
+					t.Fatalf("got %v, want between %v and %v", got, c.min, c.max)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRetryerDefaults(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   Config
+		retries  int
+		min, max time.Duration
+	}{
+		{"defaults", Config{}, DefaultMaxRetries, DefaultMinRetryDelay, DefaultMaxRetryDelay},
+		{"set", Config{MaxRetries: 3, MinRetryDelay: time.Second, MaxRetryDelay: time.Minute}, 3, time.Second, time.Minute},
+		{"max below min", Config{MinRetryDelay: time.Minute, MaxRetryDelay: time.Second}, DefaultMaxRetries, time.Minute, time.Minute},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := newRetryer(&c.config)
+			if r.NumMaxRetries != c.retries || r.minDelay != c.min || r.maxDelay != c.max {
+				t.Errorf("got (%d, %v, %v), want (%d, %v, %v)", r.NumMaxRetries, r.minDelay, r.maxDelay, c.retries, c.min, c.max)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package tfit
 
 import (
 	"io"
-	"strings"
 	"text/template"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -33,12 +32,28 @@ type Bucket struct {
 	CORSRules                         []*s3.CORSRule
 	Logging                           *s3.LoggingEnabled
 	Versioning                        *BucketVersioning
+
+	ResourceName string
 }
 
 type Buckets []*Bucket
 
-func (b *Bucket) getBucketPoliy(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketPolicy(&s3.GetBucketPolicyInput{Bucket: b.Name})
+func (b *Bucket) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_s3_bucket",
+		ID:   aws.StringValue(b.Name),
+		Name: aws.StringValue(b.Name),
+	}
+}
+
+func (b *Buckets) addToGraph(g *Graph) {
+	for _, v := range *b {
+		g.addNode(v.nameInfo())
+	}
+}
+
+func (b *Bucket) getBucketPoliy(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3conn.GetBucketPolicyWithContext(ctx, &s3.GetBucketPolicyInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
@@ -48,8 +63,8 @@ func (b *Bucket) getBucketPoliy(c *AWSClient) error {
 	return nil
 }
 
-func (b *Bucket) getWebsite(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketWebsite(&s3.GetBucketWebsiteInput{Bucket: b.Name})
+func (b *Bucket) getWebsite(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3conn.GetBucketWebsiteWithContext(ctx, &s3.GetBucketWebsiteInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
@@ -76,8 +91,8 @@ func (b *Bucket) setLifecycleRule(src []*s3.LifecycleRule) error {
 	return nil
 }
 
-func (b *Bucket) getBucketLocation(c *AWSClient) (*string, error) {
-	output, err := c.s3conn.GetBucketLocation(&s3.GetBucketLocationInput{Bucket: b.Name})
+func (b *Bucket) getBucketLocation(ctx aws.Context, c *AWSClient) (*string, error) {
+	output, err := c.s3conn.GetBucketLocationWithContext(ctx, &s3.GetBucketLocationInput{Bucket: b.Name})
 	if err != nil {
 		e := handleError(err)
 		if e != nil {
@@ -91,8 +106,8 @@ func (b *Bucket) getBucketLocation(c *AWSClient) (*string, error) {
 
 }
 
-func (b *Bucket) getReplicationConfiguration(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketReplication(&s3.GetBucketReplicationInput{Bucket: b.Name})
+func (b *Bucket) getReplicationConfiguration(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3conn.GetBucketReplicationWithContext(ctx, &s3.GetBucketReplicationInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
@@ -101,8 +116,8 @@ func (b *Bucket) getReplicationConfiguration(c *AWSClient) error {
 	return nil
 }
 
-func (b *Bucket) getLifecycleRules(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketLifecycleConfiguration(&s3.GetBucketLifecycleConfigurationInput{Bucket: b.Name})
+func (b *Bucket) getLifecycleRules(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3conn.GetBucketLifecycleConfigurationWithContext(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
@@ -110,8 +125,8 @@ func (b *Bucket) getLifecycleRules(c *AWSClient) error {
 	return b.setLifecycleRule(output.Rules)
 }
 
-func (b *Bucket) getServerSideEncryptionConfiguration(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketEncryption(&s3.GetBucketEncryptionInput{Bucket: b.Name})
+func (b *Bucket) getServerSideEncryptionConfiguration(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3conn.GetBucketEncryptionWithContext(ctx, &s3.GetBucketEncryptionInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
@@ -121,8 +136,8 @@ func (b *Bucket) getServerSideEncryptionConfiguration(c *AWSClient) error {
 	return nil
 }
 
-func (b *Bucket) getLogging(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketLogging(&s3.GetBucketLoggingInput{Bucket: b.Name})
+func (b *Bucket) getLogging(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3conn.GetBucketLoggingWithContext(ctx, &s3.GetBucketLoggingInput{Bucket: b.Name})
 	if err != nil {
 		return err
 	}
@@ -132,8 +147,8 @@ func (b *Bucket) getLogging(c *AWSClient) error {
 	return nil
 }
 
-func (b *Bucket) getCORSRule(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketCors(&s3.GetBucketCorsInput{Bucket: b.Name})
+func (b *Bucket) getCORSRule(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3conn.GetBucketCorsWithContext(ctx, &s3.GetBucketCorsInput{Bucket: b.Name})
 	if err != nil {
 		return handleError(err)
 	}
@@ -142,8 +157,8 @@ func (b *Bucket) getCORSRule(c *AWSClient) error {
 	return nil
 }
 
-func (b *Bucket) getVersioning(c *AWSClient) error {
-	output, err := c.s3conn.GetBucketVersioning(&s3.GetBucketVersioningInput{Bucket: b.Name})
+func (b *Bucket) getVersioning(ctx aws.Context, c *AWSClient) error {
+	output, err := c.s3conn.GetBucketVersioningWithContext(ctx, &s3.GetBucketVersioningInput{Bucket: b.Name})
 	if err != nil {
 		return err
 	}
@@ -162,43 +177,47 @@ func (b *Bucket) getVersioning(c *AWSClient) error {
 }
 
 func (b *Bucket) GetBucketDetails(c *AWSClient) error {
+	return b.GetBucketDetailsWithContext(aws.BackgroundContext(), c)
+}
+
+func (b *Bucket) GetBucketDetailsWithContext(ctx aws.Context, c *AWSClient) error {
 	// Get Bucket Policy
-	if err := b.getBucketPoliy(c); err != nil {
+	if err := b.getBucketPoliy(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Website detail
-	if err := b.getWebsite(c); err != nil {
+	if err := b.getWebsite(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Lifecycle Rules
-	if err := b.getLifecycleRules(c); err != nil {
+	if err := b.getLifecycleRules(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Replication Configuration
-	if err := b.getReplicationConfiguration(c); err != nil {
+	if err := b.getReplicationConfiguration(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Server Side Encryption
-	if err := b.getServerSideEncryptionConfiguration(c); err != nil {
+	if err := b.getServerSideEncryptionConfiguration(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Logging
-	if err := b.getLogging(c); err != nil {
+	if err := b.getLogging(ctx, c); err != nil {
 		return err
 	}
 
 	// Get CORS Rules
-	if err := b.getCORSRule(c); err != nil {
+	if err := b.getCORSRule(ctx, c); err != nil {
 		return err
 	}
 
 	// Get Versioning
-	if err := b.getVersioning(c); err != nil {
+	if err := b.getVersioning(ctx, c); err != nil {
 		return err
 	}
 
@@ -206,61 +225,47 @@ func (b *Bucket) GetBucketDetails(c *AWSClient) error {
 }
 
 func (c *AWSClient) GetBuckets() (*Buckets, error) {
+	return c.GetBucketsWithContext(aws.BackgroundContext())
+}
+
+// GetBucketsWithContext is the same as GetBuckets with the addition of
+// the ability to pass a context for cancellation
+func (c *AWSClient) GetBucketsWithContext(ctx aws.Context) (*Buckets, error) {
 	var res Buckets
-	output, err := c.s3conn.ListBuckets(&s3.ListBucketsInput{})
+	output, err := c.s3conn.ListBucketsWithContext(ctx, &s3.ListBucketsInput{})
 	if err != nil {
 		return nil, err
 	}
 
-	/*
-		bucketName := "internal.sw.prd.cost-management"
-		bucket := &Bucket{Name: aws.String(bucketName)}
-		if err := bucket.GetBucketDetails(c); err != nil {
-			return nil, err
+	buckets := make([]*Bucket, len(output.Buckets))
+	err = c.pool.run(ctx, len(output.Buckets), func(ctx aws.Context, i int) error {
+		bucket := &Bucket{Name: output.Buckets[i].Name}
+		region, err := bucket.getBucketLocation(ctx, c)
+		if err != nil {
+			return err
 		}
-		res = append(res, bucket)
-	*/
-	ch := make(chan *chanItem, len(output.Buckets))
-	blk := make(chan struct{}, 10)
-
-	for _, obj := range output.Buckets {
-		blk <- struct{}{}
-		go func(obj *s3.Bucket) {
-			bucket := &Bucket{Name: obj.Name}
-			region, err := bucket.getBucketLocation(c)
-			if err != nil {
-				panic(err)
-			}
-
-			// Ignore buckets in different region now
-			if region != nil {
-				ch <- &chanItem{}
-				return
-			}
-
-			if err := bucket.GetBucketDetails(c); err != nil {
-				ch <- &chanItem{err: err}
-				return
-			}
-
-			ch <- &chanItem{obj: bucket, err: err}
-			<-blk
-
-		}(obj)
 
-	}
-
-	for range output.Buckets {
-		receiver := <-ch
-		if receiver.err != nil {
-			return nil, err
+		// Ignore buckets in different region now
+		if region != nil {
+			return nil
 		}
 
-		if receiver.obj == nil {
-			continue
+		if err := bucket.GetBucketDetailsWithContext(ctx, c); err != nil {
+			return err
 		}
 
-		res = append(res, receiver.obj.(*Bucket))
+		buckets[i] = bucket
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range buckets {
+		if v != nil {
+			v.ResourceName = c.namer.Name(v.nameInfo())
+			res = append(res, v)
+		}
 	}
 
 	return &res, nil
@@ -270,14 +275,13 @@ func (b *Buckets) WriteHCL(w io.Writer) error {
 	funcMap := template.FuncMap{
 		"joinstring":       joinStringSlice,
 		"StringValueSlice": aws.StringValueSlice,
-		"replace":          strings.Replace,
 		"prettyJSON":       prettyJSON,
 	}
 
 	tmpl := `
   {{- if .}}
     {{- range .}}
-    resource "aws_s3_bucket" "{{ replace .Name "." "_" -1 }}" {
+    resource "aws_s3_bucket" "{{ .ResourceName }}" {
       bucket = "{{ .Name }}"
 
       {{- if .Logging}}
@@ -0,0 +1,216 @@
+package tfit
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+//**************** Security Group Rule ****************
+
+// StandaloneSecurityGroupRule is a rule of a security group exported as
+// aws_security_group_rule. A permission of the group becomes a rule for
+// its CIDR blocks & prefix lists and a rule per source group.
+type StandaloneSecurityGroupRule struct {
+	Type            string
+	SecurityGroupID *string
+	FromPort        int64
+	ToPort          int64
+	Protocol        *string
+	CIDRBlocks      []*string
+	IPv6CIDRBlocks  []*string
+	PrefixListIDs   []*string
+	Self            bool
+
+	// The source group, "account/group" for the groups of other accounts
+	SourceSecurityGroupID *string
+
+	// References to the exported groups, e.g. ${aws_security_group.web.id}
+	SecurityGroupRef       string
+	SourceSecurityGroupRef string
+
+	ResourceName string
+}
+
+type StandaloneSecurityGroupRules []*StandaloneSecurityGroupRule
+
+func (sg *SecurityGroup) reference() string {
+	return fmt.Sprintf("${%s.%s.id}", sg.nameInfo().Type, sg.ResourceName)
+}
+
+// setStandaloneRules builds the rules of the groups, but the default ones:
+// aws_default_security_group removes the rules it doesn't declare
+func (sg *SecurityGroups) setStandaloneRules(namer *Namer) {
+	groups := make(map[string]*SecurityGroup)
+	for _, v := range *sg {
+		groups[aws.StringValue(v.GroupId)] = v
+	}
+
+	for _, v := range *sg {
+		if v.Default {
+			continue
+		}
+
+		for _, r := range v.Ingresses {
+			v.rules = append(v.rules, newStandaloneSecurityGroupRules(v, "ingress", r, groups)...)
+		}
+		for _, r := range v.Egresses {
+			v.rules = append(v.rules, newStandaloneSecurityGroupRules(v, "egress", r, groups)...)
+		}
+
+		for _, r := range v.rules {
+			r.ResourceName = namer.Name(r.nameInfo(v.ResourceName))
+		}
+	}
+}
+
+func newStandaloneSecurityGroupRules(sg *SecurityGroup, ruleType string, src *SecurityGroupRule, groups map[string]*SecurityGroup) []*StandaloneSecurityGroupRule {
+	var res []*StandaloneSecurityGroupRule
+	rule := func() *StandaloneSecurityGroupRule {
+		return &StandaloneSecurityGroupRule{
+			Type:             ruleType,
+			SecurityGroupID:  sg.GroupId,
+			FromPort:         aws.Int64Value(src.FromPort),
+			ToPort:           aws.Int64Value(src.ToPort),
+			Protocol:         src.IpProtocol,
+			SecurityGroupRef: sg.reference(),
+		}
+	}
+
+	if len(src.CIDRBlocks) > 0 || len(src.IPv6CIDRBlock) > 0 || len(src.PrefixListIds) > 0 {
+		r := rule()
+		r.CIDRBlocks = src.CIDRBlocks
+		r.IPv6CIDRBlocks = src.IPv6CIDRBlock
+		r.PrefixListIDs = src.PrefixListIds
+		res = append(res, r)
+	}
+
+	for _, id := range src.SourceSecurityGroups {
+		r := rule()
+		if aws.StringValue(id) == aws.StringValue(sg.GroupId) {
+			r.Self = true
+		} else {
+			r.SourceSecurityGroupID = id
+			if g, ok := groups[aws.StringValue(id)]; ok {
+				r.SourceSecurityGroupRef = g.reference()
+			}
+		}
+		res = append(res, r)
+	}
+
+	return res
+}
+
+// id is the one Terraform gives to the rule: a hash of
+// the group, the ports, the protocol, the type & the sources
+func (r *StandaloneSecurityGroupRule) id() string {
+	key := fmt.Sprintf("%s-", aws.StringValue(r.SecurityGroupID))
+	if r.FromPort > 0 {
+		key += fmt.Sprintf("%d-", r.FromPort)
+	}
+	if r.ToPort > 0 {
+		key += fmt.Sprintf("%d-", r.ToPort)
+	}
+	key += fmt.Sprintf("%s-%s-", aws.StringValue(r.Protocol), r.Type)
+
+	for _, values := range [][]*string{r.CIDRBlocks, r.IPv6CIDRBlocks, r.PrefixListIDs} {
+		s := aws.StringValueSlice(values)
+		sort.Strings(s)
+		for _, v := range s {
+			key += v + "-"
+		}
+	}
+
+	// Only the group id of a source, the group name is unset in a VPC
+	if r.Self {
+		key += aws.StringValue(r.SecurityGroupID) + "--"
+	} else if r.SourceSecurityGroupID != nil {
+		parts := strings.Split(aws.StringValue(r.SourceSecurityGroupID), "/")
+		key += parts[len(parts)-1] + "--"
+	}
+
+	return fmt.Sprintf("sgrule-%d", crc32.ChecksumIEEE([]byte(key)))
+}
+
+func (r *StandaloneSecurityGroupRule) nameInfo(group string) NameInfo {
+	protocol := aws.StringValue(r.Protocol)
+	if protocol == "-1" {
+		protocol = "all"
+	}
+
+	return NameInfo{
+		Type: "aws_security_group_rule",
+		ID:   r.id(),
+		Name: fmt.Sprintf("%s-%s-%s-%d-%d", group, r.Type, protocol, r.FromPort, r.ToPort),
+	}
+}
+
+func (rules *StandaloneSecurityGroupRules) addToGraph(g *Graph) {
+	for _, v := range *rules {
+		n := g.addNode(v.nameInfo(""))
+		g.addEdge(n, "security_group", "aws_security_group", aws.StringValue(v.SecurityGroupID))
+		if !strings.Contains(aws.StringValue(v.SourceSecurityGroupID), "/") {
+			g.addEdge(n, "source_security_group", "aws_security_group", aws.StringValue(v.SourceSecurityGroupID))
+		}
+	}
+}
+
+// SplitRules removes the rules from the groups (but the default ones)
+// and returns them, to be exported as aws_security_group_rule. Groups
+// referencing each other no longer depend on each other.
+func (sg *SecurityGroups) SplitRules() *StandaloneSecurityGroupRules {
+	res := StandaloneSecurityGroupRules{}
+	for _, v := range *sg {
+		if v.Default {
+			continue
+		}
+
+		res = append(res, v.rules...)
+		v.Ingresses = nil
+		v.Egresses = nil
+	}
+
+	return &res
+}
+
+func (rules *StandaloneSecurityGroupRules) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformList": makeTerraformList,
+	}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_security_group_rule" "{{ .ResourceName }}" {
+    type = "{{ .Type }}"
+    security_group_id = "{{ .SecurityGroupRef }}"
+    from_port = {{ .FromPort }}
+    to_port = {{ .ToPort }}
+    protocol = "{{ .Protocol }}"
+    {{- if .CIDRBlocks }}
+    cidr_blocks = [{{ .CIDRBlocks | makeTerraformList }}]
+    {{- end }}
+    {{- if .IPv6CIDRBlocks }}
+    ipv6_cidr_blocks = [{{ .IPv6CIDRBlocks | makeTerraformList }}]
+    {{- end }}
+    {{- if .PrefixListIDs }}
+    prefix_list_ids = [{{ .PrefixListIDs | makeTerraformList }}]
+    {{- end }}
+    {{- if .Self }}
+    self = true
+    {{- else if .SourceSecurityGroupRef }}
+    source_security_group_id = "{{ .SourceSecurityGroupRef }}"
+    {{- else if .SourceSecurityGroupID }}
+    source_security_group_id = "{{ .SourceSecurityGroupID }}"
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, rules)
+}
@@ -0,0 +1,154 @@
+package tfit
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func testSecurityGroups(t *testing.T, namer *Namer) *SecurityGroups {
+	t.Helper()
+	pair := func(account, group string) *ec2.UserIdGroupPair {
+		return &ec2.UserIdGroupPair{UserId: aws.String(account), GroupId: aws.String(group)}
+	}
+
+	src := []*ec2.SecurityGroup{
+		{
+			GroupId:   aws.String("sg-0"),
+			GroupName: aws.String("default"),
+			VpcId:     aws.String("vpc-1"),
+			IpPermissions: []*ec2.IpPermission{{
+				IpProtocol:       aws.String("-1"),
+				UserIdGroupPairs: []*ec2.UserIdGroupPair{pair("123456789012", "sg-0")},
+			}},
+		},
+		{
+			GroupId:     aws.String("sg-1"),
+			GroupName:   aws.String("web"),
+			Description: aws.String("Web servers"),
+			VpcId:       aws.String("vpc-1"),
+			IpPermissions: []*ec2.IpPermission{{
+				IpProtocol: aws.String("tcp"),
+				FromPort:   aws.Int64(443),
+				ToPort:     aws.Int64(443),
+				IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+				Ipv6Ranges: []*ec2.Ipv6Range{{CidrIpv6: aws.String("::/0")}},
+			}},
+			IpPermissionsEgress: []*ec2.IpPermission{{
+				IpProtocol: aws.String("-1"),
+				IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+			}},
+		},
+		{
+			GroupId:   aws.String("sg-2"),
+			GroupName: aws.String("db"),
+			VpcId:     aws.String("vpc-1"),
+			IpPermissions: []*ec2.IpPermission{{
+				IpProtocol:       aws.String("tcp"),
+				FromPort:         aws.Int64(5432),
+				ToPort:           aws.Int64(5432),
+				UserIdGroupPairs: []*ec2.UserIdGroupPair{pair("123456789012", "sg-1"), pair("210987654321", "sg-9"), pair("123456789012", "sg-2")},
+			}},
+		},
+	}
+
+	res := SecurityGroups{}
+	for _, v := range src {
+		sg := &SecurityGroup{}
+		sg.setSecurityGroup(v, aws.String("123456789012"))
+		sg.ResourceName = namer.Name(sg.nameInfo())
+		res = append(res, sg)
+	}
+	res.setStandaloneRules(namer)
+
+	return &res
+}
+
+func TestSecurityGroupsWriteHCL(t *testing.T) {
+	namer, err := NewNamer(NamingNameTag, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rs := mustRenderHCL(t, testSecurityGroups(t, namer))
+	if got, want := addresses(rs), []string{"aws_default_security_group.default", "aws_security_group.web", "aws_security_group.db"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	checkAttributes(t, rs[0], map[string]string{
+		"vpc_id":                  "vpc-1",
+		"name":                    "",
+		"ingress.security_groups": "[sg-0]",
+	})
+	checkAttributes(t, rs[1], map[string]string{
+		"name":                     "web",
+		"description":              "Web servers",
+		"ingress.cidr_blocks":      "[0.0.0.0/0]",
+		"ingress.ipv6_cidr_blocks": "[::/0]",
+		"egress.protocol":          "-1",
+	})
+	checkAttributes(t, rs[2], map[string]string{
+		"ingress.security_groups": "[sg-1 210987654321/sg-9 sg-2]",
+	})
+}
+
+func TestSecurityGroupsSplitRules(t *testing.T) {
+	namer, err := NewNamer(NamingNameTag, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sgs := testSecurityGroups(t, namer)
+	rules := sgs.SplitRules()
+
+	rs := mustRenderHCL(t, sgs)
+	if _, ok := rs[0].Attributes["ingress.security_groups"]; !ok {
+		t.Error("the rules of the default group were split")
+	}
+	for _, r := range rs[1:] {
+		for _, k := range []string{"ingress.protocol", "ingress", "egress.protocol", "egress"} {
+			if _, ok := r.Attributes[k]; ok {
+				t.Errorf("%s still has %s", r.Address(), k)
+			}
+		}
+	}
+
+	// References to the exported groups aren't literals, nor kept
+	// by ParseResources: the rendered rules are checked as text
+	got := []string{}
+	for _, r := range *rules {
+		source := aws.StringValue(r.SourceSecurityGroupID)
+		if r.Self {
+			source = "self"
+		}
+		if len(r.SourceSecurityGroupRef) > 0 {
+			source = r.SourceSecurityGroupRef
+		}
+		got = append(got, r.ResourceName+" "+r.SecurityGroupRef+" "+source)
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"db-ingress-tcp-5432-5432 ${aws_security_group.db.id} ${aws_security_group.web.id}",
+		"db-ingress-tcp-5432-5432-2 ${aws_security_group.db.id} 210987654321/sg-9",
+		"db-ingress-tcp-5432-5432-3 ${aws_security_group.db.id} self",
+		"web-egress-all-0-0 ${aws_security_group.web.id} ",
+		"web-ingress-tcp-443-443 ${aws_security_group.web.id} ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	mustRenderHCL(t, rules)
+
+	ids := make(map[string]bool)
+	for _, r := range *rules {
+		ids[r.id()] = true
+	}
+	if len(ids) != len(*rules) {
+		t.Errorf("got %d ids for %d rules", len(ids), len(*rules))
+	}
+}
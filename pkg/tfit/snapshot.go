@@ -0,0 +1,271 @@
+package tfit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// SnapshotVersion is the version of the snapshot format
+const SnapshotVersion = 1
+
+// Snapshot is a normalized inventory of the exported
+// resources at a point in time
+type Snapshot struct {
+	Version   int       `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+	Region    string    `json:"region,omitempty"`
+	Resources Resources `json:"resources"`
+}
+
+// TakeSnapshot exports every resource (or the resources of the given types)
+func (c *AWSClient) TakeSnapshot(types ...string) (*Snapshot, error) {
+	return c.TakeSnapshotWithContext(aws.BackgroundContext(), types...)
+}
+
+func (c *AWSClient) TakeSnapshotWithContext(ctx aws.Context, types ...string) (*Snapshot, error) {
+	rs, err := c.GetResourcesWithContext(ctx, types...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(rs, func(i, j int) bool {
+		if rs[i].Type != rs[j].Type {
+			return rs[i].Type < rs[j].Type
+		}
+		return rs[i].ID < rs[j].ID
+	})
+
+	return &Snapshot{
+		Version:   SnapshotVersion,
+		Timestamp: time.Now().UTC(),
+		Region:    c.region,
+		Resources: rs,
+	}, nil
+}
+
+// ReadSnapshot reads a snapshot written by Snapshot.WriteJSON
+func ReadSnapshot(path string) (*Snapshot, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading snapshot %s: %s", path, err)
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(src, &s); err != nil {
+		return nil, fmt.Errorf("Error parsing snapshot %s: %s", path, err)
+	}
+
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("Unsupported snapshot version in %s: %d", path, s.Version)
+	}
+
+	return &s, nil
+}
+
+func (s *Snapshot) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// AttributeChange is an attribute which changed between two snapshots.
+// For lists (e.g. ingress rules) Added & Removed are the elements which
+// appeared and disappeared.
+type AttributeChange struct {
+	Attribute string        `json:"attribute"`
+	Old       interface{}   `json:"old,omitempty"`
+	New       interface{}   `json:"new,omitempty"`
+	Added     []interface{} `json:"added,omitempty"`
+	Removed   []interface{} `json:"removed,omitempty"`
+}
+
+// ResourceChange is a resource existing in both snapshots
+// whose attributes changed
+type ResourceChange struct {
+	Type       string             `json:"type"`
+	ID         string             `json:"id"`
+	Address    string             `json:"address"`
+	Attributes []*AttributeChange `json:"attributes"`
+}
+
+// SnapshotDiff is what changed between two snapshots
+type SnapshotDiff struct {
+	From    time.Time         `json:"from"`
+	To      time.Time         `json:"to"`
+	Added   Resources         `json:"added"`
+	Removed Resources         `json:"removed"`
+	Changed []*ResourceChange `json:"changed"`
+}
+
+// snapshotKey identifies a resource across snapshots
+func snapshotKey(r *Resource) string {
+	if len(r.ID) > 0 {
+		return r.Type + "|" + r.ID
+	}
+
+	return r.Address()
+}
+
+// DiffSnapshots compares two snapshots. Resources are matched by type &
+// AWS id, so renaming a resource (e.g. its Name tag) is an attribute change.
+func DiffSnapshots(old, new *Snapshot) *SnapshotDiff {
+	d := &SnapshotDiff{
+		From:    old.Timestamp,
+		To:      new.Timestamp,
+		Added:   Resources{},
+		Removed: Resources{},
+		Changed: []*ResourceChange{},
+	}
+
+	before := make(map[string]*Resource, len(old.Resources))
+	for _, r := range old.Resources {
+		before[snapshotKey(r)] = r
+	}
+
+	after := make(map[string]bool, len(new.Resources))
+	for _, r := range new.Resources {
+		key := snapshotKey(r)
+		after[key] = true
+
+		prev, ok := before[key]
+		if !ok {
+			d.Added = append(d.Added, r)
+			continue
+		}
+
+		if changes := diffAttributes(prev.Attributes, r.Attributes); len(changes) > 0 {
+			d.Changed = append(d.Changed, &ResourceChange{
+				Type:       r.Type,
+				ID:         r.ID,
+				Address:    r.Address(),
+				Attributes: changes,
+			})
+		}
+	}
+
+	for _, r := range old.Resources {
+		if !after[snapshotKey(r)] {
+			d.Removed = append(d.Removed, r)
+		}
+	}
+
+	return d
+}
+
+func diffAttributes(old, new map[string]interface{}) []*AttributeChange {
+	keys := make(map[string]bool)
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var res []*AttributeChange
+	for _, k := range sorted {
+		ov, inOld := old[k]
+		nv, inNew := new[k]
+		if inOld && inNew && canonicalValue(ov) == canonicalValue(nv) {
+			continue
+		}
+
+		change := &AttributeChange{Attribute: k, Old: ov, New: nv}
+		ol, oldIsList := ov.([]interface{})
+		nl, newIsList := nv.([]interface{})
+		if (oldIsList || !inOld) && (newIsList || !inNew) {
+			change.Added = listDifference(nl, ol)
+			change.Removed = listDifference(ol, nl)
+			change.Old, change.New = nil, nil
+		}
+		res = append(res, change)
+	}
+
+	return res
+}
+
+// listDifference returns the elements of a missing from b
+func listDifference(a, b []interface{}) []interface{} {
+	count := make(map[string]int)
+	for _, v := range b {
+		count[canonicalValue(v)]++
+	}
+
+	var res []interface{}
+	for _, v := range a {
+		k := canonicalValue(v)
+		if count[k] > 0 {
+			count[k]--
+			continue
+		}
+		res = append(res, v)
+	}
+
+	return res
+}
+
+// HasChanges tells whether anything changed between the snapshots
+func (d *SnapshotDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// WriteText writes the changes for humans
+func (d *SnapshotDiff) WriteText(w io.Writer) error {
+	lines := []string{fmt.Sprintf("Changes from %s to %s", d.From.Format(time.RFC3339), d.To.Format(time.RFC3339))}
+	if !d.HasChanges() {
+		lines = append(lines, "No changes")
+	}
+
+	for _, r := range d.Added {
+		lines = append(lines, fmt.Sprintf("+ %s (%s)", r.Address(), r.ID))
+	}
+	for _, r := range d.Removed {
+		lines = append(lines, fmt.Sprintf("- %s (%s)", r.Address(), r.ID))
+	}
+	for _, r := range d.Changed {
+		lines = append(lines, fmt.Sprintf("~ %s (%s)", r.Address, r.ID))
+		for _, a := range r.Attributes {
+			if a.Added != nil || a.Removed != nil {
+				for _, v := range a.Added {
+					lines = append(lines, fmt.Sprintf("    %s: + %s", a.Attribute, jsonValue(v)))
+				}
+				for _, v := range a.Removed {
+					lines = append(lines, fmt.Sprintf("    %s: - %s", a.Attribute, jsonValue(v)))
+				}
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("    %s: %s => %s", a.Attribute, driftValue(a.Old), driftValue(a.New)))
+		}
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func jsonValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+// WriteJSON writes the changes as JSON
+func (d *SnapshotDiff) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
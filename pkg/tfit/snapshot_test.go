@@ -0,0 +1,109 @@
+package tfit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	sg := func(id string, attrs map[string]interface{}) *Resource {
+		return &Resource{Type: "aws_security_group", Name: id, ID: id, Attributes: attrs}
+	}
+	rule := func(port string) map[string]interface{} {
+		return map[string]interface{}{"from_port": port, "to_port": port}
+	}
+
+	cases := []struct {
+		name    string
+		old     Resources
+		new     Resources
+		added   []string
+		removed []string
+		changed []*ResourceChange
+	}{
+		{
+			name:    "same",
+			old:     Resources{sg("sg-1", map[string]interface{}{"name": "web"})},
+			new:     Resources{sg("sg-1", map[string]interface{}{"name": "web"})},
+			added:   []string{},
+			removed: []string{},
+			changed: []*ResourceChange{},
+		},
+		{
+			name:    "added and removed",
+			old:     Resources{sg("sg-1", nil)},
+			new:     Resources{sg("sg-2", nil)},
+			added:   []string{"aws_security_group.sg-2"},
+			removed: []string{"aws_security_group.sg-1"},
+			changed: []*ResourceChange{},
+		},
+		{
+			name:    "renamed resource is matched by id",
+			old:     Resources{{Type: "aws_instance", Name: "web", ID: "i-1", Attributes: map[string]interface{}{"tags.Name": "web"}}},
+			new:     Resources{{Type: "aws_instance", Name: "api", ID: "i-1", Attributes: map[string]interface{}{"tags.Name": "api"}}},
+			added:   []string{},
+			removed: []string{},
+			changed: []*ResourceChange{{Type: "aws_instance", ID: "i-1", Address: "aws_instance.api", Attributes: []*AttributeChange{
+				{Attribute: "tags.Name", Old: "web", New: "api"},
+			}}},
+		},
+		{
+			name:    "scalar attributes",
+			old:     Resources{sg("sg-1", map[string]interface{}{"description": "old", "name": "web"})},
+			new:     Resources{sg("sg-1", map[string]interface{}{"name": "web", "vpc_id": "vpc-1"})},
+			added:   []string{},
+			removed: []string{},
+			changed: []*ResourceChange{{Type: "aws_security_group", ID: "sg-1", Address: "aws_security_group.sg-1", Attributes: []*AttributeChange{
+				{Attribute: "description", Old: "old"},
+				{Attribute: "vpc_id", New: "vpc-1"},
+			}}},
+		},
+		{
+			name:    "list elements",
+			old:     Resources{sg("sg-1", map[string]interface{}{"ingress": []interface{}{rule("22"), rule("443")}})},
+			new:     Resources{sg("sg-1", map[string]interface{}{"ingress": []interface{}{rule("443"), rule("80")}})},
+			added:   []string{},
+			removed: []string{},
+			changed: []*ResourceChange{{Type: "aws_security_group", ID: "sg-1", Address: "aws_security_group.sg-1", Attributes: []*AttributeChange{
+				{Attribute: "ingress", Added: []interface{}{rule("80")}, Removed: []interface{}{rule("22")}},
+			}}},
+		},
+		{
+			name:    "new list",
+			old:     Resources{sg("sg-1", map[string]interface{}{})},
+			new:     Resources{sg("sg-1", map[string]interface{}{"egress": []interface{}{rule("0")}})},
+			added:   []string{},
+			removed: []string{},
+			changed: []*ResourceChange{{Type: "aws_security_group", ID: "sg-1", Address: "aws_security_group.sg-1", Attributes: []*AttributeChange{
+				{Attribute: "egress", Added: []interface{}{rule("0")}},
+			}}},
+		},
+		{
+			name:    "resources without id are matched by address",
+			old:     Resources{{Type: "aws_route53_record", Name: "www", Attributes: map[string]interface{}{"ttl": "300"}}},
+			new:     Resources{{Type: "aws_route53_record", Name: "www", Attributes: map[string]interface{}{"ttl": "300"}}},
+			added:   []string{},
+			removed: []string{},
+			changed: []*ResourceChange{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := DiffSnapshots(&Snapshot{Resources: c.old}, &Snapshot{Resources: c.new})
+
+			if got := addresses(d.Added); !reflect.DeepEqual(got, c.added) {
+				t.Errorf("added: got %v, want %v", got, c.added)
+			}
+			if got := addresses(d.Removed); !reflect.DeepEqual(got, c.removed) {
+				t.Errorf("removed: got %v, want %v", got, c.removed)
+			}
+			if !reflect.DeepEqual(d.Changed, c.changed) {
+				t.Errorf("changed: got %s, want %s", jsonValue(d.Changed), jsonValue(c.changed))
+			}
+			if want := len(c.added)+len(c.removed)+len(c.changed) > 0; d.HasChanges() != want {
+				t.Errorf("HasChanges: got %v, want %v", d.HasChanges(), want)
+			}
+		})
+	}
+}
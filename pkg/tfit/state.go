@@ -0,0 +1,186 @@
+package tfit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Resource types managing an AWS resource tfit exports as another type
+var stateTypeAliases = map[string]string{
+	"aws_default_vpc":            "aws_vpc",
+	"aws_default_subnet":         "aws_subnet",
+	"aws_default_route_table":    "aws_route_table",
+	"aws_default_security_group": "aws_security_group",
+	"aws_default_network_acl":    "aws_network_acl",
+
+	"aws_vpc_peering_connection_accepter": "aws_vpc_peering_connection",
+}
+
+func stateType(resourceType string) string {
+	if alias, ok := stateTypeAliases[resourceType]; ok {
+		return alias
+	}
+
+	return resourceType
+}
+
+// State is the set of AWS resources managed by Terraform state files
+type State struct {
+	ids map[string]map[string]bool // type -> id
+
+	// The ids of the resources of the root module, by address
+	addresses map[string]string
+}
+
+// Terraform < 0.12
+type stateV3 struct {
+	Modules []struct {
+		Path      []string `json:"path"`
+		Resources map[string]struct {
+			Type    string `json:"type"`
+			Primary struct {
+				ID string `json:"id"`
+			} `json:"primary"`
+		} `json:"resources"`
+	} `json:"modules"`
+}
+
+// Terraform >= 0.12
+type stateV4 struct {
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey       interface{}            `json:"index_key"`
+			Attributes     map[string]interface{} `json:"attributes"`
+			AttributesFlat map[string]string      `json:"attributes_flat"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+func NewState() *State {
+	return &State{
+		ids:       make(map[string]map[string]bool),
+		addresses: make(map[string]string),
+	}
+}
+
+// ReadStateFiles reads Terraform state files (format version 3 or 4)
+func ReadStateFiles(paths ...string) (*State, error) {
+	s := NewState()
+	for _, p := range paths {
+		src, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading %s: %s", p, err)
+		}
+
+		if err := s.Parse(src); err != nil {
+			return nil, fmt.Errorf("Error parsing %s: %s", p, err)
+		}
+	}
+
+	return s, nil
+}
+
+// Parse adds the resources of a state file to the State
+func (s *State) Parse(src []byte) error {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(src, &header); err != nil {
+		return err
+	}
+
+	switch header.Version {
+	case 1, 2, 3:
+		var st stateV3
+		if err := json.Unmarshal(src, &st); err != nil {
+			return err
+		}
+
+		for _, m := range st.Modules {
+			for key, r := range m.Resources {
+				if strings.HasPrefix(key, "data.") {
+					continue
+				}
+				s.add(r.Type, r.Primary.ID)
+
+				// The resources with a count have an index suffix
+				if len(m.Path) <= 1 && strings.Count(key, ".") == 1 {
+					s.addresses[key] = r.Primary.ID
+				}
+			}
+		}
+	case 4:
+		var st stateV4
+		if err := json.Unmarshal(src, &st); err != nil {
+			return err
+		}
+
+		for _, r := range st.Resources {
+			if r.Mode == "data" {
+				continue
+			}
+
+			for _, i := range r.Instances {
+				id, ok := i.Attributes["id"].(string)
+				if !ok {
+					id = i.AttributesFlat["id"]
+				}
+				s.add(r.Type, id)
+
+				if len(r.Module) == 0 && len(r.Instances) == 1 && i.IndexKey == nil && len(id) > 0 {
+					s.addresses[r.Type+"."+r.Name] = id
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("Unsupported state version: %d", header.Version)
+	}
+
+	return nil
+}
+
+func (s *State) add(resourceType, id string) {
+	resourceType = stateType(resourceType)
+	if len(id) == 0 {
+		return
+	}
+
+	if s.ids[resourceType] == nil {
+		s.ids[resourceType] = make(map[string]bool)
+	}
+	s.ids[resourceType][strings.ToLower(id)] = true
+}
+
+// Manages tells whether the state has the resource
+func (s *State) Manages(r *Resource) bool {
+	return s.ids[stateType(r.Type)][strings.ToLower(r.ID)]
+}
+
+// ResolveIDs sets the AWS id of the configured resources the state
+// has. Only the resources of the root module without count or for_each
+// can be resolved, by their address.
+func (s *State) ResolveIDs(rs Resources) {
+	for _, r := range rs {
+		if id, ok := s.addresses[r.Address()]; ok {
+			r.ID = id
+		}
+	}
+}
+
+// Unmanaged returns the resources which are not in the state
+func (s *State) Unmanaged(rs Resources) Resources {
+	res := Resources{}
+	for _, r := range rs {
+		if !s.Manages(r) {
+			res = append(res, r)
+		}
+	}
+
+	return res
+}
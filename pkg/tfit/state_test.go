@@ -0,0 +1,181 @@
+package tfit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const stateV3Example = `{
+  "version": 3,
+  "modules": [{
+    "resources": {
+      "aws_vpc.main": {"type": "aws_vpc", "primary": {"id": "vpc-1"}},
+      "aws_default_security_group.default": {"type": "aws_default_security_group", "primary": {"id": "sg-1"}},
+      "data.aws_vpc.other": {"type": "aws_vpc", "primary": {"id": "vpc-2"}}
+    }
+  }]
+}`
+
+const stateV4Example = `{
+  "version": 4,
+  "resources": [
+    {"mode": "managed", "type": "aws_s3_bucket", "instances": [
+      {"attributes": {"id": "Logs"}},
+      {"attributes_flat": {"id": "backups"}}
+    ]},
+    {"mode": "managed", "type": "aws_vpc_peering_connection_accepter", "instances": [
+      {"attributes": {"id": "pcx-1"}}
+    ]},
+    {"mode": "data", "type": "aws_s3_bucket", "instances": [
+      {"attributes": {"id": "assets"}}
+    ]}
+  ]
+}`
+
+func TestStateParse(t *testing.T) {
+	cases := []struct {
+		name      string
+		src       string
+		managed   []Resource
+		unmanaged []Resource
+		err       string
+	}{
+		{
+			name: "v3",
+			src:  stateV3Example,
+			managed: []Resource{
+				{Type: "aws_vpc", ID: "vpc-1"},
+				{Type: "aws_security_group", ID: "sg-1"},
+				{Type: "aws_default_security_group", ID: "sg-1"},
+			},
+			unmanaged: []Resource{
+				{Type: "aws_vpc", ID: "vpc-2"},
+				{Type: "aws_security_group", ID: "vpc-1"},
+			},
+		},
+		{
+			name: "v4",
+			src:  stateV4Example,
+			managed: []Resource{
+				{Type: "aws_s3_bucket", ID: "logs"},
+				{Type: "aws_s3_bucket", ID: "backups"},
+				{Type: "aws_vpc_peering_connection", ID: "pcx-1"},
+			},
+			unmanaged: []Resource{
+				{Type: "aws_s3_bucket", ID: "assets"},
+				{Type: "aws_s3_bucket", ID: ""},
+			},
+		},
+		{
+			name: "unsupported version",
+			src:  `{"version": 5}`,
+			err:  "Unsupported state version: 5",
+		},
+		{
+			name: "invalid JSON",
+			src:  `{"version": `,
+			err:  "unexpected end of JSON input",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := NewState()
+			err := s.Parse([]byte(c.src))
+			if len(c.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Errorf("got error %v, want %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, r := range c.managed {
+				if !s.Manages(&r) {
+					t.Errorf("%s %s should be managed", r.Type, r.ID)
+				}
+			}
+			for _, r := range c.unmanaged {
+				if s.Manages(&r) {
+					t.Errorf("%s %s should not be managed", r.Type, r.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestStateUnmanaged(t *testing.T) {
+	s := NewState()
+	for _, src := range []string{stateV3Example, stateV4Example} {
+		if err := s.Parse([]byte(src)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	live := Resources{
+		{Type: "aws_vpc", Name: "main", ID: "vpc-1"},
+		{Type: "aws_vpc", Name: "other", ID: "vpc-2"},
+		{Type: "aws_s3_bucket", Name: "logs", ID: "logs"},
+	}
+
+	unmanaged := s.Unmanaged(live)
+	if len(unmanaged) != 1 || unmanaged[0].Address() != "aws_vpc.other" {
+		t.Errorf("got %v, want aws_vpc.other", addresses(unmanaged))
+	}
+}
+
+func TestStateResolveIDs(t *testing.T) {
+	s := NewState()
+	for _, src := range []string{
+		`{"version": 3, "modules": [
+  {"path": ["root"], "resources": {
+    "aws_vpc.main": {"type": "aws_vpc", "primary": {"id": "vpc-1"}},
+    "aws_subnet.private.0": {"type": "aws_subnet", "primary": {"id": "subnet-1"}},
+    "aws_subnet.private.1": {"type": "aws_subnet", "primary": {"id": "subnet-2"}}
+  }},
+  {"path": ["root", "net"], "resources": {
+    "aws_vpc.other": {"type": "aws_vpc", "primary": {"id": "vpc-3"}}
+  }}
+]}`,
+		`{"version": 4, "resources": [
+  {"mode": "managed", "type": "aws_s3_bucket", "name": "logs", "instances": [{"attributes": {"id": "logs-123"}}]},
+  {"mode": "managed", "type": "aws_s3_bucket", "name": "assets", "instances": [
+    {"index_key": "a", "attributes": {"id": "assets-a"}}
+  ]},
+  {"module": "module.app", "mode": "managed", "type": "aws_iam_role", "name": "app", "instances": [{"attributes": {"id": "app"}}]}
+]}`,
+	} {
+		if err := s.Parse([]byte(src)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rs := Resources{
+		{Type: "aws_vpc", Name: "main"},
+		{Type: "aws_vpc", Name: "other"},
+		{Type: "aws_subnet", Name: "private"},
+		{Type: "aws_s3_bucket", Name: "logs"},
+		{Type: "aws_s3_bucket", Name: "assets"},
+		{Type: "aws_iam_role", Name: "app"},
+	}
+	s.ResolveIDs(rs)
+
+	got := []string{}
+	for _, r := range rs {
+		got = append(got, r.Address()+"="+r.ID)
+	}
+	want := []string{
+		"aws_vpc.main=vpc-1",
+		"aws_vpc.other=",
+		"aws_subnet.private=",
+		"aws_s3_bucket.logs=logs-123",
+		"aws_s3_bucket.assets=",
+		"aws_iam_role.app=",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
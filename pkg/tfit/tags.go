@@ -0,0 +1,108 @@
+package tfit
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// TaggedResourceTypes are the exported resource types which have tags
+var TaggedResourceTypes = []string{
+	"aws_vpc",
+	"aws_vpc_dhcp_options",
+	"aws_subnet",
+	"aws_route_table",
+	"aws_network_acl",
+	"aws_internet_gateway",
+	"aws_nat_gateway",
+	"aws_eip",
+	"aws_vpc_peering_connection",
+	"aws_ec2_transit_gateway",
+	"aws_ec2_transit_gateway_vpc_attachment",
+	"aws_ec2_transit_gateway_route_table",
+	"aws_vpn_gateway",
+	"aws_customer_gateway",
+	"aws_vpn_connection",
+	"aws_security_group",
+	"aws_instance",
+	"aws_ebs_volume",
+	"aws_elb",
+	"aws_launch_template",
+	"aws_autoscaling_group",
+	"aws_iam_user",
+	"aws_route53_zone",
+}
+
+// tagger is implemented by the collections whose resources have tags
+type tagger interface {
+	taggedResources() []NameInfo
+	addMissingTags(tags map[string]string)
+}
+
+// AddMissingTags adds the tags a resource of the collection doesn't
+// have yet, before rendering it. Collections without tags are left as is.
+func AddMissingTags(col Collection, tags map[string]string) {
+	if v, ok := col.(tagger); ok && len(tags) > 0 {
+		v.addMissingTags(tags)
+	}
+}
+
+func sortedTagKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (t *Tags) addMissing(tags map[string]string) {
+	if *t == nil {
+		*t = make(Tags)
+	}
+
+	for _, k := range sortedTagKeys(tags) {
+		if _, ok := (*t)[k]; !ok {
+			(*t)[k] = aws.String(tags[k])
+		}
+	}
+}
+
+func addMissingPtrTags(src map[*string]*string, tags map[string]string) map[*string]*string {
+	if src == nil {
+		src = make(map[*string]*string)
+	}
+
+	has := ptrTagValues(src)
+	for _, k := range sortedTagKeys(tags) {
+		if _, ok := has[k]; !ok {
+			src[aws.String(k)] = aws.String(tags[k])
+		}
+	}
+
+	return src
+}
+
+func addMissingResourceTags(src []*ResourceTag, tags map[string]string) []*ResourceTag {
+	has := resourceTagValues(src)
+	for _, k := range sortedTagKeys(tags) {
+		if _, ok := has[k]; !ok {
+			src = append(src, &ResourceTag{Key: aws.String(k), Value: aws.String(tags[k])})
+		}
+	}
+
+	return src
+}
+
+// addMissingTagDescriptions propagates the added tags to the instances
+func addMissingTagDescriptions(src []*TagDescription, tags map[string]string) []*TagDescription {
+	has := tagDescriptionValues(src)
+	for _, k := range sortedTagKeys(tags) {
+		if _, ok := has[k]; !ok {
+			src = append(src, &TagDescription{Key: aws.String(k), Value: aws.String(tags[k]), PropagateAtLaunch: aws.Bool(true)})
+		}
+	}
+
+	return src
+}
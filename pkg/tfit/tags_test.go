@@ -0,0 +1,72 @@
+package tfit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestAuditTags(t *testing.T) {
+	namer, err := NewNamer(NamingID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resources := []NameInfo{
+		{Type: "aws_vpc", ID: "vpc-1", Tags: map[string]string{"env": "prod", "owner": "ops"}},
+		{Type: "aws_vpc", ID: "vpc-2", Tags: map[string]string{"env": "prod", "owner": " "}},
+		{Type: "aws_instance", ID: "i-1"},
+	}
+
+	got := make(map[string]string)
+	for _, f := range auditTags(namer, resources, []string{"env", "owner"}) {
+		if f.Rule != RuleTagsMissing || f.Severity != SeverityLow {
+			t.Errorf("%s: got %s %s", f.Address, f.Rule, f.Severity)
+		}
+		got[f.Address] = f.Message
+	}
+
+	want := map[string]string{
+		"aws_vpc.vpc-2":    "missing tags: owner",
+		"aws_instance.i-1": "missing tags: env, owner",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestAddMissingTags(t *testing.T) {
+	defaults := map[string]string{"env": "prod", "owner": "ops"}
+
+	vpcs := &VPCs{
+		{VPCId: aws.String("vpc-1")},
+		{VPCId: aws.String("vpc-2"), Tags: &Tags{"env": aws.String("dev")}},
+	}
+	AddMissingTags(vpcs, defaults)
+
+	for i, want := range []map[string]string{
+		{"env": "prod", "owner": "ops"},
+		{"env": "dev", "owner": "ops"},
+	} {
+		if got := (*vpcs)[i].Tags.values(); !reflect.DeepEqual(got, want) {
+			t.Errorf("vpc %d: got %v, want %v", i, got, want)
+		}
+	}
+
+	instances := &Instances{{InstanceID: aws.String("i-1"), Tags: map[*string]*string{aws.String("owner"): aws.String("dev")}}}
+	AddMissingTags(instances, defaults)
+	if got, want := ptrTagValues((*instances)[0].Tags), map[string]string{"env": "prod", "owner": "dev"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("instance: got %v, want %v", got, want)
+	}
+
+	rtags := addMissingResourceTags([]*ResourceTag{{Key: aws.String("env"), Value: aws.String("dev")}}, defaults)
+	if got, want := resourceTagValues(rtags), map[string]string{"env": "dev", "owner": "ops"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("resource tags: got %v, want %v", got, want)
+	}
+
+	descriptions := addMissingTagDescriptions(nil, defaults)
+	if len(descriptions) != 2 || aws.StringValue(descriptions[0].Key) != "env" || !aws.BoolValue(descriptions[0].PropagateAtLaunch) {
+		t.Errorf("tag descriptions: got %v", descriptions)
+	}
+}
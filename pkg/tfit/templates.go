@@ -2,8 +2,13 @@ package tfit
 
 const EC2_ROUTE_TABLE = `{{ if . }}
   {{- range .}}
-resource "aws_route_table" "{{ .Id }}" {
+  {{- if .Default }}
+resource "aws_default_route_table" "{{ .ResourceName }}" {
+  default_route_table_id = "{{ .Id }}"
+  {{- else }}
+resource "aws_route_table" "{{ .ResourceName }}" {
   vpc_id = "{{ .VpcId }}"
+  {{- end }}
 
   {{- if .Tags }}
   tags {
@@ -54,3 +59,65 @@ resource "aws_route_table" "{{ .Id }}" {
 }
   {{- end }}
 {{ end }}`
+
+const EC2_ROUTE_TABLE_ASSOCIATION = `{{ if . }}
+  {{- range .}}
+resource "aws_route_table_association" "{{ .ResourceName }}" {
+  subnet_id = "{{ .SubnetID }}"
+  route_table_id = "{{ .RouteTableID }}"
+}
+  {{- end }}
+{{ end }}`
+
+const EC2_MAIN_ROUTE_TABLE_ASSOCIATION = `{{ if . }}
+  {{- range .}}
+resource "aws_main_route_table_association" "{{ .ResourceName }}" {
+  vpc_id = "{{ .VPCID }}"
+  route_table_id = "{{ .RouteTableID }}"
+}
+  {{- end }}
+{{ end }}`
+
+const EC2_ROUTE = `{{ if . }}
+  {{- range .}}
+resource "aws_route" "{{ .ResourceName }}" {
+  route_table_id = "{{ .RouteTableID }}"
+  {{- if .CIDRBlock }}
+  destination_cidr_block = "{{ .CIDRBlock }}"
+  {{- end }}
+  {{- if .IPv6CIDRBlock }}
+  destination_ipv6_cidr_block = "{{ .IPv6CIDRBlock }}"
+  {{- end }}
+  {{- if .VpcPeeringConnectionId }}
+  vpc_peering_connection_id = "{{ .VpcPeeringConnectionId }}"
+  {{- end }}
+  {{- if .TransitGatewayId }}
+  transit_gateway_id = "{{ .TransitGatewayId }}"
+  {{- end }}
+  {{- if .NetworkInterfaceId }}
+  network_interface_id = "{{ .NetworkInterfaceId }}"
+  {{- end }}
+  {{- if .NatGatewayId }}
+  nat_gateway_id = "{{ .NatGatewayId }}"
+  {{- end }}
+  {{- if .InstanceId }}
+  instance_id = "{{ .InstanceId }}"
+  {{- end }}
+  {{- if .GatewayId }}
+  gateway_id = "{{ .GatewayId }}"
+  {{- end }}
+  {{- if .EgressOnlyInternetGatewayId }}
+  egress_only_gateway_id = "{{ .EgressOnlyInternetGatewayId }}"
+  {{- end }}
+}
+  {{- end }}
+{{ end }}`
+
+const EC2_VPN_GATEWAY_ROUTE_PROPAGATION = `{{ if . }}
+  {{- range .}}
+resource "aws_vpn_gateway_route_propagation" "{{ .ResourceName }}" {
+  vpn_gateway_id = "{{ .VPNGatewayID }}"
+  route_table_id = "{{ .RouteTableID }}"
+}
+  {{- end }}
+{{ end }}`
@@ -0,0 +1,579 @@
+package tfit
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func stateFilter(states ...string) []*ec2.Filter {
+	return []*ec2.Filter{{Name: aws.String("state"), Values: aws.StringSlice(states)}}
+}
+
+//**************** Transit Gateway ****************
+
+type TransitGateway struct {
+	TransitGatewayID             *string
+	Description                  *string
+	AmazonSideASN                *int64
+	AutoAcceptSharedAttachments  *string
+	DefaultRouteTableAssociation *string
+	DefaultRouteTablePropagation *string
+	DNSSupport                   *string
+	VPNECMPSupport               *string
+	Tags                         *Tags
+
+	ResourceName string
+}
+
+type TransitGateways []*TransitGateway
+
+func (tgw *TransitGateway) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_ec2_transit_gateway",
+		ID:   aws.StringValue(tgw.TransitGatewayID),
+		Tags: tgw.Tags.values(),
+	}
+}
+
+func (tgws *TransitGateways) addToGraph(g *Graph) {
+	for _, v := range *tgws {
+		g.addNode(v.nameInfo())
+	}
+}
+
+func (tgws *TransitGateways) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *tgws {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (tgws *TransitGateways) addMissingTags(tags map[string]string) {
+	for _, v := range *tgws {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (c *AWSClient) GetTransitGateways(AccountId *string) (*TransitGateways, error) {
+	return c.GetTransitGatewaysWithContext(aws.BackgroundContext(), AccountId)
+}
+
+// GetTransitGatewaysWithContext returns the transit gateways of the
+// account, not the ones other accounts share with it
+func (c *AWSClient) GetTransitGatewaysWithContext(ctx aws.Context, AccountId *string) (*TransitGateways, error) {
+	res := TransitGateways{}
+	opt := &ec2.DescribeTransitGatewaysInput{
+		Filters: stateFilter(ec2.TransitGatewayStatePending, ec2.TransitGatewayStateAvailable, ec2.TransitGatewayStateModifying),
+	}
+	for {
+		out, err := c.ec2conn.DescribeTransitGatewaysWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing transit gateways: %s", err)
+		}
+
+		for _, v := range out.TransitGateways {
+			if aws.StringValue(v.OwnerId) != aws.StringValue(AccountId) {
+				continue
+			}
+
+			tgw := &TransitGateway{
+				TransitGatewayID: v.TransitGatewayId,
+				Tags:             &Tags{},
+			}
+			if len(aws.StringValue(v.Description)) > 0 {
+				tgw.Description = v.Description
+			}
+			if o := v.Options; o != nil {
+				tgw.AmazonSideASN = o.AmazonSideAsn
+				tgw.AutoAcceptSharedAttachments = o.AutoAcceptSharedAttachments
+				tgw.DefaultRouteTableAssociation = o.DefaultRouteTableAssociation
+				tgw.DefaultRouteTablePropagation = o.DefaultRouteTablePropagation
+				tgw.DNSSupport = o.DnsSupport
+				tgw.VPNECMPSupport = o.VpnEcmpSupport
+			}
+			tgw.Tags.setTags(v.Tags)
+
+			tgw.ResourceName = c.namer.Name(tgw.nameInfo())
+			res = append(res, tgw)
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+func (tgws *TransitGateways) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_ec2_transit_gateway" "{{ .ResourceName }}" {
+    {{- if .Description }}
+    description = "{{ .Description }}"
+    {{- end }}
+    {{- if .AmazonSideASN }}
+    amazon_side_asn = {{ .AmazonSideASN }}
+    {{- end }}
+    {{- if .AutoAcceptSharedAttachments }}
+    auto_accept_shared_attachments = "{{ .AutoAcceptSharedAttachments }}"
+    {{- end }}
+    {{- if .DefaultRouteTableAssociation }}
+    default_route_table_association = "{{ .DefaultRouteTableAssociation }}"
+    {{- end }}
+    {{- if .DefaultRouteTablePropagation }}
+    default_route_table_propagation = "{{ .DefaultRouteTablePropagation }}"
+    {{- end }}
+    {{- if .DNSSupport }}
+    dns_support = "{{ .DNSSupport }}"
+    {{- end }}
+    {{- if .VPNECMPSupport }}
+    vpn_ecmp_support = "{{ .VPNECMPSupport }}"
+    {{- end }}
+    {{- if .Tags }}
+
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, tgws)
+}
+
+//**************** Transit Gateway VPC Attachment ****************
+
+type TransitGatewayVPCAttachment struct {
+	TransitGatewayAttachmentID *string
+	TransitGatewayID           *string
+	VPCID                      *string
+	SubnetIDs                  []*string
+	DNSSupport                 *string
+	IPv6Support                *string
+	Tags                       *Tags
+
+	ResourceName string
+}
+
+type TransitGatewayVPCAttachments []*TransitGatewayVPCAttachment
+
+func (a *TransitGatewayVPCAttachment) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_ec2_transit_gateway_vpc_attachment",
+		ID:   aws.StringValue(a.TransitGatewayAttachmentID),
+		Tags: a.Tags.values(),
+	}
+}
+
+func (a *TransitGatewayVPCAttachments) addToGraph(g *Graph) {
+	for _, v := range *a {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "transit_gateway", "aws_ec2_transit_gateway", aws.StringValue(v.TransitGatewayID))
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCID))
+		g.addEdges(n, "subnet", "aws_subnet", v.SubnetIDs)
+	}
+}
+
+func (a *TransitGatewayVPCAttachments) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *a {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (a *TransitGatewayVPCAttachments) addMissingTags(tags map[string]string) {
+	for _, v := range *a {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (c *AWSClient) GetTransitGatewayVPCAttachments(AccountId *string) (*TransitGatewayVPCAttachments, error) {
+	return c.GetTransitGatewayVPCAttachmentsWithContext(aws.BackgroundContext(), AccountId)
+}
+
+// GetTransitGatewayVPCAttachmentsWithContext returns the attachments of the
+// VPCs of the account, which is the one creating them
+func (c *AWSClient) GetTransitGatewayVPCAttachmentsWithContext(ctx aws.Context, AccountId *string) (*TransitGatewayVPCAttachments, error) {
+	res := TransitGatewayVPCAttachments{}
+	opt := &ec2.DescribeTransitGatewayVpcAttachmentsInput{
+		Filters: stateFilter(ec2.TransitGatewayAttachmentStatePendingAcceptance, ec2.TransitGatewayAttachmentStatePending,
+			ec2.TransitGatewayAttachmentStateAvailable, ec2.TransitGatewayAttachmentStateModifying),
+	}
+	for {
+		out, err := c.ec2conn.DescribeTransitGatewayVpcAttachmentsWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing transit gateway VPC attachments: %s", err)
+		}
+
+		for _, v := range out.TransitGatewayVpcAttachments {
+			if aws.StringValue(v.VpcOwnerId) != aws.StringValue(AccountId) {
+				continue
+			}
+
+			a := &TransitGatewayVPCAttachment{
+				TransitGatewayAttachmentID: v.TransitGatewayAttachmentId,
+				TransitGatewayID:           v.TransitGatewayId,
+				VPCID:                      v.VpcId,
+				SubnetIDs:                  v.SubnetIds,
+				Tags:                       &Tags{},
+			}
+			if v.Options != nil {
+				a.DNSSupport = v.Options.DnsSupport
+				a.IPv6Support = v.Options.Ipv6Support
+			}
+			a.Tags.setTags(v.Tags)
+
+			a.ResourceName = c.namer.Name(a.nameInfo())
+			res = append(res, a)
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return &res, nil
+}
+
+func (a *TransitGatewayVPCAttachments) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformList": makeTerraformList,
+	}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_ec2_transit_gateway_vpc_attachment" "{{ .ResourceName }}" {
+    transit_gateway_id = "{{ .TransitGatewayID }}"
+    vpc_id = "{{ .VPCID }}"
+    subnet_ids = [{{ .SubnetIDs | makeTerraformList }}]
+    {{- if .DNSSupport }}
+    dns_support = "{{ .DNSSupport }}"
+    {{- end }}
+    {{- if .IPv6Support }}
+    ipv6_support = "{{ .IPv6Support }}"
+    {{- end }}
+    {{- if .Tags }}
+
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, a)
+}
+
+//**************** Transit Gateway Route Table ****************
+
+type TransitGatewayRouteTable struct {
+	TransitGatewayRouteTableID *string
+	TransitGatewayID           *string
+	Tags                       *Tags
+
+	// The default association & propagation table of the transit gateway,
+	// created with it: only its routes are exported
+	Default bool
+	Routes  []*TransitGatewayRoute
+
+	ResourceName string
+}
+
+type TransitGatewayRoute struct {
+	TransitGatewayRouteTableID *string
+	DestinationCIDRBlock       *string
+	TransitGatewayAttachmentID *string
+	Blackhole                  bool
+
+	ResourceName string
+}
+
+type TransitGatewayRouteTables []*TransitGatewayRouteTable
+
+type TransitGatewayRoutes []*TransitGatewayRoute
+
+func (rtb *TransitGatewayRouteTable) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_ec2_transit_gateway_route_table",
+		ID:   aws.StringValue(rtb.TransitGatewayRouteTableID),
+		Tags: rtb.Tags.values(),
+	}
+}
+
+// nameInfo of the route, whose id is the one Terraform gives it
+func (r *TransitGatewayRoute) nameInfo(table string) NameInfo {
+	return NameInfo{
+		Type: "aws_ec2_transit_gateway_route",
+		ID:   aws.StringValue(r.TransitGatewayRouteTableID) + "_" + aws.StringValue(r.DestinationCIDRBlock),
+		Name: table + "-" + aws.StringValue(r.DestinationCIDRBlock),
+	}
+}
+
+func (rtbs *TransitGatewayRouteTables) addToGraph(g *Graph) {
+	for _, v := range *rtbs {
+		if v.Default {
+			continue
+		}
+
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "transit_gateway", "aws_ec2_transit_gateway", aws.StringValue(v.TransitGatewayID))
+	}
+}
+
+func (rtbs *TransitGatewayRouteTables) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *rtbs {
+		if !v.Default {
+			res = append(res, v.nameInfo())
+		}
+	}
+
+	return res
+}
+
+func (rtbs *TransitGatewayRouteTables) addMissingTags(tags map[string]string) {
+	for _, v := range *rtbs {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (r *TransitGatewayRoutes) addToGraph(g *Graph) {
+	for _, v := range *r {
+		n := g.addNode(v.nameInfo(""))
+		g.addEdge(n, "route_table", "aws_ec2_transit_gateway_route_table", aws.StringValue(v.TransitGatewayRouteTableID))
+		g.addEdge(n, "attachment", "aws_ec2_transit_gateway_vpc_attachment", aws.StringValue(v.TransitGatewayAttachmentID))
+	}
+}
+
+// Routes returns the static routes of the tables, the default ones included
+func (rtbs *TransitGatewayRouteTables) Routes() *TransitGatewayRoutes {
+	res := TransitGatewayRoutes{}
+	for _, v := range *rtbs {
+		res = append(res, v.Routes...)
+	}
+
+	return &res
+}
+
+func (c *AWSClient) GetTransitGatewayRouteTables() (*TransitGatewayRouteTables, error) {
+	return c.GetTransitGatewayRouteTablesWithContext(aws.BackgroundContext())
+}
+
+// GetTransitGatewayRouteTablesWithContext returns the route tables of
+// the transit gateways with their static routes
+func (c *AWSClient) GetTransitGatewayRouteTablesWithContext(ctx aws.Context) (*TransitGatewayRouteTables, error) {
+	res := TransitGatewayRouteTables{}
+	opt := &ec2.DescribeTransitGatewayRouteTablesInput{
+		Filters: stateFilter(ec2.TransitGatewayRouteTableStatePending, ec2.TransitGatewayRouteTableStateAvailable),
+	}
+	for {
+		out, err := c.ec2conn.DescribeTransitGatewayRouteTablesWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing transit gateway route tables: %s", err)
+		}
+
+		for _, v := range out.TransitGatewayRouteTables {
+			rtb := &TransitGatewayRouteTable{
+				TransitGatewayRouteTableID: v.TransitGatewayRouteTableId,
+				TransitGatewayID:           v.TransitGatewayId,
+				Default:                    aws.BoolValue(v.DefaultAssociationRouteTable) || aws.BoolValue(v.DefaultPropagationRouteTable),
+				Tags:                       &Tags{},
+			}
+			rtb.Tags.setTags(v.Tags)
+			res = append(res, rtb)
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	err := c.pool.run(ctx, len(res), func(ctx aws.Context, i int) error {
+		return c.getTransitGatewayRoutesWithContext(ctx, res[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range res {
+		v.ResourceName = c.namer.Name(v.nameInfo())
+		for _, r := range v.Routes {
+			r.ResourceName = c.namer.Name(r.nameInfo(v.ResourceName))
+		}
+	}
+
+	return &res, nil
+}
+
+// getTransitGatewayRoutesWithContext sets the static routes of the table.
+// SearchTransitGatewayRoutes has no pagination: when a search is truncated
+// the routes are searched again per attachment of the transit gateway,
+// the blackhole ones apart, and a search still truncated is an error.
+func (c *AWSClient) getTransitGatewayRoutesWithContext(ctx aws.Context, rtb *TransitGatewayRouteTable) error {
+	routes, truncated, err := c.searchTransitGatewayRoutesWithContext(ctx, rtb, nil)
+	if err != nil || !truncated {
+		rtb.Routes = routes
+		return err
+	}
+
+	attachments, err := c.getTransitGatewayAttachmentIDsWithContext(ctx, rtb.TransitGatewayID)
+	if err != nil {
+		return err
+	}
+
+	filters := [][]*ec2.Filter{stateFilter(ec2.TransitGatewayRouteStateBlackhole)}
+	for _, id := range attachments {
+		filters = append(filters, []*ec2.Filter{{
+			Name:   aws.String("attachment.transit-gateway-attachment-id"),
+			Values: []*string{id},
+		}})
+	}
+
+	rtb.Routes = nil
+	for _, f := range filters {
+		routes, truncated, err := c.searchTransitGatewayRoutesWithContext(ctx, rtb, f)
+		if err != nil {
+			return err
+		}
+		if truncated {
+			return fmt.Errorf("Error searching the routes of transit gateway route table %s: too many static routes for %s = %s",
+				aws.StringValue(rtb.TransitGatewayRouteTableID), aws.StringValue(f[0].Name), aws.StringValueSlice(f[0].Values))
+		}
+		rtb.Routes = append(rtb.Routes, routes...)
+	}
+
+	return nil
+}
+
+// searchTransitGatewayRoutesWithContext returns the static routes of the
+// table matching 'filters' and whether more routes are available
+func (c *AWSClient) searchTransitGatewayRoutesWithContext(ctx aws.Context, rtb *TransitGatewayRouteTable, filters []*ec2.Filter) ([]*TransitGatewayRoute, bool, error) {
+	out, err := c.ec2conn.SearchTransitGatewayRoutesWithContext(ctx, &ec2.SearchTransitGatewayRoutesInput{
+		TransitGatewayRouteTableId: rtb.TransitGatewayRouteTableID,
+		Filters: append([]*ec2.Filter{{
+			Name:   aws.String("type"),
+			Values: aws.StringSlice([]string{ec2.TransitGatewayRouteTypeStatic}),
+		}}, filters...),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("Error searching the routes of transit gateway route table %s: %s", aws.StringValue(rtb.TransitGatewayRouteTableID), err)
+	}
+
+	var res []*TransitGatewayRoute
+	for _, v := range out.Routes {
+		r := &TransitGatewayRoute{
+			TransitGatewayRouteTableID: rtb.TransitGatewayRouteTableID,
+			DestinationCIDRBlock:       v.DestinationCidrBlock,
+			Blackhole:                  aws.StringValue(v.State) == ec2.TransitGatewayRouteStateBlackhole,
+		}
+		for _, a := range v.TransitGatewayAttachments {
+			r.TransitGatewayAttachmentID = a.TransitGatewayAttachmentId
+		}
+		res = append(res, r)
+	}
+
+	return res, aws.BoolValue(out.AdditionalRoutesAvailable), nil
+}
+
+// getTransitGatewayAttachmentIDsWithContext returns the ids of the
+// attachments of a transit gateway, whatever their type
+func (c *AWSClient) getTransitGatewayAttachmentIDsWithContext(ctx aws.Context, tgwID *string) ([]*string, error) {
+	var res []*string
+	opt := &ec2.DescribeTransitGatewayAttachmentsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("transit-gateway-id"), Values: []*string{tgwID}}},
+	}
+	for {
+		out, err := c.ec2conn.DescribeTransitGatewayAttachmentsWithContext(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Error describing the attachments of transit gateway %s: %s", aws.StringValue(tgwID), err)
+		}
+
+		for _, v := range out.TransitGatewayAttachments {
+			res = append(res, v.TransitGatewayAttachmentId)
+		}
+
+		if out.NextToken == nil {
+			break
+		}
+		opt.NextToken = out.NextToken
+	}
+
+	return res, nil
+}
+
+func (rtbs *TransitGatewayRouteTables) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+		{{- if not .Default }}
+	resource "aws_ec2_transit_gateway_route_table" "{{ .ResourceName }}" {
+    transit_gateway_id = "{{ .TransitGatewayID }}"
+    {{- if .Tags }}
+
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, rtbs)
+}
+
+func (r *TransitGatewayRoutes) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_ec2_transit_gateway_route" "{{ .ResourceName }}" {
+    transit_gateway_route_table_id = "{{ .TransitGatewayRouteTableID }}"
+    destination_cidr_block = "{{ .DestinationCIDRBlock }}"
+    {{- if .Blackhole }}
+    blackhole = true
+    {{- else }}
+    transit_gateway_attachment_id = "{{ .TransitGatewayAttachmentID }}"
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, r)
+}
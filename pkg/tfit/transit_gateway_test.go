@@ -0,0 +1,178 @@
+package tfit
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestTransitGatewaysWriteHCL(t *testing.T) {
+	tgws := &TransitGateways{
+		{
+			TransitGatewayID:             aws.String("tgw-1"),
+			Description:                  aws.String("hub"),
+			AmazonSideASN:                aws.Int64(64512),
+			AutoAcceptSharedAttachments:  aws.String("disable"),
+			DefaultRouteTableAssociation: aws.String("enable"),
+			DefaultRouteTablePropagation: aws.String("enable"),
+			DNSSupport:                   aws.String("enable"),
+			VPNECMPSupport:               aws.String("enable"),
+			Tags:                         &Tags{"Name": aws.String("hub")},
+			ResourceName:                 "hub",
+		},
+		{TransitGatewayID: aws.String("tgw-2"), Tags: &Tags{}, ResourceName: "tgw-2"},
+	}
+
+	rs := mustRenderHCL(t, tgws)
+	if len(rs) != 2 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"description":                     "hub",
+		"amazon_side_asn":                 "64512",
+		"auto_accept_shared_attachments":  "disable",
+		"default_route_table_association": "enable",
+		"dns_support":                     "enable",
+		"vpn_ecmp_support":                "enable",
+		"tags.Name":                       "hub",
+	})
+	checkAttributes(t, rs[1], map[string]string{"description": "", "amazon_side_asn": ""})
+
+	attachments := &TransitGatewayVPCAttachments{{
+		TransitGatewayAttachmentID: aws.String("tgw-attach-1"),
+		TransitGatewayID:           aws.String("tgw-1"),
+		VPCID:                      aws.String("vpc-1"),
+		SubnetIDs:                  aws.StringSlice([]string{"subnet-1", "subnet-2"}),
+		DNSSupport:                 aws.String("enable"),
+		IPv6Support:                aws.String("disable"),
+		ResourceName:               "vpc-1",
+	}}
+	rs = mustRenderHCL(t, attachments)
+	if len(rs) != 1 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"transit_gateway_id": "tgw-1",
+		"vpc_id":             "vpc-1",
+		"subnet_ids":         "[subnet-1 subnet-2]",
+		"dns_support":        "enable",
+		"ipv6_support":       "disable",
+	})
+}
+
+func TestTransitGatewayRouteTablesWriteHCL(t *testing.T) {
+	rtbs := &TransitGatewayRouteTables{
+		{
+			TransitGatewayRouteTableID: aws.String("tgw-rtb-1"),
+			TransitGatewayID:           aws.String("tgw-1"),
+			Default:                    true,
+			Routes: []*TransitGatewayRoute{{
+				TransitGatewayRouteTableID: aws.String("tgw-rtb-1"),
+				DestinationCIDRBlock:       aws.String("10.0.0.0/8"),
+				TransitGatewayAttachmentID: aws.String("tgw-attach-1"),
+				ResourceName:               "default-10",
+			}},
+			ResourceName: "default",
+		},
+		{
+			TransitGatewayRouteTableID: aws.String("tgw-rtb-2"),
+			TransitGatewayID:           aws.String("tgw-1"),
+			Tags:                       &Tags{"Name": aws.String("isolated")},
+			Routes: []*TransitGatewayRoute{{
+				TransitGatewayRouteTableID: aws.String("tgw-rtb-2"),
+				DestinationCIDRBlock:       aws.String("0.0.0.0/0"),
+				Blackhole:                  true,
+				ResourceName:               "isolated-0",
+			}},
+			ResourceName: "isolated",
+		},
+	}
+
+	rs := mustRenderHCL(t, rtbs)
+	if got, want := addresses(rs), []string{"aws_ec2_transit_gateway_route_table.isolated"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	checkAttributes(t, rs[0], map[string]string{"transit_gateway_id": "tgw-1", "tags.Name": "isolated"})
+
+	rs = mustRenderHCL(t, rtbs.Routes())
+	if got, want := addresses(rs), []string{"aws_ec2_transit_gateway_route.default-10", "aws_ec2_transit_gateway_route.isolated-0"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"transit_gateway_route_table_id": "tgw-rtb-1",
+		"destination_cidr_block":         "10.0.0.0/8",
+		"transit_gateway_attachment_id":  "tgw-attach-1",
+		"blackhole":                      "",
+	})
+	checkAttributes(t, rs[1], map[string]string{
+		"blackhole":                     "true",
+		"transit_gateway_attachment_id": "",
+	})
+
+	if id := (*rtbs)[0].Routes[0].nameInfo("").ID; id != "tgw-rtb-1_10.0.0.0/8" {
+		t.Errorf("got route id %s", id)
+	}
+}
+
+// tgwRoutesHandler serves the static routes of tgw-rtb-1 keyed by the
+// filter added to the type one, "" being the unfiltered search
+func tgwRoutesHandler(routes map[string]string, truncated map[string]bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.Form.Get("Action") {
+		case "DescribeTransitGatewayAttachments":
+			fmt.Fprint(w, `<DescribeTransitGatewayAttachmentsResponse><transitGatewayAttachments>
+<item><transitGatewayAttachmentId>tgw-attach-1</transitGatewayAttachmentId></item>
+<item><transitGatewayAttachmentId>tgw-attach-2</transitGatewayAttachmentId></item>
+</transitGatewayAttachments></DescribeTransitGatewayAttachmentsResponse>`)
+		case "SearchTransitGatewayRoutes":
+			key := r.Form.Get("Filter.2.Value.1")
+			fmt.Fprintf(w, `<SearchTransitGatewayRoutesResponse><routeSet>%s</routeSet><additionalRoutesAvailable>%t</additionalRoutesAvailable></SearchTransitGatewayRoutesResponse>`,
+				routes[key], truncated[key])
+		default:
+			http.Error(w, r.Form.Get("Action"), http.StatusBadRequest)
+		}
+	}
+}
+
+func tgwRouteXML(cidr, attachment string) string {
+	if len(attachment) == 0 {
+		return fmt.Sprintf(`<item><destinationCidrBlock>%s</destinationCidrBlock><state>blackhole</state></item>`, cidr)
+	}
+	return fmt.Sprintf(`<item><destinationCidrBlock>%s</destinationCidrBlock><state>active</state>
+<transitGatewayAttachments><item><transitGatewayAttachmentId>%s</transitGatewayAttachmentId></item></transitGatewayAttachments></item>`, cidr, attachment)
+}
+
+func TestTransitGatewayRoutesTruncated(t *testing.T) {
+	routes := map[string]string{
+		"":             tgwRouteXML("10.0.0.0/16", "tgw-attach-1"),
+		"blackhole":    tgwRouteXML("0.0.0.0/0", ""),
+		"tgw-attach-1": tgwRouteXML("10.0.0.0/16", "tgw-attach-1") + tgwRouteXML("10.1.0.0/16", "tgw-attach-1"),
+		"tgw-attach-2": tgwRouteXML("10.2.0.0/16", "tgw-attach-2"),
+	}
+	c := newTestClient(t, tgwRoutesHandler(routes, map[string]bool{"": true}))
+
+	rtb := &TransitGatewayRouteTable{TransitGatewayRouteTableID: aws.String("tgw-rtb-1"), TransitGatewayID: aws.String("tgw-1")}
+	if err := c.getTransitGatewayRoutesWithContext(aws.BackgroundContext(), rtb); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, r := range rtb.Routes {
+		got = append(got, fmt.Sprintf("%s>%s", aws.StringValue(r.DestinationCIDRBlock), aws.StringValue(r.TransitGatewayAttachmentID)))
+	}
+	want := []string{"0.0.0.0/0>", "10.0.0.0/16>tgw-attach-1", "10.1.0.0/16>tgw-attach-1", "10.2.0.0/16>tgw-attach-2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	c = newTestClient(t, tgwRoutesHandler(routes, map[string]bool{"": true, "tgw-attach-2": true}))
+	err := c.getTransitGatewayRoutesWithContext(aws.BackgroundContext(), rtb)
+	if err == nil || !strings.Contains(err.Error(), "tgw-attach-2") {
+		t.Errorf("got %v, want an error for tgw-attach-2", err)
+	}
+}
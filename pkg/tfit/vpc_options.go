@@ -0,0 +1,278 @@
+package tfit
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+//**************** VPC IPv4 CIDR Block Association ****************
+
+// VPCIPv4CIDRBlockAssociation is a secondary IPv4 CIDR block of a VPC
+type VPCIPv4CIDRBlockAssociation struct {
+	AssociationID *string
+	VPCID         *string
+	CIDRBlock     *string
+
+	ResourceName string
+}
+
+type VPCIPv4CIDRBlockAssociations []*VPCIPv4CIDRBlockAssociation
+
+// setCIDRBlockAssociations keeps the secondary CIDR blocks, the
+// primary one is the cidr_block of the VPC
+func (vpc *VPC) setCIDRBlockAssociations(src []*ec2.VpcCidrBlockAssociation) {
+	for _, v := range src {
+		if aws.StringValue(v.CidrBlock) == aws.StringValue(vpc.CIDRBlock) || v.CidrBlockState == nil {
+			continue
+		}
+		state := aws.StringValue(v.CidrBlockState.State)
+		if state != ec2.VpcCidrBlockStateCodeAssociated && state != ec2.VpcCidrBlockStateCodeAssociating {
+			continue
+		}
+
+		vpc.CIDRBlockAssociations = append(vpc.CIDRBlockAssociations, &VPCIPv4CIDRBlockAssociation{
+			AssociationID: v.AssociationId,
+			VPCID:         vpc.VPCId,
+			CIDRBlock:     v.CidrBlock,
+		})
+	}
+}
+
+func (a *VPCIPv4CIDRBlockAssociation) nameInfo(vpc string) NameInfo {
+	return NameInfo{
+		Type: "aws_vpc_ipv4_cidr_block_association",
+		ID:   aws.StringValue(a.AssociationID),
+		Name: vpc + "-" + aws.StringValue(a.CIDRBlock),
+	}
+}
+
+func (a *VPCIPv4CIDRBlockAssociations) addToGraph(g *Graph) {
+	for _, v := range *a {
+		n := g.addNode(v.nameInfo(""))
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCID))
+	}
+}
+
+// CIDRBlockAssociations returns the secondary IPv4 CIDR blocks of the VPCs
+func (vpcs *VPCs) CIDRBlockAssociations() *VPCIPv4CIDRBlockAssociations {
+	res := VPCIPv4CIDRBlockAssociations{}
+	for _, v := range *vpcs {
+		res = append(res, v.CIDRBlockAssociations...)
+	}
+
+	return &res
+}
+
+func (a *VPCIPv4CIDRBlockAssociations) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_vpc_ipv4_cidr_block_association" "{{ .ResourceName }}" {
+    vpc_id = "{{ .VPCID }}"
+    cidr_block = "{{ .CIDRBlock }}"
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, a)
+}
+
+//**************** DHCP Options ****************
+
+type DHCPOptions struct {
+	DHCPOptionsID      *string
+	DomainName         *string
+	DomainNameServers  []*string
+	NTPServers         []*string
+	NetBIOSNameServers []*string
+	NetBIOSNodeType    *string
+	Tags               *Tags
+
+	ResourceName string
+}
+
+// VPCDHCPOptionsAssociation associates a DHCP options set with a VPC
+type VPCDHCPOptionsAssociation struct {
+	VPCID         *string
+	DHCPOptionsID *string
+
+	ResourceName string
+}
+
+type DHCPOptionsSets []*DHCPOptions
+
+type VPCDHCPOptionsAssociations []*VPCDHCPOptionsAssociation
+
+func (o *DHCPOptions) set(src *ec2.DhcpOptions) {
+	o.DHCPOptionsID = src.DhcpOptionsId
+	o.Tags = &Tags{}
+	o.Tags.setTags(src.Tags)
+
+	for _, v := range src.DhcpConfigurations {
+		var values []*string
+		for _, value := range v.Values {
+			values = append(values, value.Value)
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		switch aws.StringValue(v.Key) {
+		case "domain-name":
+			o.DomainName = values[0]
+		case "domain-name-servers":
+			o.DomainNameServers = values
+		case "ntp-servers":
+			o.NTPServers = values
+		case "netbios-name-servers":
+			o.NetBIOSNameServers = values
+		case "netbios-node-type":
+			o.NetBIOSNodeType = values[0]
+		}
+	}
+}
+
+func (o *DHCPOptions) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_vpc_dhcp_options",
+		ID:   aws.StringValue(o.DHCPOptionsID),
+		Name: aws.StringValue(o.DomainName),
+		Tags: o.Tags.values(),
+	}
+}
+
+// nameInfo of the association, whose id is the one Terraform gives it
+func (a *VPCDHCPOptionsAssociation) nameInfo(vpc string) NameInfo {
+	return NameInfo{
+		Type: "aws_vpc_dhcp_options_association",
+		ID:   aws.StringValue(a.DHCPOptionsID) + "-" + aws.StringValue(a.VPCID),
+		Name: vpc,
+	}
+}
+
+func (o *DHCPOptionsSets) addToGraph(g *Graph) {
+	for _, v := range *o {
+		g.addNode(v.nameInfo())
+	}
+}
+
+func (o *DHCPOptionsSets) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *o {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (o *DHCPOptionsSets) addMissingTags(tags map[string]string) {
+	for _, v := range *o {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (a *VPCDHCPOptionsAssociations) addToGraph(g *Graph) {
+	for _, v := range *a {
+		n := g.addNode(v.nameInfo(""))
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCID))
+		g.addEdge(n, "dhcp_options", "aws_vpc_dhcp_options", aws.StringValue(v.DHCPOptionsID))
+	}
+}
+
+// DHCPOptionsAssociations returns the associations of the VPCs with
+// their DHCP options sets, but the VPCs without options
+func (vpcs *VPCs) DHCPOptionsAssociations() *VPCDHCPOptionsAssociations {
+	res := VPCDHCPOptionsAssociations{}
+	for _, v := range *vpcs {
+		if v.DHCPOptionsAssociation != nil {
+			res = append(res, v.DHCPOptionsAssociation)
+		}
+	}
+
+	return &res
+}
+
+func (c *AWSClient) GetDHCPOptionsSets() (*DHCPOptionsSets, error) {
+	return c.GetDHCPOptionsSetsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetDHCPOptionsSetsWithContext(ctx aws.Context) (*DHCPOptionsSets, error) {
+	out, err := c.ec2conn.DescribeDhcpOptionsWithContext(ctx, &ec2.DescribeDhcpOptionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("Error describing DHCP options: %s", err)
+	}
+
+	res := DHCPOptionsSets{}
+	for _, v := range out.DhcpOptions {
+		o := &DHCPOptions{}
+		o.set(v)
+		o.ResourceName = c.namer.Name(o.nameInfo())
+		res = append(res, o)
+	}
+
+	return &res, nil
+}
+
+func (o *DHCPOptionsSets) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{
+		"makeTerraformList": makeTerraformList,
+	}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_vpc_dhcp_options" "{{ .ResourceName }}" {
+    {{- if .DomainName }}
+    domain_name = "{{ .DomainName }}"
+    {{- end }}
+    {{- if .DomainNameServers }}
+    domain_name_servers = [{{ .DomainNameServers | makeTerraformList }}]
+    {{- end }}
+    {{- if .NTPServers }}
+    ntp_servers = [{{ .NTPServers | makeTerraformList }}]
+    {{- end }}
+    {{- if .NetBIOSNameServers }}
+    netbios_name_servers = [{{ .NetBIOSNameServers | makeTerraformList }}]
+    {{- end }}
+    {{- if .NetBIOSNodeType }}
+    netbios_node_type = "{{ .NetBIOSNodeType }}"
+    {{- end }}
+    {{- if .Tags }}
+
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, o)
+}
+
+func (a *VPCDHCPOptionsAssociations) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_vpc_dhcp_options_association" "{{ .ResourceName }}" {
+    vpc_id = "{{ .VPCID }}"
+    dhcp_options_id = "{{ .DHCPOptionsID }}"
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, a)
+}
@@ -0,0 +1,81 @@
+package tfit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestVPCCIDRBlockAssociations(t *testing.T) {
+	block := func(id, cidr, state string) *ec2.VpcCidrBlockAssociation {
+		return &ec2.VpcCidrBlockAssociation{
+			AssociationId:  aws.String(id),
+			CidrBlock:      aws.String(cidr),
+			CidrBlockState: &ec2.VpcCidrBlockState{State: aws.String(state)},
+		}
+	}
+
+	vpc := &VPC{VPCId: aws.String("vpc-1"), CIDRBlock: aws.String("10.0.0.0/16"), ResourceName: "main"}
+	vpc.setCIDRBlockAssociations([]*ec2.VpcCidrBlockAssociation{
+		block("vpc-cidr-assoc-0", "10.0.0.0/16", ec2.VpcCidrBlockStateCodeAssociated),
+		block("vpc-cidr-assoc-1", "10.1.0.0/16", ec2.VpcCidrBlockStateCodeAssociated),
+		block("vpc-cidr-assoc-2", "10.2.0.0/16", ec2.VpcCidrBlockStateCodeDisassociated),
+	})
+	for _, a := range vpc.CIDRBlockAssociations {
+		a.ResourceName = a.nameInfo(vpc.ResourceName).Name
+	}
+
+	rs := mustRenderHCL(t, (&VPCs{vpc}).CIDRBlockAssociations())
+	if got, want := addresses(rs), []string{"aws_vpc_ipv4_cidr_block_association.main-10.1.0.0/16"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	checkAttributes(t, rs[0], map[string]string{"vpc_id": "vpc-1", "cidr_block": "10.1.0.0/16"})
+}
+
+func TestDHCPOptionsWriteHCL(t *testing.T) {
+	config := func(key string, values ...string) *ec2.DhcpConfiguration {
+		c := &ec2.DhcpConfiguration{Key: aws.String(key)}
+		for _, v := range values {
+			c.Values = append(c.Values, &ec2.AttributeValue{Value: aws.String(v)})
+		}
+		return c
+	}
+
+	o := &DHCPOptions{}
+	o.set(&ec2.DhcpOptions{
+		DhcpOptionsId: aws.String("dopt-1"),
+		DhcpConfigurations: []*ec2.DhcpConfiguration{
+			config("domain-name", "example.internal"),
+			config("domain-name-servers", "10.0.0.2", "AmazonProvidedDNS"),
+			config("ntp-servers"),
+			config("netbios-node-type", "2"),
+		},
+		Tags: []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("internal")}},
+	})
+	o.ResourceName = "internal"
+
+	rs := mustRenderHCL(t, &DHCPOptionsSets{o})
+	if len(rs) != 1 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"domain_name":          "example.internal",
+		"domain_name_servers":  "[10.0.0.2 AmazonProvidedDNS]",
+		"ntp_servers":          "",
+		"netbios_name_servers": "",
+		"netbios_node_type":    "2",
+		"tags.Name":            "internal",
+	})
+
+	vpcs := &VPCs{
+		{VPCId: aws.String("vpc-1"), DHCPOptionsAssociation: &VPCDHCPOptionsAssociation{VPCID: aws.String("vpc-1"), DHCPOptionsID: aws.String("dopt-1"), ResourceName: "main"}},
+		{VPCId: aws.String("vpc-2")},
+	}
+	rs = mustRenderHCL(t, vpcs.DHCPOptionsAssociations())
+	if got, want := addresses(rs), []string{"aws_vpc_dhcp_options_association.main"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	checkAttributes(t, rs[0], map[string]string{"vpc_id": "vpc-1", "dhcp_options_id": "dopt-1"})
+}
@@ -0,0 +1,467 @@
+package tfit
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+//**************** VPN Gateway ****************
+
+type VPNGateway struct {
+	VPNGatewayID     *string
+	AvailabilityZone *string
+	AmazonSideASN    *int64
+	Tags             *Tags
+
+	Attachments []*VPNGatewayAttachment
+
+	ResourceName string
+}
+
+// VPNGatewayAttachment attaches a VPN gateway to a VPC
+type VPNGatewayAttachment struct {
+	VPNGatewayID *string
+	VPCID        *string
+
+	ResourceName string
+}
+
+type VPNGateways []*VPNGateway
+
+type VPNGatewayAttachments []*VPNGatewayAttachment
+
+func (vgw *VPNGateway) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_vpn_gateway",
+		ID:   aws.StringValue(vgw.VPNGatewayID),
+		Tags: vgw.Tags.values(),
+	}
+}
+
+// id is the one Terraform gives to the attachment: a hash of the VPC & the gateway
+func (a *VPNGatewayAttachment) id() string {
+	key := aws.StringValue(a.VPCID) + "-" + aws.StringValue(a.VPNGatewayID)
+	return fmt.Sprintf("vpn-attachment-%x", crc32.ChecksumIEEE([]byte(key)))
+}
+
+func (a *VPNGatewayAttachment) nameInfo(gateway string) NameInfo {
+	return NameInfo{
+		Type: "aws_vpn_gateway_attachment",
+		ID:   a.id(),
+		Name: gateway + "-" + aws.StringValue(a.VPCID),
+	}
+}
+
+func (vgws *VPNGateways) addToGraph(g *Graph) {
+	for _, v := range *vgws {
+		g.addNode(v.nameInfo())
+	}
+}
+
+func (vgws *VPNGateways) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *vgws {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (vgws *VPNGateways) addMissingTags(tags map[string]string) {
+	for _, v := range *vgws {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (a *VPNGatewayAttachments) addToGraph(g *Graph) {
+	for _, v := range *a {
+		n := g.addNode(v.nameInfo(""))
+		g.addEdge(n, "vpn_gateway", "aws_vpn_gateway", aws.StringValue(v.VPNGatewayID))
+		g.addEdge(n, "vpc", "aws_vpc", aws.StringValue(v.VPCID))
+	}
+}
+
+// Attachments returns the attachments of the gateways to the VPCs
+func (vgws *VPNGateways) Attachments() *VPNGatewayAttachments {
+	res := VPNGatewayAttachments{}
+	for _, v := range *vgws {
+		res = append(res, v.Attachments...)
+	}
+
+	return &res
+}
+
+func (c *AWSClient) GetVPNGateways() (*VPNGateways, error) {
+	return c.GetVPNGatewaysWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetVPNGatewaysWithContext(ctx aws.Context) (*VPNGateways, error) {
+	out, err := c.ec2conn.DescribeVpnGatewaysWithContext(ctx, &ec2.DescribeVpnGatewaysInput{
+		Filters: stateFilter(ec2.VpnStatePending, ec2.VpnStateAvailable),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error describing VPN gateways: %s", err)
+	}
+
+	res := VPNGateways{}
+	for _, v := range out.VpnGateways {
+		vgw := &VPNGateway{
+			VPNGatewayID:     v.VpnGatewayId,
+			AvailabilityZone: v.AvailabilityZone,
+			AmazonSideASN:    v.AmazonSideAsn,
+			Tags:             &Tags{},
+		}
+		vgw.Tags.setTags(v.Tags)
+		vgw.ResourceName = c.namer.Name(vgw.nameInfo())
+
+		for _, a := range v.VpcAttachments {
+			state := aws.StringValue(a.State)
+			if state != ec2.AttachmentStatusAttached && state != ec2.AttachmentStatusAttaching {
+				continue
+			}
+
+			attachment := &VPNGatewayAttachment{VPNGatewayID: v.VpnGatewayId, VPCID: a.VpcId}
+			attachment.ResourceName = c.namer.Name(attachment.nameInfo(vgw.ResourceName))
+			vgw.Attachments = append(vgw.Attachments, attachment)
+		}
+
+		res = append(res, vgw)
+	}
+
+	return &res, nil
+}
+
+func (vgws *VPNGateways) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_vpn_gateway" "{{ .ResourceName }}" {
+    {{- if .AvailabilityZone }}
+    availability_zone = "{{ .AvailabilityZone }}"
+    {{- end }}
+    {{- if .AmazonSideASN }}
+    amazon_side_asn = {{ .AmazonSideASN }}
+    {{- end }}
+    {{- if .Tags }}
+
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, vgws)
+}
+
+func (a *VPNGatewayAttachments) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_vpn_gateway_attachment" "{{ .ResourceName }}" {
+    vpn_gateway_id = "{{ .VPNGatewayID }}"
+    vpc_id = "{{ .VPCID }}"
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, a)
+}
+
+//**************** Customer Gateway ****************
+
+type CustomerGateway struct {
+	CustomerGatewayID *string
+	BGPASN            *string
+	IPAddress         *string
+	Type              *string
+	Tags              *Tags
+
+	ResourceName string
+}
+
+type CustomerGateways []*CustomerGateway
+
+func (cgw *CustomerGateway) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_customer_gateway",
+		ID:   aws.StringValue(cgw.CustomerGatewayID),
+		Name: aws.StringValue(cgw.IPAddress),
+		Tags: cgw.Tags.values(),
+	}
+}
+
+func (cgws *CustomerGateways) addToGraph(g *Graph) {
+	for _, v := range *cgws {
+		g.addNode(v.nameInfo())
+	}
+}
+
+func (cgws *CustomerGateways) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *cgws {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (cgws *CustomerGateways) addMissingTags(tags map[string]string) {
+	for _, v := range *cgws {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (c *AWSClient) GetCustomerGateways() (*CustomerGateways, error) {
+	return c.GetCustomerGatewaysWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetCustomerGatewaysWithContext(ctx aws.Context) (*CustomerGateways, error) {
+	out, err := c.ec2conn.DescribeCustomerGatewaysWithContext(ctx, &ec2.DescribeCustomerGatewaysInput{
+		Filters: stateFilter(ec2.VpnStatePending, ec2.VpnStateAvailable),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error describing customer gateways: %s", err)
+	}
+
+	res := CustomerGateways{}
+	for _, v := range out.CustomerGateways {
+		cgw := &CustomerGateway{
+			CustomerGatewayID: v.CustomerGatewayId,
+			BGPASN:            v.BgpAsn,
+			IPAddress:         v.IpAddress,
+			Type:              v.Type,
+			Tags:              &Tags{},
+		}
+		cgw.Tags.setTags(v.Tags)
+
+		cgw.ResourceName = c.namer.Name(cgw.nameInfo())
+		res = append(res, cgw)
+	}
+
+	return &res, nil
+}
+
+func (cgws *CustomerGateways) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_customer_gateway" "{{ .ResourceName }}" {
+    bgp_asn = {{ .BGPASN }}
+    ip_address = "{{ .IPAddress }}"
+    type = "{{ .Type }}"
+    {{- if .Tags }}
+
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, cgws)
+}
+
+//**************** VPN Connection ****************
+
+// VPNConnection leaves out the tunnel options: their pre-shared keys are secrets
+type VPNConnection struct {
+	VPNConnectionID   *string
+	CustomerGatewayID *string
+	VPNGatewayID      *string
+	TransitGatewayID  *string
+	Type              *string
+	StaticRoutesOnly  *bool
+	Tags              *Tags
+
+	Routes []*VPNConnectionRoute
+
+	ResourceName string
+}
+
+type VPNConnectionRoute struct {
+	VPNConnectionID      *string
+	DestinationCIDRBlock *string
+
+	ResourceName string
+}
+
+type VPNConnections []*VPNConnection
+
+type VPNConnectionRoutes []*VPNConnectionRoute
+
+func (vpn *VPNConnection) nameInfo() NameInfo {
+	return NameInfo{
+		Type: "aws_vpn_connection",
+		ID:   aws.StringValue(vpn.VPNConnectionID),
+		Tags: vpn.Tags.values(),
+	}
+}
+
+func (r *VPNConnectionRoute) nameInfo(connection string) NameInfo {
+	return NameInfo{
+		Type: "aws_vpn_connection_route",
+		ID:   aws.StringValue(r.DestinationCIDRBlock) + ":" + aws.StringValue(r.VPNConnectionID),
+		Name: connection + "-" + aws.StringValue(r.DestinationCIDRBlock),
+	}
+}
+
+func (vpns *VPNConnections) addToGraph(g *Graph) {
+	for _, v := range *vpns {
+		n := g.addNode(v.nameInfo())
+		g.addEdge(n, "customer_gateway", "aws_customer_gateway", aws.StringValue(v.CustomerGatewayID))
+		g.addEdge(n, "vpn_gateway", "aws_vpn_gateway", aws.StringValue(v.VPNGatewayID))
+		g.addEdge(n, "transit_gateway", "aws_ec2_transit_gateway", aws.StringValue(v.TransitGatewayID))
+	}
+}
+
+func (vpns *VPNConnections) taggedResources() []NameInfo {
+	var res []NameInfo
+	for _, v := range *vpns {
+		res = append(res, v.nameInfo())
+	}
+
+	return res
+}
+
+func (vpns *VPNConnections) addMissingTags(tags map[string]string) {
+	for _, v := range *vpns {
+		if v.Tags == nil {
+			v.Tags = &Tags{}
+		}
+		v.Tags.addMissing(tags)
+	}
+}
+
+func (r *VPNConnectionRoutes) addToGraph(g *Graph) {
+	for _, v := range *r {
+		n := g.addNode(v.nameInfo(""))
+		g.addEdge(n, "vpn_connection", "aws_vpn_connection", aws.StringValue(v.VPNConnectionID))
+	}
+}
+
+// Routes returns the static routes of the connections
+func (vpns *VPNConnections) Routes() *VPNConnectionRoutes {
+	res := VPNConnectionRoutes{}
+	for _, v := range *vpns {
+		res = append(res, v.Routes...)
+	}
+
+	return &res
+}
+
+func (c *AWSClient) GetVPNConnections() (*VPNConnections, error) {
+	return c.GetVPNConnectionsWithContext(aws.BackgroundContext())
+}
+
+func (c *AWSClient) GetVPNConnectionsWithContext(ctx aws.Context) (*VPNConnections, error) {
+	out, err := c.ec2conn.DescribeVpnConnectionsWithContext(ctx, &ec2.DescribeVpnConnectionsInput{
+		Filters: stateFilter(ec2.VpnStatePending, ec2.VpnStateAvailable),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error describing VPN connections: %s", err)
+	}
+
+	res := VPNConnections{}
+	for _, v := range out.VpnConnections {
+		vpn := &VPNConnection{
+			VPNConnectionID:   v.VpnConnectionId,
+			CustomerGatewayID: v.CustomerGatewayId,
+			VPNGatewayID:      v.VpnGatewayId,
+			TransitGatewayID:  v.TransitGatewayId,
+			Type:              v.Type,
+			Tags:              &Tags{},
+		}
+		if v.Options != nil {
+			vpn.StaticRoutesOnly = v.Options.StaticRoutesOnly
+		}
+		vpn.Tags.setTags(v.Tags)
+		vpn.ResourceName = c.namer.Name(vpn.nameInfo())
+
+		for _, r := range v.Routes {
+			if aws.StringValue(r.Source) != ec2.VpnStaticRouteSourceStatic || aws.StringValue(r.State) == ec2.VpnStateDeleted {
+				continue
+			}
+
+			route := &VPNConnectionRoute{VPNConnectionID: v.VpnConnectionId, DestinationCIDRBlock: r.DestinationCidrBlock}
+			route.ResourceName = c.namer.Name(route.nameInfo(vpn.ResourceName))
+			vpn.Routes = append(vpn.Routes, route)
+		}
+
+		res = append(res, vpn)
+	}
+
+	return &res, nil
+}
+
+func (vpns *VPNConnections) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_vpn_connection" "{{ .ResourceName }}" {
+    customer_gateway_id = "{{ .CustomerGatewayID }}"
+    {{- if .VPNGatewayID }}
+    vpn_gateway_id = "{{ .VPNGatewayID }}"
+    {{- end }}
+    {{- if .TransitGatewayID }}
+    transit_gateway_id = "{{ .TransitGatewayID }}"
+    {{- end }}
+    type = "{{ .Type }}"
+    {{- if .StaticRoutesOnly }}
+    static_routes_only = {{ .StaticRoutesOnly }}
+    {{- end }}
+    {{- if .Tags }}
+
+    tags {
+      {{- range $k, $v := .Tags }}
+      "{{ $k }}" = "{{ $v }}"
+      {{- end }}
+    }
+    {{- end }}
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, vpns)
+}
+
+func (r *VPNConnectionRoutes) WriteHCL(w io.Writer) error {
+	funcMap := template.FuncMap{}
+
+	tmpl := `
+	{{ if . }}
+		{{- range . }}
+	resource "aws_vpn_connection_route" "{{ .ResourceName }}" {
+    vpn_connection_id = "{{ .VPNConnectionID }}"
+    destination_cidr_block = "{{ .DestinationCIDRBlock }}"
+	}
+		{{- end }}
+	{{- end }}
+	`
+	return renderHCL(w, tmpl, funcMap, r)
+}
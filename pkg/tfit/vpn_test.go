@@ -0,0 +1,104 @@
+package tfit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestVPNGatewaysWriteHCL(t *testing.T) {
+	attachment := &VPNGatewayAttachment{VPNGatewayID: aws.String("vgw-1"), VPCID: aws.String("vpc-1"), ResourceName: "office-vpc-1"}
+	vgws := &VPNGateways{
+		{
+			VPNGatewayID:  aws.String("vgw-1"),
+			AmazonSideASN: aws.Int64(64512),
+			Tags:          &Tags{"Name": aws.String("office")},
+			Attachments:   []*VPNGatewayAttachment{attachment},
+			ResourceName:  "office",
+		},
+		{VPNGatewayID: aws.String("vgw-2"), AvailabilityZone: aws.String("eu-west-1a"), ResourceName: "vgw-2"},
+	}
+
+	rs := mustRenderHCL(t, vgws)
+	if len(rs) != 2 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{"amazon_side_asn": "64512", "availability_zone": "", "tags.Name": "office"})
+	checkAttributes(t, rs[1], map[string]string{"availability_zone": "eu-west-1a", "amazon_side_asn": ""})
+
+	rs = mustRenderHCL(t, vgws.Attachments())
+	if got, want := addresses(rs), []string{"aws_vpn_gateway_attachment.office-vpc-1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	checkAttributes(t, rs[0], map[string]string{"vpn_gateway_id": "vgw-1", "vpc_id": "vpc-1"})
+
+	if id := attachment.id(); !strings.HasPrefix(id, "vpn-attachment-") || id != attachment.id() {
+		t.Errorf("got id %s", id)
+	}
+}
+
+func TestCustomerGatewaysWriteHCL(t *testing.T) {
+	cgws := &CustomerGateways{{
+		CustomerGatewayID: aws.String("cgw-1"),
+		BGPASN:            aws.String("65000"),
+		IPAddress:         aws.String("203.0.113.10"),
+		Type:              aws.String("ipsec.1"),
+		Tags:              &Tags{"Name": aws.String("office")},
+		ResourceName:      "office",
+	}}
+
+	rs := mustRenderHCL(t, cgws)
+	if len(rs) != 1 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{"bgp_asn": "65000", "ip_address": "203.0.113.10", "type": "ipsec.1", "tags.Name": "office"})
+}
+
+func TestVPNConnectionsWriteHCL(t *testing.T) {
+	vpns := &VPNConnections{
+		{
+			VPNConnectionID:   aws.String("vpn-1"),
+			CustomerGatewayID: aws.String("cgw-1"),
+			VPNGatewayID:      aws.String("vgw-1"),
+			Type:              aws.String("ipsec.1"),
+			StaticRoutesOnly:  aws.Bool(true),
+			Routes: []*VPNConnectionRoute{{
+				VPNConnectionID:      aws.String("vpn-1"),
+				DestinationCIDRBlock: aws.String("192.168.0.0/16"),
+				ResourceName:         "office-192",
+			}},
+			ResourceName: "office",
+		},
+		{
+			VPNConnectionID:   aws.String("vpn-2"),
+			CustomerGatewayID: aws.String("cgw-1"),
+			TransitGatewayID:  aws.String("tgw-1"),
+			Type:              aws.String("ipsec.1"),
+			ResourceName:      "hub",
+		},
+	}
+
+	rs := mustRenderHCL(t, vpns)
+	if len(rs) != 2 {
+		t.Fatalf("got %v", addresses(rs))
+	}
+	checkAttributes(t, rs[0], map[string]string{
+		"customer_gateway_id": "cgw-1",
+		"vpn_gateway_id":      "vgw-1",
+		"transit_gateway_id":  "",
+		"static_routes_only":  "true",
+	})
+	checkAttributes(t, rs[1], map[string]string{
+		"vpn_gateway_id":     "",
+		"transit_gateway_id": "tgw-1",
+		"static_routes_only": "",
+	})
+
+	rs = mustRenderHCL(t, vpns.Routes())
+	if got, want := addresses(rs), []string{"aws_vpn_connection_route.office-192"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	checkAttributes(t, rs[0], map[string]string{"vpn_connection_id": "vpn-1", "destination_cidr_block": "192.168.0.0/16"})
+}